@@ -2,6 +2,7 @@ package bitset
 
 import (
 	"bytes"
+	"iter"
 	"math/bits"
 	"strconv"
 
@@ -80,6 +81,10 @@ func (b *BitSet) Flip(bitIndex uint) {
 func (b *BitSet) PrevSetBit(bitIndex uint) int {
 	var word uint64
 
+	if b.wordInUse == 0 {
+		return -1
+	}
+
 	wordIndex := wordIndex(bitIndex)
 	if wordIndex >= b.wordInUse {
 		wordIndex = b.wordInUse - 1
@@ -109,6 +114,10 @@ func (b *BitSet) PrevSetBit(bitIndex uint) int {
 func (b *BitSet) PrevClearBit(bitIndex uint) int {
 	var word uint64
 
+	if b.wordInUse == 0 {
+		return -1
+	}
+
 	wordIndex := wordIndex(bitIndex)
 	if wordIndex >= b.wordInUse {
 		wordIndex = b.wordInUse - 1
@@ -184,6 +193,17 @@ func (b *BitSet) NextClearBit(bitIndex uint) int {
 	}
 }
 
+// Bits 从小到大遍历所有设置为1的位置
+func (b *BitSet) Bits() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i := b.NextSetBit(0); i != -1; i = b.NextSetBit(uint(i + 1)) {
+			if !yield(uint(i)) {
+				return
+			}
+		}
+	}
+}
+
 // And 与
 func (b *BitSet) And(s *BitSet) {
 	for b.wordInUse > s.wordInUse {
@@ -203,7 +223,8 @@ func (b *BitSet) Or(s *BitSet) {
 		b.wordInUse = s.wordInUse
 	}
 
-	for i := uint(0); i < b.wordInUse; i++ {
+	// s中超过s.wordInUse的word都为0
+	for i := uint(0); i < s.wordInUse; i++ {
 		b.words[i] |= s.words[i]
 	}
 }
@@ -215,7 +236,8 @@ func (b *BitSet) Xor(s *BitSet) {
 		b.wordInUse = s.wordInUse
 	}
 
-	for i := uint(0); i < b.wordInUse; i++ {
+	// s中超过s.wordInUse的word都为0
+	for i := uint(0); i < s.wordInUse; i++ {
 		b.words[i] ^= s.words[i]
 	}
 }
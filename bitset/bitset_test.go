@@ -1,6 +1,9 @@
 package bitset
 
 import (
+	"math/rand"
+	"slices"
+	"sync"
 	"testing"
 )
 
@@ -92,6 +95,17 @@ func Test_PrevClearBit(t *testing.T) {
 	}
 }
 
+func Test_PrevBitEmpty(t *testing.T) {
+	bs := NewBitSet(128)
+	if bs.PrevSetBit(100) != -1 {
+		t.Fatalf("BitSet PrevSetBit error, bs.PrevSetBit(100): %v\n", bs.PrevSetBit(100))
+	}
+
+	if bs.PrevClearBit(100) != -1 {
+		t.Fatalf("BitSet PrevClearBit error, bs.PrevClearBit(100): %v\n", bs.PrevClearBit(100))
+	}
+}
+
 func Test_NextSetBit(t *testing.T) {
 	bs := NewBitSet(0)
 	bs.Set(100)
@@ -160,6 +174,21 @@ func Test_Xor(t *testing.T) {
 	}
 }
 
+func Test_OrXorShorter(t *testing.T) {
+	b := NewBitSet(0)
+	b.Set(1000)
+
+	s := NewBitSet(0)
+	s.Set(1)
+
+	b.Or(s)
+	b.Xor(s)
+	b.Xor(s)
+	if b.String() != "{1, 1000}" {
+		t.Fatalf("BitSet Or Xor error, b.String() want \"{1, 1000}\", but got %v\n", b.String())
+	}
+}
+
 func Test_AndNot(t *testing.T) {
 	b := NewBitSet(0)
 	b.Set(1)
@@ -175,3 +204,91 @@ func Test_AndNot(t *testing.T) {
 		t.Fatalf("BitSet AndNot error, b.String() want \"{1, 3, 5}\", but got %v\n", b.String())
 	}
 }
+
+func Test_Bits(t *testing.T) {
+	bs := NewBitSet(0)
+	want := []uint{0, 3, 63, 64, 100, 1000}
+	for _, v := range want {
+		bs.Set(v)
+	}
+
+	if got := slices.Collect(bs.Bits()); !slices.Equal(got, want) {
+		t.Fatalf("BitSet bits error, want %v, but got %v\n", want, got)
+	}
+
+	for i := range bs.Bits() {
+		if i != 0 {
+			t.Fatalf("BitSet bits error, want 0, but got %v\n", i)
+		}
+
+		break
+	}
+
+	for i := range NewBitSet(0).Bits() {
+		t.Fatalf("BitSet bits error, empty bitset got %v\n", i)
+	}
+}
+
+func Test_SyncBitSet(t *testing.T) {
+	b := NewSyncBitSet(NewBitSet(0))
+	s := NewSyncBitSet(NewBitSet(0))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func(seed int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 2000; j++ {
+				bit := uint(r.Intn(1024))
+				switch r.Intn(6) {
+				case 0:
+					b.Clear(bit)
+				case 1:
+					s.Flip(bit)
+				case 2:
+					// 两个SyncBitSet互相操作不能死锁
+					b.Or(s)
+					s.AndNot(b)
+				default:
+					b.Set(bit)
+				}
+			}
+		}(int64(i))
+
+		// 遍历的回调中修改b，遍历时持有锁会导致死锁
+		go func(seed int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 20; j++ {
+				last := -1
+				for bit := range b.Bits() {
+					if int(bit) <= last {
+						t.Errorf("Bits not sorted: %v after %v\n", bit, last)
+						return
+					}
+
+					last = int(bit)
+					b.Clear(uint(r.Intn(1024)))
+				}
+
+				b.Get(uint(r.Intn(1024)))
+				b.NextSetBit(uint(r.Intn(1024)))
+				b.PrevClearBit(uint(r.Intn(1024)))
+				b.Xor(b)
+			}
+		}(int64(i))
+	}
+
+	wg.Wait()
+
+	b.Set(3)
+	b.Set(7)
+	b.And(b)
+	if !b.Get(3) || !b.Get(7) {
+		t.Fatalf("SyncBitSet And self error, got %v\n", b.String())
+	}
+}
@@ -0,0 +1,158 @@
+package bitset
+
+import (
+	"iter"
+	"slices"
+	"sync"
+)
+
+// SyncBitSet 并发安全的BitSet，读操作可以并行执行，Bits遍历时不持有锁
+type SyncBitSet struct {
+	mu sync.RWMutex
+	bs *BitSet
+}
+
+// NewSyncBitSet 创建一个并发安全的BitSet，bs交给SyncBitSet之后不能再直接使用
+func NewSyncBitSet(bs *BitSet) *SyncBitSet {
+	return &SyncBitSet{
+		bs: bs,
+	}
+}
+
+// Set bitIndex位置1
+func (b *SyncBitSet) Set(bitIndex uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bs.Set(bitIndex)
+}
+
+// Get bitIndex位的值是否为1
+func (b *SyncBitSet) Get(bitIndex uint) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bs.Get(bitIndex)
+}
+
+// Clear 清除bitIndex位的值
+func (b *SyncBitSet) Clear(bitIndex uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bs.Clear(bitIndex)
+}
+
+// Flip 将bitIndex指定的位置取反
+func (b *SyncBitSet) Flip(bitIndex uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bs.Flip(bitIndex)
+}
+
+// PrevSetBit 找到bitIndex位之前的第一个设置为1的位置
+func (b *SyncBitSet) PrevSetBit(bitIndex uint) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bs.PrevSetBit(bitIndex)
+}
+
+// PrevClearBit 找到bitIndex位之前的第一个设置为0的位置
+func (b *SyncBitSet) PrevClearBit(bitIndex uint) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bs.PrevClearBit(bitIndex)
+}
+
+// NextSetBit 找到从bitIndex位开始的第一个设置为1的位置
+func (b *SyncBitSet) NextSetBit(bitIndex uint) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bs.NextSetBit(bitIndex)
+}
+
+// NextClearBit 找到从bitIndex位开始的第一个设置为0的位置
+func (b *SyncBitSet) NextClearBit(bitIndex uint) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bs.NextClearBit(bitIndex)
+}
+
+// Bits 从小到大遍历所有设置为1的位置，开始遍历时拷贝所有设置为1的位置
+func (b *SyncBitSet) Bits() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		b.mu.RLock()
+		bits := slices.Collect(b.bs.Bits())
+		b.mu.RUnlock()
+
+		for _, i := range bits {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// And 与
+func (b *SyncBitSet) And(s *SyncBitSet) {
+	other := s.clone()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bs.And(other)
+}
+
+// Or 或
+func (b *SyncBitSet) Or(s *SyncBitSet) {
+	other := s.clone()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bs.Or(other)
+}
+
+// Xor 异或
+func (b *SyncBitSet) Xor(s *SyncBitSet) {
+	other := s.clone()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bs.Xor(other)
+}
+
+// AndNot 清除b在对应的s中已经设置为1的位
+func (b *SyncBitSet) AndNot(s *SyncBitSet) {
+	other := s.clone()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bs.AndNot(other)
+}
+
+// String String
+func (b *SyncBitSet) String() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.bs.String()
+}
+
+// clone 持有读锁拷贝BitSet，避免同时持有两个SyncBitSet的锁产生死锁
+func (b *SyncBitSet) clone() *BitSet {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return &BitSet{
+		words:     slices.Clone(b.bs.words),
+		wordInUse: b.bs.wordInUse,
+	}
+}
@@ -2,6 +2,7 @@ package adlist
 
 import (
 	"fmt"
+	"iter"
 	"strings"
 
 	"github.com/asinglestep/gods/utils"
@@ -159,6 +160,43 @@ func (l *List) SearchNode(entry interface{}) *Node {
 	return nil
 }
 
+// All 从头节点开始遍历，返回节点的位置和entry
+func (l *List) All() iter.Seq2[int, interface{}] {
+	return func(yield func(int, interface{}) bool) {
+		for i, node := 0, l.head; node != nil; i, node = i+1, node.next {
+			if !yield(i, node.entry) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 从尾节点开始遍历，返回节点的位置和entry
+func (l *List) Backward() iter.Seq2[int, interface{}] {
+	return func(yield func(int, interface{}) bool) {
+		for i, node := l.length-1, l.tail; node != nil; i, node = i-1, node.prev {
+			if !yield(i, node.entry) {
+				return
+			}
+		}
+	}
+}
+
+// Range 从头节点开始遍历位置在[lo, hi]之间的节点，返回节点的位置和entry
+func (l *List) Range(lo, hi int) iter.Seq2[int, interface{}] {
+	return func(yield func(int, interface{}) bool) {
+		for i, node := 0, l.head; node != nil && i <= hi; i, node = i+1, node.next {
+			if i < lo {
+				continue
+			}
+
+			if !yield(i, node.entry) {
+				return
+			}
+		}
+	}
+}
+
 // String String
 func (l *List) String() string {
 	iter := NewIterator(l)
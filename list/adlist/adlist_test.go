@@ -3,28 +3,12 @@ package adlist
 import (
 	// "fmt"
 	"testing"
-)
-
-type intComparator struct {
-}
 
-func (c intComparator) Compare(k1, k2 interface{}) int {
-	i1 := k1.(int)
-	i2 := k2.(int)
-
-	if i1 > i2 {
-		return 1
-	}
-
-	if i1 < i2 {
-		return -1
-	}
-
-	return 0
-}
+	"github.com/asinglestep/gods/utils"
+)
 
 func Test_Insert(t *testing.T) {
-	list := NewList(intComparator{})
+	list := NewList(utils.IntComparator)
 	list.AddNodeToHead(4)
 	list.AddNodeToTail(5)
 
@@ -51,7 +35,7 @@ func Test_Insert(t *testing.T) {
 }
 
 func Test_Delete(t *testing.T) {
-	list := NewList(intComparator{})
+	list := NewList(utils.IntComparator)
 
 	list.AddNodeToHead(4)
 	list.AddNodeToTail(5)
@@ -80,7 +64,7 @@ func Test_Delete(t *testing.T) {
 }
 
 func Test_Search(t *testing.T) {
-	list := NewList(intComparator{})
+	list := NewList(utils.IntComparator)
 
 	list.AddNodeToHead(4)
 	list.AddNodeToTail(5)
@@ -98,3 +82,53 @@ func Test_Search(t *testing.T) {
 		t.Fatalf("want 7, got %v\n", list.SearchNode(7).GetEntry().(int))
 	}
 }
+
+func Test_AllBackwardRange(t *testing.T) {
+	list := NewList(utils.IntComparator)
+	for i := 0; i < 10; i++ {
+		list.AddNodeToTail(i)
+	}
+
+	idx := 0
+	for i, v := range list.All() {
+		if i != idx || v.(int) != idx {
+			t.Fatalf("All want %v, got %v: %v\n", idx, i, v)
+		}
+
+		idx++
+	}
+
+	if idx != 10 {
+		t.Fatalf("All stop at %v\n", idx)
+	}
+
+	idx = 9
+	for i, v := range list.Backward() {
+		if i != idx || v.(int) != idx {
+			t.Fatalf("Backward want %v, got %v: %v\n", idx, i, v)
+		}
+
+		idx--
+	}
+
+	if idx != -1 {
+		t.Fatalf("Backward stop at %v\n", idx)
+	}
+
+	idx = 3
+	for i, v := range list.Range(3, 6) {
+		if i != idx || v.(int) != idx {
+			t.Fatalf("Range want %v, got %v: %v\n", idx, i, v)
+		}
+
+		if idx == 5 {
+			break
+		}
+
+		idx++
+	}
+
+	if idx != 5 {
+		t.Fatalf("Range stop at %v\n", idx)
+	}
+}
@@ -0,0 +1,52 @@
+package skiplist
+
+import (
+	"iter"
+)
+
+// BuildFromSorted 由按key升序排列的seq创建跳跃表，时间复杂度O(n)
+//
+// @param
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// seq: 按key升序排列的key、value，key不能重复
+// opts: 随机数种子、节点出现在上一层的概率、最大层数等选项
+//
+// @return
+// seq没有按key升序排列时返回false
+func BuildFromSorted[K, V any](comparator func(a, b K) int, seq iter.Seq2[K, V], opts ...Option) (*List[K, V], bool) {
+	l := NewListFunc[K, V](comparator, opts...)
+
+	// last[i]: 第i层的最后一个节点，rank[i]: last[i]的排名，head的排名为0
+	last := make([]*Node[K, V], l.maxLevel)
+	rank := make([]int, l.maxLevel)
+	for i := range last {
+		last[i] = l.head
+	}
+
+	for key, val := range seq {
+		if l.length > 0 && l.comparator(last[0].entry.GetKey(), key) >= 0 {
+			return nil, false
+		}
+
+		l.length++
+		level := l.randomLevel()
+		node := NewNode(level, key, val)
+		node.backward = last[0]
+
+		// 新节点追加到每一层的末尾
+		for i := 0; i < level; i++ {
+			last[i].level[i].forward = node
+			last[i].level[i].span = l.length - rank[i]
+			last[i], rank[i] = node, l.length
+		}
+
+		l.level = max(l.level, level)
+	}
+
+	// 每一层最后一个节点的跨度为它后面的节点数
+	for i := 0; i < l.level; i++ {
+		last[i].level[i].span = l.length - rank[i]
+	}
+
+	return l, true
+}
@@ -1,15 +1,15 @@
 package skiplist
 
 // Iterator Iterator
-type Iterator struct {
+type Iterator[K, V any] struct {
 	index int
-	node  *Node
-	list  *List
+	node  *Node[K, V]
+	list  *List[K, V]
 }
 
 // NewIterator NewIterator
-func NewIterator(list *List) *Iterator {
-	iter := &Iterator{}
+func NewIterator[K, V any](list *List[K, V]) *Iterator[K, V] {
+	iter := &Iterator[K, V]{}
 	iter.index = -1
 	iter.list = list
 	iter.node = nil
@@ -18,13 +18,14 @@ func NewIterator(list *List) *Iterator {
 }
 
 // Next Next
-func (iter *Iterator) Next() bool {
-	iter.index++
-
-	if iter.index >= iter.list.length {
+func (iter *Iterator[K, V]) Next() bool {
+	if iter.index+1 >= iter.list.length {
+		// 遍历结束，iter.node停在最后一个节点
+		iter.index = iter.list.length
 		return false
 	}
 
+	iter.index++
 	if iter.index == 0 {
 		iter.node = iter.list.head.level[0].forward
 	} else {
@@ -34,12 +35,27 @@ func (iter *Iterator) Next() bool {
 	return true
 }
 
+// Prev Prev
+func (iter *Iterator[K, V]) Prev() bool {
+	if iter.index <= 0 {
+		iter.index = -1
+		return false
+	}
+
+	if iter.index < iter.list.length {
+		iter.node = iter.node.backward
+	}
+
+	iter.index--
+	return true
+}
+
 // GetKey GetKey
-func (iter *Iterator) GetKey() interface{} {
+func (iter *Iterator[K, V]) GetKey() K {
 	return iter.node.entry.GetKey()
 }
 
 // GetValue GetValue
-func (iter *Iterator) GetValue() interface{} {
+func (iter *Iterator[K, V]) GetValue() V {
 	return iter.node.entry.GetValue()
 }
@@ -0,0 +1,66 @@
+package skiplist
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Option 创建跳跃表的选项
+type Option func(o *options)
+
+// options 跳跃表的选项
+type options struct {
+	source      rand.Source // 随机数源
+	probability float64     // 节点出现在上一层的概率
+	maxLevel    int         // 最大层数
+}
+
+// newOptions 默认选项：以当前时间为种子的随机数源，概率为0.5，最大层数为MAX_LEVEL
+func newOptions(opts []Option) *options {
+	o := &options{
+		probability: 0.5,
+		maxLevel:    MAX_LEVEL,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.source == nil {
+		o.source = rand.NewSource(time.Now().UnixNano())
+	}
+
+	return o
+}
+
+// WithSeed 使用seed作为随机数种子，相同的种子和插入顺序会生成相同结构的跳跃表，用于复现问题
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.source = rand.NewSource(seed)
+	}
+}
+
+// WithSource 使用src生成节点的层数，src只能被一个跳跃表使用
+func WithSource(src rand.Source) Option {
+	return func(o *options) {
+		o.source = src
+	}
+}
+
+// WithProbability 节点出现在上一层的概率，p需要在(0, 1)之间，否则忽略
+func WithProbability(p float64) Option {
+	return func(o *options) {
+		if p > 0 && p < 1 {
+			o.probability = p
+		}
+	}
+}
+
+// WithMaxLevel 最大层数，n需要大于0，否则忽略
+func WithMaxLevel(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxLevel = n
+		}
+	}
+}
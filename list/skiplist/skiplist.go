@@ -2,49 +2,76 @@ package skiplist
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
+	"iter"
 	"math/rand"
-	"time"
 
+	"github.com/asinglestep/gods/map/orderedmap"
 	"github.com/asinglestep/gods/utils"
 )
 
 const (
-	MAX_LEVEL = 32 // 跳跃表最大层数
+	MAX_LEVEL = 32 // 跳跃表默认的最大层数
 )
 
 // List List
-type List struct {
+type List[K, V any] struct {
 	level  int // 当前跳跃表的最大层数
 	length int // 长度
-	head   *Node
+	head   *Node[K, V]
 
-	comparator utils.Comparator
+	comparator  func(a, b K) int
+	rand        *rand.Rand // 生成节点层数的随机数
+	probability float64    // 节点出现在上一层的概率
+	maxLevel    int        // 最大层数
 }
 
-// NewList 创建跳跃表
-func NewList(comparator utils.Comparator) *List {
-	list := &List{}
+// NewList 创建key、value为interface{}的跳跃表
+func NewList(comparator utils.Comparator, opts ...Option) *List[interface{}, interface{}] {
+	return NewListFunc[interface{}, interface{}](comparator.Compare, opts...)
+}
+
+// NewListFunc 创建跳跃表
+//
+// @param
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// opts: 随机数种子、节点出现在上一层的概率、最大层数等选项
+func NewListFunc[K, V any](comparator func(a, b K) int, opts ...Option) *List[K, V] {
+	var key K
+	var val V
+
+	o := newOptions(opts)
+
+	list := &List[K, V]{}
 	list.level = 1
 	list.length = 0
-	list.head = NewNode(MAX_LEVEL, nil, nil)
+	list.head = NewNode(o.maxLevel, key, val)
 	list.comparator = comparator
+	list.rand = rand.New(o.source)
+	list.probability = o.probability
+	list.maxLevel = o.maxLevel
 
 	return list
 }
 
+// NewOrderedList 创建key为有序类型的跳跃表
+func NewOrderedList[K cmp.Ordered, V any](opts ...Option) *List[K, V] {
+	return NewListFunc[K, V](cmp.Compare[K], opts...)
+}
+
 // Search 查找
-func (l *List) Search(key interface{}) *utils.Entry {
+func (l *List[K, V]) Search(key K) *utils.TypedEntry[K, V] {
 	x := l.head
 
 	for i := l.level - 1; i >= 0; i-- {
 		for x.level[i].forward != nil {
-			res := l.comparator.Compare(x.level[i].forward.entry.GetKey(), key)
-			if res == utils.Et {
+			res := l.comparator(x.level[i].forward.entry.GetKey(), key)
+			if res == 0 {
 				return x.level[i].forward.entry
 			}
 
-			if res == utils.Gt {
+			if res > 0 {
 				break
 			}
 
@@ -56,11 +83,11 @@ func (l *List) Search(key interface{}) *utils.Entry {
 }
 
 // Insert 插入
-func (l *List) Insert(key, val interface{}) {
+func (l *List[K, V]) Insert(key K, val V) {
 	x := l.head
 	level := l.randomLevel()
-	update := make([]*Node, MAX_LEVEL)
-	rank := make([]int, MAX_LEVEL)
+	update := make([]*Node[K, V], l.maxLevel)
+	rank := make([]int, l.maxLevel)
 
 	// 将第0层到第l.level层中最后一个小于key的节点保存到update中
 	for i := l.level - 1; i >= 0; i-- {
@@ -70,7 +97,7 @@ func (l *List) Insert(key, val interface{}) {
 			rank[i] = rank[i+1]
 		}
 
-		for x.level[i].forward != nil && l.comparator.Compare(x.level[i].forward.entry.GetKey(), key) == utils.Lt {
+		for x.level[i].forward != nil && l.comparator(x.level[i].forward.entry.GetKey(), key) < 0 {
 			rank[i] += x.level[i].span
 			x = x.level[i].forward
 		}
@@ -105,16 +132,20 @@ func (l *List) Insert(key, val interface{}) {
 	}
 
 	node.backward = update[0]
+	if node.level[0].forward != nil {
+		node.level[0].forward.backward = node
+	}
+
 	l.length++
 }
 
 // Delete 删除
-func (l *List) Delete(key interface{}) {
+func (l *List[K, V]) Delete(key K) {
 	x := l.head
-	update := make([]*Node, MAX_LEVEL)
+	update := make([]*Node[K, V], l.maxLevel)
 
 	for i := l.level - 1; i >= 0; i-- {
-		for x.level[i].forward != nil && l.comparator.Compare(x.level[i].forward.entry.GetKey(), key) == utils.Lt {
+		for x.level[i].forward != nil && l.comparator(x.level[i].forward.entry.GetKey(), key) < 0 {
 			// 当前节点的key小于要删除的key，取下一个节点
 			x = x.level[i].forward
 		}
@@ -124,12 +155,16 @@ func (l *List) Delete(key interface{}) {
 	}
 
 	dNode := x.level[0].forward
-	if l.comparator.Compare(dNode.entry.GetKey(), key) == utils.Et {
+	if dNode != nil && l.comparator(dNode.entry.GetKey(), key) == 0 {
 		l.deleteNode(update, dNode)
 	}
 }
 
-func (l *List) deleteNode(update []*Node, dNode *Node) {
+func (l *List[K, V]) deleteNode(update []*Node[K, V], dNode *Node[K, V]) {
+	if dNode.level[0].forward != nil {
+		dNode.level[0].forward.backward = dNode.backward
+	}
+
 	for i := 0; i < l.level; i++ {
 		if update[i].level[i].forward == dNode {
 			update[i].level[i].span += dNode.level[i].span - 1
@@ -138,21 +173,244 @@ func (l *List) deleteNode(update []*Node, dNode *Node) {
 		} else {
 			update[i].level[i].span--
 		}
+	}
+
+	// 删除节点后，最高层可能没有节点了
+	for l.level > 1 && l.head.level[l.level-1].forward == nil {
+		l.level--
+	}
+
+	l.length--
+}
+
+// Put 插入key、value，key已存在时更新value
+func (l *List[K, V]) Put(key K, val V) {
+	if entry := l.Search(key); entry != nil {
+		entry.SetValue(val)
+		return
+	}
+
+	l.Insert(key, val)
+}
+
+// Get 查找key对应的value
+func (l *List[K, V]) Get(key K) (val V, bFound bool) {
+	if entry := l.Search(key); entry != nil {
+		return entry.GetValue(), true
+	}
+
+	return val, false
+}
+
+// Len 长度
+func (l *List[K, V]) Len() int {
+	return l.length
+}
+
+// Min 最小的key和对应的value
+func (l *List[K, V]) Min() (K, V, bool) {
+	return l.head.level[0].forward.unpack()
+}
+
+// Max 最大的key和对应的value
+func (l *List[K, V]) Max() (key K, val V, bFound bool) {
+	x := l.maximum()
+	if x == l.head {
+		return key, val, false
+	}
+
+	return x.unpack()
+}
+
+// Floor 小于等于key的最大key和对应的value
+func (l *List[K, V]) Floor(key K) (k K, v V, bFound bool) {
+	x := l.lookupLess(key)
+	if next := x.level[0].forward; next != nil && l.comparator(next.entry.GetKey(), key) == 0 {
+		return next.unpack()
+	}
+
+	if x == l.head {
+		return k, v, false
+	}
+
+	return x.unpack()
+}
+
+// Ceiling 大于等于key的最小key和对应的value
+func (l *List[K, V]) Ceiling(key K) (K, V, bool) {
+	return l.lookupLess(key).level[0].forward.unpack()
+}
+
+// Lower 小于key的最大key和对应的value
+func (l *List[K, V]) Lower(key K) (k K, v V, bFound bool) {
+	x := l.lookupLess(key)
+	if x == l.head {
+		return k, v, false
+	}
+
+	return x.unpack()
+}
+
+// Higher 大于key的最小key和对应的value
+func (l *List[K, V]) Higher(key K) (K, V, bool) {
+	x := l.lookupLess(key).level[0].forward
+	if x != nil && l.comparator(x.entry.GetKey(), key) == 0 {
+		// 跳过等于key的节点
+		x = x.level[0].forward
+	}
+
+	return x.unpack()
+}
+
+// PopMin 删除最小的key，并返回最小的key和对应的value
+func (l *List[K, V]) PopMin() (key K, val V, bFound bool) {
+	dNode := l.head.level[0].forward
+	if dNode == nil {
+		return key, val, false
+	}
+
+	key, val, bFound = dNode.unpack()
+
+	// 最小节点在每一层的前一个节点都是头节点
+	update := make([]*Node[K, V], l.maxLevel)
+	for i := range update {
+		update[i] = l.head
+	}
+
+	l.deleteNode(update, dNode)
+	return key, val, bFound
+}
+
+// PopMax 删除最大的key，并返回最大的key和对应的value
+func (l *List[K, V]) PopMax() (key K, val V, bFound bool) {
+	if key, val, bFound = l.Max(); bFound {
+		l.Delete(key)
+	}
+
+	return key, val, bFound
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的节点
+func (l *List[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for x := l.lookupLess(lo).level[0].forward; x != nil; x = x.level[0].forward {
+			if l.comparator(x.entry.GetKey(), hi) > 0 {
+				return
+			}
+
+			if !yield(x.entry.GetKey(), x.entry.GetValue()) {
+				return
+			}
+		}
+	}
+}
 
-		if l.level > 1 && l.head.level[i].forward == nil {
-			l.level--
+// All 按key升序遍历所有节点
+func (l *List[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for x := l.head.level[0].forward; x != nil; x = x.level[0].forward {
+			if !yield(x.entry.GetKey(), x.entry.GetValue()) {
+				return
+			}
 		}
 	}
+}
 
-	if dNode.level[0].forward != nil {
-		dNode.level[0].forward.backward = dNode.backward
+// Backward 按key降序遍历所有节点，沿着backward指针遍历
+func (l *List[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for x := l.maximum(); x != l.head; x = x.backward {
+			if !yield(x.entry.GetKey(), x.entry.GetValue()) {
+				return
+			}
+		}
 	}
+}
 
-	l.length--
+// Iterator 从最小key开始的迭代器
+func (l *List[K, V]) Iterator() orderedmap.Iterator[K, V] {
+	return NewIterator(l)
+}
+
+// Verify 验证跳跃表的顺序、backward指针、每一层的跨度和长度
+func (l *List[K, V]) Verify() bool {
+	// 第0层的排名
+	rank := map[*Node[K, V]]int{l.head: 0}
+	prev := l.head
+	for x := l.head.level[0].forward; x != nil; x = x.level[0].forward {
+		if x.backward != prev || len(x.level) > l.level {
+			return false
+		}
+
+		if prev != l.head && l.comparator(prev.entry.GetKey(), x.entry.GetKey()) > 0 {
+			return false
+		}
+
+		rank[x] = len(rank)
+		prev = x
+	}
+
+	if len(rank)-1 != l.length {
+		return false
+	}
+
+	for i := 0; i < l.maxLevel; i++ {
+		if i >= l.level {
+			// 高于l.level的层没有节点
+			if l.head.level[i].forward != nil {
+				return false
+			}
+
+			continue
+		}
+
+		if i > 0 && i == l.level-1 && l.head.level[i].forward == nil {
+			return false
+		}
+
+		for x := l.head; x != nil; x = x.level[i].forward {
+			next := x.level[i].forward
+			span := l.length - rank[x]
+			if next != nil {
+				span = rank[next] - rank[x]
+			}
+
+			if x.level[i].span != span {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// maximum 最后一个节点，跳跃表为空时返回head
+func (l *List[K, V]) maximum() *Node[K, V] {
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil {
+			x = x.level[i].forward
+		}
+	}
+
+	return x
+}
+
+// lookupLess 查找最后一个小于key的节点，没有时返回head
+func (l *List[K, V]) lookupLess(key K) *Node[K, V] {
+	x := l.head
+
+	for i := l.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && l.comparator(x.level[i].forward.entry.GetKey(), key) < 0 {
+			x = x.level[i].forward
+		}
+	}
+
+	return x
 }
 
 // String String
-func (l *List) String() string {
+func (l *List[K, V]) String() string {
 	buffer := bytes.Buffer{}
 	for i := l.level - 1; i >= 0; i-- {
 		x := l.head
@@ -178,22 +436,18 @@ func (l *List) String() string {
 	return buffer.String()
 }
 
-// randomLevel randomLevel
-func (l *List) randomLevel() int {
+// randomLevel 随机生成节点的层数，节点以probability的概率出现在上一层，最多maxLevel层
+func (l *List[K, V]) randomLevel() int {
 	level := 1
-	for rand.New(rand.NewSource(time.Now().UnixNano())).Float32() < 0.5 {
+	for level < l.maxLevel && l.rand.Float64() < l.probability {
 		level++
 	}
 
-	if level > MAX_LEVEL {
-		level = MAX_LEVEL
-	}
-
 	return level
 }
 
 // genSpace genSpace
-func (l *List) genSpace(n int) string {
+func (l *List[K, V]) genSpace(n int) string {
 	buffer := bytes.Buffer{}
 	for i := 0; i < n; i++ {
 		buffer.WriteString(" ")
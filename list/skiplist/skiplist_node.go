@@ -5,23 +5,32 @@ import (
 )
 
 // Level Level
-type Level struct {
-	forward *Node // 指向后续节点
-	span    int   // 跨度
+type Level[K, V any] struct {
+	forward *Node[K, V] // 指向后续节点
+	span    int         // 跨度
 }
 
 // Node Node
-type Node struct {
-	entry    *utils.Entry // 节点数据
-	level    []Level
-	backward *Node
+type Node[K, V any] struct {
+	entry    *utils.TypedEntry[K, V] // 节点数据
+	level    []Level[K, V]
+	backward *Node[K, V]
 }
 
 // NewNode 创建节点
-func NewNode(level int, key, val interface{}) *Node {
-	node := &Node{}
-	node.entry = utils.NewEntry(key, val)
-	node.level = make([]Level, level)
+func NewNode[K, V any](level int, key K, val V) *Node[K, V] {
+	node := &Node[K, V]{}
+	node.entry = utils.NewTypedEntry(key, val)
+	node.level = make([]Level[K, V], level)
 
 	return node
 }
+
+// unpack 获取节点的key和value，node为nil时返回false
+func (node *Node[K, V]) unpack() (key K, val V, bFound bool) {
+	if node == nil {
+		return key, val, false
+	}
+
+	return node.entry.GetKey(), node.entry.GetValue(), true
+}
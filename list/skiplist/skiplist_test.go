@@ -1,28 +1,18 @@
 package skiplist
 
 import (
+	"cmp"
+	"iter"
 	"math/rand"
+	"slices"
+	"sync"
 	"testing"
 	"time"
-)
-
-type intComparator struct {
-}
-
-func (c intComparator) Compare(k1, k2 interface{}) int {
-	i1 := k1.(int)
-	i2 := k2.(int)
-
-	if i1 > i2 {
-		return 1
-	}
 
-	if i1 < i2 {
-		return -1
-	}
-
-	return 0
-}
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/map/orderedmap/conformance"
+	"github.com/asinglestep/gods/utils"
+)
 
 func Test_SkipListRandInsert(t *testing.T) {
 	num := 10
@@ -30,7 +20,7 @@ func Test_SkipListRandInsert(t *testing.T) {
 	// fmt.Println("插入数组: ", arr)
 	// arr = []int{2, 9, 4, 5, 8, 1, 0, 6, 7, 3}
 
-	skipList := NewList(intComparator{})
+	skipList := NewList(utils.IntComparator)
 	for _, v := range arr {
 		skipList.Insert(v, v)
 	}
@@ -54,7 +44,7 @@ func Test_SkipListRandDelete(t *testing.T) {
 	// fmt.Println("插入数组: ", arr)
 	// arr = []int{2, 9, 4, 5, 8, 1, 0, 6, 7, 3}
 
-	skipList := NewList(intComparator{})
+	skipList := NewList(utils.IntComparator)
 	for _, v := range arr {
 		skipList.Insert(v, v)
 	}
@@ -92,7 +82,7 @@ func Test_SkipListRandSearch(t *testing.T) {
 	// fmt.Println("插入数组: ", arr)
 	// arr = []int{2, 9, 4, 5, 8, 1, 0, 6, 7, 3}
 
-	skipList := NewList(intComparator{})
+	skipList := NewList(utils.IntComparator)
 	for _, v := range arr {
 		skipList.Insert(v, v)
 	}
@@ -112,3 +102,196 @@ func Test_SkipListRandSearch(t *testing.T) {
 		t.Fatalf("want %v, got %v\n", key, entry.GetKey().(int))
 	}
 }
+
+func Test_SkipListConformance(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewOrderedList[int, int]()
+	})
+}
+
+func Test_SyncSkipListConformance(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewSyncList(NewOrderedList[int, int]())
+	})
+}
+
+func Test_SyncSkipListConcurrent(t *testing.T) {
+	conformance.RunConcurrent(t, NewSyncList(NewOrderedList[int, int]()))
+}
+
+func Test_SyncSkipListSearch(t *testing.T) {
+	list := NewSyncList(NewOrderedList[int, int]())
+	for i := 0; i < 100; i++ {
+		list.Insert(i*2, i)
+	}
+
+	if found := list.Search(20); found == nil || found.GetKey() != 20 || found.GetValue() != 10 {
+		t.Fatalf("Search(20) want 20: 10, got %v\n", found)
+	}
+
+	if found := list.Search(21); found != nil {
+		t.Fatalf("Search(21) want nil, got %v\n", found)
+	}
+
+	// 返回的entry是拷贝，修改拷贝不影响跳表
+	list.Search(20).SetValue(-1)
+	if v, _ := list.Get(20); v != 10 {
+		t.Fatalf("Get(20) want 10, got %v\n", v)
+	}
+
+	if !list.Verify() {
+		t.Fatalf("Verify Error\n")
+	}
+
+	// 读取返回的拷贝时不持有锁，同时修改跳表不会产生数据竞争，使用go test -race验证
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 2000; i++ {
+			list.Put(i%200, -i)
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		if found := list.Search(i % 200); found != nil {
+			_ = found.GetValue()
+		}
+	}
+
+	wg.Wait()
+
+	if !list.Verify() {
+		t.Fatalf("Verify Error after concurrent Put\n")
+	}
+}
+
+// levels 按顺序返回所有节点的层数
+func levels[K, V any](l *List[K, V]) []int {
+	var res []int
+	for x := l.head.level[0].forward; x != nil; x = x.level[0].forward {
+		res = append(res, len(x.level))
+	}
+
+	return res
+}
+
+func Test_SkipListSeed(t *testing.T) {
+	keys := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(1000)
+
+	build := func(opts ...Option) *List[int, int] {
+		l := NewOrderedList[int, int](opts...)
+		for _, k := range keys {
+			l.Insert(k, k)
+		}
+
+		return l
+	}
+
+	// 相同的种子生成相同结构的跳跃表
+	a, b := levels(build(WithSeed(42))), levels(build(WithSource(rand.NewSource(42))))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("same seed want same levels, index %v got %v %v\n", i, a[i], b[i])
+		}
+	}
+
+	// 最大层数
+	l := build(WithSeed(1), WithMaxLevel(3), WithProbability(0.9))
+	if l.level > 3 {
+		t.Fatalf("WithMaxLevel(3) got level %v\n", l.level)
+	}
+
+	for _, level := range levels(l) {
+		if level > 3 {
+			t.Fatalf("WithMaxLevel(3) got node level %v\n", level)
+		}
+	}
+
+	for i, k := range l.All() {
+		if i != k {
+			t.Fatalf("want %v, got %v\n", i, k)
+		}
+	}
+
+	// 不合法的选项被忽略
+	l = NewOrderedList[int, int](WithMaxLevel(0), WithProbability(1))
+	if l.maxLevel != MAX_LEVEL || l.probability != 0.5 {
+		t.Fatalf("invalid options should be ignored, got %v %v\n", l.maxLevel, l.probability)
+	}
+}
+
+func Test_SkipListConformanceOptions(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewOrderedList[int, int](WithSeed(1), WithMaxLevel(4), WithProbability(0.25))
+	})
+}
+
+func Test_SkipListBuildFromSorted(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 100, 5000} {
+		vals := rand.Perm(n)
+		l, ok := BuildFromSorted(cmp.Compare[int], slices.All(vals), WithSeed(int64(n)))
+		if !ok || !l.Verify() || l.Len() != n {
+			t.Fatalf("n %v: BuildFromSorted Verify Error, got %v nodes\n", n, l.Len())
+		}
+
+		idx := 0
+		for k, v := range l.All() {
+			if k != idx || v != vals[idx] {
+				t.Fatalf("n %v: want %v %v, got %v %v\n", n, idx, vals[idx], k, v)
+			}
+
+			idx++
+		}
+
+		// 创建的跳跃表可以继续插入、删除
+		for i := 0; i < n; i += 2 {
+			l.Delete(i)
+			l.Insert(n+i, i)
+		}
+
+		if !l.Verify() || l.Len() != n {
+			t.Fatalf("n %v: Verify Error after Insert and Delete\n", n)
+		}
+	}
+
+	unsorted := func(yield func(int, int) bool) {
+		for _, k := range []int{1, 3, 2} {
+			if !yield(k, k) {
+				return
+			}
+		}
+	}
+
+	if _, ok := BuildFromSorted(cmp.Compare[int], unsorted); ok {
+		t.Fatalf("unsorted input should return false\n")
+	}
+
+	// 比较函数只保证结果的符号
+	sub := func(a, b int) int { return a - b }
+	step := func(keys ...int) iter.Seq2[int, int] {
+		return func(yield func(int, int) bool) {
+			for _, k := range keys {
+				if !yield(k, k) {
+					return
+				}
+			}
+		}
+	}
+
+	if l, ok := BuildFromSorted(sub, step(0, 3, 6, 9, 12)); !ok || !l.Verify() || l.Len() != 5 {
+		t.Fatalf("sorted input with a - b comparator should return true\n")
+	}
+
+	if _, ok := BuildFromSorted(sub, step(0, 9, 3)); ok {
+		t.Fatalf("unsorted input with a - b comparator should return false\n")
+	}
+}
+
+func Benchmark_SkipListBuildFromSorted(b *testing.B) {
+	vals := make([]int, 100000)
+	for i := 0; i < b.N; i++ {
+		BuildFromSorted(cmp.Compare[int], slices.All(vals))
+	}
+}
@@ -0,0 +1,56 @@
+package skiplist
+
+import (
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/utils"
+)
+
+// SyncList 并发安全的跳表，读操作可以并行执行，Range、All、Backward、Iterator遍历时不持有锁，见orderedmap.Sync
+//
+// Search在持有读锁时拷贝entry，返回的entry不在跳表中，读取时不需要持有锁，修改也不会影响跳表
+type SyncList[K, V any] struct {
+	*orderedmap.Sync[K, V]
+	list *List[K, V]
+}
+
+// NewSyncList 创建一个并发安全的跳表，list交给SyncList之后不能再直接使用
+func NewSyncList[K, V any](list *List[K, V]) *SyncList[K, V] {
+	return &SyncList[K, V]{
+		Sync: orderedmap.NewSync[K, V](list),
+		list: list,
+	}
+}
+
+// Insert 插入
+func (l *SyncList[K, V]) Insert(key K, val V) {
+	l.Put(key, val)
+}
+
+// Search 查找key对应的entry，返回entry的拷贝
+func (l *SyncList[K, V]) Search(key K) (entry *utils.TypedEntry[K, V]) {
+	l.View(func(orderedmap.OrderedMap[K, V]) {
+		if found := l.list.Search(key); found != nil {
+			entry = utils.NewTypedEntry(found.GetKey(), found.GetValue())
+		}
+	})
+
+	return entry
+}
+
+// Verify 验证跳跃表的顺序、backward指针、每一层的跨度和长度
+func (l *SyncList[K, V]) Verify() (bOK bool) {
+	l.View(func(orderedmap.OrderedMap[K, V]) {
+		bOK = l.list.Verify()
+	})
+
+	return bOK
+}
+
+// String String
+func (l *SyncList[K, V]) String() (str string) {
+	l.View(func(orderedmap.OrderedMap[K, V]) {
+		str = l.list.String()
+	})
+
+	return str
+}
@@ -2,6 +2,7 @@ package linkedhashmap
 
 import (
 	"fmt"
+	"iter"
 
 	"github.com/asinglestep/gods/list/adlist"
 	"github.com/asinglestep/gods/utils"
@@ -33,10 +34,9 @@ func NewLinkedHashMap(capacity uint64) *LinkedHashMap {
 func (l *LinkedHashMap) Put(entry *utils.Entry) error {
 	node, ok := l.m[entry.GetKey()]
 	if ok {
-		// 节点存在，更新
-		node.SetEntry(entry)
+		// 节点存在，更新，并移动到链表尾部
 		l.list.DeleteNode(node)
-		l.list.AddNodeToTail(node)
+		l.m[entry.GetKey()] = l.list.AddNodeToTail(entry)
 		return nil
 	}
 
@@ -71,9 +71,36 @@ func (l *LinkedHashMap) Get(key interface{}) (entry *utils.Entry, err error) {
 		}
 
 		l.list.DeleteNode(node)
-		l.list.AddNodeToTail(node)
+		l.m[key] = l.list.AddNodeToTail(e)
 		return e, nil
 	}
 
 	return nil, ErrNotExist
 }
+
+// All 从最久未访问的节点开始遍历，返回key和value，遍历不会改变节点的顺序
+func (l *LinkedHashMap) All() iter.Seq2[interface{}, interface{}] {
+	return entries(l.list.All())
+}
+
+// Backward 从最近访问的节点开始遍历，返回key和value，遍历不会改变节点的顺序
+func (l *LinkedHashMap) Backward() iter.Seq2[interface{}, interface{}] {
+	return entries(l.list.Backward())
+}
+
+// Range 从最久未访问的节点开始遍历位置在[lo, hi]之间的节点，返回key和value
+func (l *LinkedHashMap) Range(lo, hi int) iter.Seq2[interface{}, interface{}] {
+	return entries(l.list.Range(lo, hi))
+}
+
+// entries 将链表的(位置, entry)序列转换为(key, value)序列
+func entries(seq iter.Seq2[int, interface{}]) iter.Seq2[interface{}, interface{}] {
+	return func(yield func(interface{}, interface{}) bool) {
+		for _, e := range seq {
+			entry := e.(*utils.Entry)
+			if !yield(entry.GetKey(), entry.GetValue()) {
+				return
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package linkedhashmap
 
 import (
+	"math/rand"
+	"sync"
 	"testing"
 
 	"github.com/asinglestep/gods/utils"
@@ -68,3 +70,117 @@ func Test_Get(t *testing.T) {
 		t.Fatalf("wang 10, got %v", e.GetKey().(int))
 	}
 }
+
+func Test_All(t *testing.T) {
+	lmap := NewLinkedHashMap(5)
+	for i := 1; i <= 6; i++ {
+		lmap.Put(utils.NewEntry(i, i))
+	}
+
+	// 2 移到尾部，3 更新后移到尾部
+	lmap.Get(2)
+	lmap.Put(utils.NewEntry(3, 33))
+
+	keys := []int{4, 5, 6, 2, 3}
+	values := []int{4, 5, 6, 2, 33}
+
+	idx := 0
+	for k, v := range lmap.All() {
+		if k.(int) != keys[idx] || v.(int) != values[idx] {
+			t.Fatalf("All want %v: %v, got %v: %v", keys[idx], values[idx], k, v)
+		}
+
+		idx++
+	}
+
+	if idx != len(keys) {
+		t.Fatalf("All stop at %v", idx)
+	}
+
+	idx = len(keys) - 1
+	for k := range lmap.Backward() {
+		if k.(int) != keys[idx] {
+			t.Fatalf("Backward want %v, got %v", keys[idx], k)
+		}
+
+		idx--
+	}
+
+	if idx != -1 {
+		t.Fatalf("Backward stop at %v", idx)
+	}
+
+	idx = 1
+	for k := range lmap.Range(1, 3) {
+		if k.(int) != keys[idx] {
+			t.Fatalf("Range want %v, got %v", keys[idx], k)
+		}
+
+		idx++
+	}
+
+	if idx != 4 {
+		t.Fatalf("Range stop at %v", idx)
+	}
+}
+
+func Test_SyncLinkedHashMap(t *testing.T) {
+	lmap := NewSyncLinkedHashMap(NewLinkedHashMap(64))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func(seed int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 2000; j++ {
+				key := r.Intn(128)
+				if r.Intn(2) == 0 {
+					lmap.Put(utils.NewEntry(key, j))
+				} else {
+					lmap.Get(key)
+				}
+			}
+		}(int64(i))
+
+		// 遍历的回调中修改lmap，遍历时持有锁会导致死锁
+		go func(seed int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 20; j++ {
+				n := 0
+				for k := range lmap.All() {
+					lmap.Get(k)
+					n++
+				}
+
+				if n > 64 {
+					t.Errorf("All yield %v entries, capacity 64\n", n)
+					return
+				}
+
+				for range lmap.Range(0, 10) {
+					lmap.Put(utils.NewEntry(r.Intn(128), j))
+				}
+
+				for range lmap.Backward() {
+					break
+				}
+			}
+		}(int64(i))
+	}
+
+	wg.Wait()
+
+	lmap.Put(utils.NewEntry(1000, 1000))
+	for k, v := range lmap.Backward() {
+		if k.(int) != 1000 || v.(int) != 1000 {
+			t.Fatalf("Backward want 1000, got %v: %v\n", k, v)
+		}
+
+		break
+	}
+}
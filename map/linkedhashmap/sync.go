@@ -0,0 +1,69 @@
+package linkedhashmap
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/utils"
+)
+
+// SyncLinkedHashMap 并发安全的LinkedHashMap
+//
+// Get会把节点移动到链表尾部，和Put一样需要持有写锁；
+// All、Backward、Range在持有读锁时拷贝元素，遍历拷贝时不持有锁
+type SyncLinkedHashMap struct {
+	mu   sync.RWMutex
+	lmap *LinkedHashMap
+}
+
+// NewSyncLinkedHashMap 创建一个并发安全的LinkedHashMap，lmap交给SyncLinkedHashMap之后不能再直接使用
+func NewSyncLinkedHashMap(lmap *LinkedHashMap) *SyncLinkedHashMap {
+	return &SyncLinkedHashMap{
+		lmap: lmap,
+	}
+}
+
+// Put 加入或者更新节点
+func (l *SyncLinkedHashMap) Put(entry *utils.Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lmap.Put(entry)
+}
+
+// Get 获取key指定的数据
+func (l *SyncLinkedHashMap) Get(key interface{}) (*utils.Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lmap.Get(key)
+}
+
+// All 从最久未访问的节点开始遍历，返回key和value，开始遍历时拷贝元素
+func (l *SyncLinkedHashMap) All() iter.Seq2[interface{}, interface{}] {
+	return l.snapshot(l.lmap.All)
+}
+
+// Backward 从最近访问的节点开始遍历，返回key和value，开始遍历时拷贝元素
+func (l *SyncLinkedHashMap) Backward() iter.Seq2[interface{}, interface{}] {
+	return l.snapshot(l.lmap.Backward)
+}
+
+// Range 从最久未访问的节点开始遍历位置在[lo, hi]之间的节点，返回key和value，开始遍历时拷贝元素
+func (l *SyncLinkedHashMap) Range(lo, hi int) iter.Seq2[interface{}, interface{}] {
+	return l.snapshot(func() iter.Seq2[interface{}, interface{}] {
+		return l.lmap.Range(lo, hi)
+	})
+}
+
+// snapshot 开始遍历时持有读锁拷贝seq返回的序列，遍历拷贝时不持有锁
+func (l *SyncLinkedHashMap) snapshot(seq func() iter.Seq2[interface{}, interface{}]) iter.Seq2[interface{}, interface{}] {
+	return func(yield func(interface{}, interface{}) bool) {
+		l.mu.RLock()
+		s := orderedmap.Collect(seq())
+		l.mu.RUnlock()
+
+		s.All()(yield)
+	}
+}
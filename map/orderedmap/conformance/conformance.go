@@ -0,0 +1,529 @@
+package conformance
+
+import (
+	"iter"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asinglestep/gods/map/orderedmap"
+)
+
+// Verifier 能够验证自身结构的有序map，例如各个树的Verify
+type Verifier interface {
+	Verify() bool
+}
+
+// PrevIterator 支持反向迭代的迭代器
+type PrevIterator interface {
+	Prev() bool
+}
+
+// Config 测试配置
+type Config struct {
+	Ops        int // 随机操作的次数
+	KeyRange   int // key的取值范围[0, KeyRange)
+	CheckEvery int // 每隔多少次操作做一次完整校验
+}
+
+// DefaultConfigs 默认的测试配置
+//
+// key范围小的配置会产生大量的覆盖和删除已存在的key，
+// key范围大的配置会产生较多的节点分裂和合并
+var DefaultConfigs = map[string]Config{
+	"dense":  {Ops: 20000, KeyRange: 256, CheckEvery: 1000},
+	"sparse": {Ops: 50000, KeyRange: 1 << 20, CheckEvery: 5000},
+}
+
+// Run 使用DefaultConfigs对newMap创建的有序map进行差分测试
+func Run(t *testing.T, newMap func() orderedmap.OrderedMap[int, int]) {
+	for name, cfg := range DefaultConfigs {
+		t.Run(name, func(t *testing.T) {
+			RunConfig(t, newMap, cfg, time.Now().UnixNano())
+		})
+	}
+}
+
+// RunConfig 对newMap创建的有序map随机执行插入、覆盖、删除、PopMin、PopMax操作，
+// 并与go map + 有序切片实现的参考模型比较Get、Len、Min、Max、Floor、Ceiling、Lower、Higher、Range、迭代器以及Verify的结果
+//
+// @param
+// seed: 随机数种子，失败时会打印出来用于复现
+func RunConfig(t *testing.T, newMap func() orderedmap.OrderedMap[int, int], cfg Config, seed int64) {
+	c := &checker{
+		t:    t,
+		rnd:  rand.New(rand.NewSource(seed)),
+		seed: seed,
+		cfg:  cfg,
+		m:    newMap(),
+		ref:  make(map[int]int),
+	}
+
+	// 空map
+	c.checkAll(0)
+
+	for op := 1; op <= cfg.Ops; op++ {
+		key := c.rnd.Intn(cfg.KeyRange)
+
+		switch n := c.rnd.Intn(10); {
+		case n < 6:
+			// 插入或者覆盖
+			c.put(key, c.rnd.Int())
+
+		case n < 8:
+			// 删除，可能不存在
+			c.delete(key)
+
+		case n < 9:
+			// 删除已存在的key
+			if len(c.keys) != 0 {
+				key = c.keys[c.rnd.Intn(len(c.keys))]
+			}
+
+			c.delete(key)
+
+		default:
+			// 删除最小或者最大的key
+			key = c.pop(op, c.rnd.Intn(2) == 0)
+		}
+
+		c.checkKey(op, key)
+		if op%cfg.CheckEvery == 0 {
+			c.checkAll(op)
+		}
+	}
+
+	// 删除全部的key
+	for i := 0; len(c.keys) != 0; i++ {
+		if i%3 == 0 {
+			c.pop(cfg.Ops+1, i%2 == 0)
+		} else {
+			c.delete(c.keys[0])
+		}
+	}
+
+	// 空map
+	c.pop(cfg.Ops+1, true)
+	c.pop(cfg.Ops+1, false)
+
+	c.checkAll(cfg.Ops + 1)
+}
+
+// RunConcurrent 多个goroutine同时对m执行读、写和遍历，遍历的回调中会修改m，
+// 用于验证并发安全的有序map，需要配合go test -race检查数据竞争，遍历时持有锁会导致死锁
+func RunConcurrent(t *testing.T, m orderedmap.OrderedMap[int, int]) {
+	const (
+		workers  = 4
+		ops      = 2000
+		keyRange = 512
+	)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(3)
+
+		// 写
+		go func(seed int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < ops; j++ {
+				switch r.Intn(8) {
+				case 0:
+					m.PopMin()
+				case 1:
+					m.PopMax()
+				case 2, 3:
+					m.Delete(r.Intn(keyRange))
+				default:
+					m.Put(r.Intn(keyRange), j)
+				}
+			}
+		}(int64(i))
+
+		// 读
+		go func(seed int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < ops; j++ {
+				key := r.Intn(keyRange)
+				m.Get(key)
+				m.Floor(key)
+				m.Higher(key)
+				m.Len()
+				m.Min()
+			}
+		}(int64(i))
+
+		// 遍历，回调中修改m
+		go func(seed int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < ops/100; j++ {
+				last := -1
+				for k := range m.All() {
+					if k <= last {
+						t.Errorf("All not sorted: %v after %v\n", k, last)
+						return
+					}
+
+					last = k
+					m.Put(r.Intn(keyRange), j)
+				}
+
+				for k := range m.Range(keyRange/4, keyRange/2) {
+					m.Delete(k)
+				}
+
+				iter := m.Iterator()
+				for iter.Next() {
+					m.Delete(iter.GetKey())
+					break
+				}
+			}
+		}(int64(i))
+	}
+
+	wg.Wait()
+
+	if v, ok := m.(Verifier); ok && !v.Verify() {
+		t.Fatalf("Verify failed\n")
+	}
+
+	n := 0
+	for range m.All() {
+		n++
+	}
+
+	if n != m.Len() {
+		t.Fatalf("All yield %v keys, Len %v\n", n, m.Len())
+	}
+}
+
+type checker struct {
+	t    *testing.T
+	rnd  *rand.Rand
+	seed int64
+	cfg  Config
+	m    orderedmap.OrderedMap[int, int]
+	ref  map[int]int // 参考模型
+	keys []int       // 参考模型中有序的key
+}
+
+// put 同时插入到有序map和参考模型中
+func (c *checker) put(key, val int) {
+	c.m.Put(key, val)
+	if _, ok := c.ref[key]; !ok {
+		pos, _ := slices.BinarySearch(c.keys, key)
+		c.keys = slices.Insert(c.keys, pos, key)
+	}
+
+	c.ref[key] = val
+}
+
+// delete 同时从有序map和参考模型中删除
+func (c *checker) delete(key int) {
+	c.m.Delete(key)
+	if _, ok := c.ref[key]; ok {
+		pos, _ := slices.BinarySearch(c.keys, key)
+		c.keys = slices.Delete(c.keys, pos, pos+1)
+	}
+
+	delete(c.ref, key)
+}
+
+// pop 从有序map中删除最小或者最大的key，并校验返回的key和value
+//
+// @return
+// 删除的key，map为空时返回-1
+func (c *checker) pop(op int, bMin bool) int {
+	c.t.Helper()
+
+	var k, v int
+	var ok bool
+	name, pos := "PopMax", len(c.keys)-1
+	if bMin {
+		name, pos = "PopMin", 0
+		k, v, ok = c.m.PopMin()
+	} else {
+		k, v, ok = c.m.PopMax()
+	}
+
+	if len(c.keys) == 0 {
+		if ok {
+			c.fatalf(op, "%v want not found, got %v\n", name, k)
+		}
+
+		return -1
+	}
+
+	key := c.keys[pos]
+	if !ok || k != key || v != c.ref[key] {
+		c.fatalf(op, "%v want %v: %v, got %v: %v %v\n", name, key, c.ref[key], k, v, ok)
+	}
+
+	c.keys = slices.Delete(c.keys, pos, pos+1)
+	delete(c.ref, key)
+
+	return key
+}
+
+// fatalf 失败时打印随机数种子和操作序号
+func (c *checker) fatalf(op int, format string, args ...interface{}) {
+	c.t.Helper()
+	c.t.Fatalf("seed %v, op %v: "+format, append([]interface{}{c.seed, op}, args...)...)
+}
+
+// checkKey 校验刚刚操作过的key
+func (c *checker) checkKey(op int, key int) {
+	c.t.Helper()
+
+	want, wantOK := c.ref[key]
+	if got, ok := c.m.Get(key); ok != wantOK || got != want {
+		c.fatalf(op, "Get(%v) want %v %v, got %v %v\n", key, want, wantOK, got, ok)
+	}
+
+	if c.m.Len() != len(c.ref) {
+		c.fatalf(op, "Len want %v, got %v\n", len(c.ref), c.m.Len())
+	}
+}
+
+// checkAll 完整校验
+func (c *checker) checkAll(op int) {
+	c.t.Helper()
+
+	keys := c.keys
+
+	if v, ok := c.m.(Verifier); ok && !v.Verify() {
+		c.fatalf(op, "Verify failed\n")
+	}
+
+	if c.m.Len() != len(keys) {
+		c.fatalf(op, "Len want %v, got %v\n", len(keys), c.m.Len())
+	}
+
+	c.checkMinMax(op, keys)
+	c.checkIterator(op, keys)
+	c.checkSeq(op, "All", c.m.All(), slices.All(keys))
+	c.checkSeq(op, "Backward", c.m.Backward(), slices.Backward(keys))
+
+	for i := 0; i < 100; i++ {
+		key := c.rnd.Intn(c.cfg.KeyRange+2) - 1
+		c.checkKey(op, key)
+		c.checkFloorCeiling(op, keys, key)
+		c.checkLowerHigher(op, keys, key)
+	}
+
+	for i := 0; i < 20; i++ {
+		lo := c.rnd.Intn(c.cfg.KeyRange+2) - 1
+		hi := lo + c.rnd.Intn(c.cfg.KeyRange/4+1)
+		c.checkRange(op, keys, lo, hi)
+	}
+
+	// lo大于hi
+	c.checkRange(op, keys, c.cfg.KeyRange/2, c.cfg.KeyRange/2-1)
+}
+
+// checkMinMax 校验Min、Max
+func (c *checker) checkMinMax(op int, keys []int) {
+	c.t.Helper()
+
+	k, v, ok := c.m.Min()
+	if len(keys) == 0 {
+		if ok {
+			c.fatalf(op, "Min want not found, got %v\n", k)
+		}
+	} else if !ok || k != keys[0] || v != c.ref[k] {
+		c.fatalf(op, "Min want %v, got %v %v\n", keys[0], k, ok)
+	}
+
+	k, v, ok = c.m.Max()
+	if len(keys) == 0 {
+		if ok {
+			c.fatalf(op, "Max want not found, got %v\n", k)
+		}
+	} else if !ok || k != keys[len(keys)-1] || v != c.ref[k] {
+		c.fatalf(op, "Max want %v, got %v %v\n", keys[len(keys)-1], k, ok)
+	}
+}
+
+// checkFloorCeiling 校验Floor、Ceiling
+func (c *checker) checkFloorCeiling(op int, keys []int, key int) {
+	c.t.Helper()
+
+	// 第一个大于等于key的位置
+	pos, found := slices.BinarySearch(keys, key)
+
+	k, v, ok := c.m.Ceiling(key)
+	if pos == len(keys) {
+		if ok {
+			c.fatalf(op, "Ceiling(%v) want not found, got %v\n", key, k)
+		}
+	} else if !ok || k != keys[pos] || v != c.ref[k] {
+		c.fatalf(op, "Ceiling(%v) want %v, got %v %v\n", key, keys[pos], k, ok)
+	}
+
+	if !found {
+		// 最后一个小于key的位置
+		pos--
+	}
+
+	k, v, ok = c.m.Floor(key)
+	if pos < 0 {
+		if ok {
+			c.fatalf(op, "Floor(%v) want not found, got %v\n", key, k)
+		}
+	} else if !ok || k != keys[pos] || v != c.ref[k] {
+		c.fatalf(op, "Floor(%v) want %v, got %v %v\n", key, keys[pos], k, ok)
+	}
+}
+
+// checkLowerHigher 校验Lower、Higher
+func (c *checker) checkLowerHigher(op int, keys []int, key int) {
+	c.t.Helper()
+
+	// 第一个大于key的位置
+	pos, found := slices.BinarySearch(keys, key)
+	if found {
+		pos++
+	}
+
+	k, v, ok := c.m.Higher(key)
+	if pos == len(keys) {
+		if ok {
+			c.fatalf(op, "Higher(%v) want not found, got %v\n", key, k)
+		}
+	} else if !ok || k != keys[pos] || v != c.ref[k] {
+		c.fatalf(op, "Higher(%v) want %v, got %v %v\n", key, keys[pos], k, ok)
+	}
+
+	// 最后一个小于key的位置
+	pos, _ = slices.BinarySearch(keys, key)
+	pos--
+
+	k, v, ok = c.m.Lower(key)
+	if pos < 0 {
+		if ok {
+			c.fatalf(op, "Lower(%v) want not found, got %v\n", key, k)
+		}
+	} else if !ok || k != keys[pos] || v != c.ref[k] {
+		c.fatalf(op, "Lower(%v) want %v, got %v %v\n", key, keys[pos], k, ok)
+	}
+}
+
+// checkRange 校验Range，包括提前结束遍历
+func (c *checker) checkRange(op int, keys []int, lo, hi int) {
+	c.t.Helper()
+
+	i, _ := slices.BinarySearch(keys, lo)
+	for k, v := range c.m.Range(lo, hi) {
+		if i >= len(keys) || keys[i] > hi {
+			c.fatalf(op, "Range(%v, %v) yield extra key %v\n", lo, hi, k)
+		}
+
+		if k != keys[i] || v != c.ref[k] {
+			c.fatalf(op, "Range(%v, %v) want %v, got %v\n", lo, hi, keys[i], k)
+		}
+
+		i++
+	}
+
+	if i < len(keys) && keys[i] <= hi {
+		c.fatalf(op, "Range(%v, %v) missing key %v\n", lo, hi, keys[i])
+	}
+
+	// 提前结束
+	n := 0
+	for range c.m.Range(lo, hi) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+}
+
+// checkSeq 校验seq按顺序返回want中的所有key，want为(位置, key)的序列，包括提前结束遍历
+func (c *checker) checkSeq(op int, name string, seq iter.Seq2[int, int], want iter.Seq2[int, int]) {
+	c.t.Helper()
+
+	next, stop := iter.Pull2(want)
+	defer stop()
+
+	for k, v := range seq {
+		_, key, ok := next()
+		if !ok {
+			c.fatalf(op, "%v yield extra key %v\n", name, k)
+		}
+
+		if k != key || v != c.ref[key] {
+			c.fatalf(op, "%v want %v, got %v\n", name, key, k)
+		}
+	}
+
+	if _, key, ok := next(); ok {
+		c.fatalf(op, "%v missing key %v\n", name, key)
+	}
+
+	// 提前结束
+	for range seq {
+		break
+	}
+}
+
+// checkIterator 正向遍历全部元素，再从随机位置反向遍历到第一个元素
+func (c *checker) checkIterator(op int, keys []int) {
+	c.t.Helper()
+
+	i := 0
+	iter := c.m.Iterator()
+	for iter.Next() {
+		if i >= len(keys) {
+			c.fatalf(op, "Iterator yield extra key %v\n", iter.GetKey())
+		}
+
+		if iter.GetKey() != keys[i] || iter.GetValue() != c.ref[keys[i]] {
+			c.fatalf(op, "Iterator want %v, got %v\n", keys[i], iter.GetKey())
+		}
+
+		i++
+	}
+
+	if i != len(keys) {
+		c.fatalf(op, "Iterator stop at %v, want %v keys\n", i, len(keys))
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	iter = c.m.Iterator()
+	if _, ok := iter.(PrevIterator); !ok {
+		return
+	}
+
+	// 正向走到随机位置pos
+	pos := c.rnd.Intn(len(keys))
+	for j := 0; j <= pos; j++ {
+		iter.Next()
+	}
+
+	prev := iter.(PrevIterator)
+	for j := pos - 1; j >= 0; j-- {
+		if !prev.Prev() {
+			c.fatalf(op, "Iterator Prev stop at %v\n", j)
+		}
+
+		if iter.GetKey() != keys[j] || iter.GetValue() != c.ref[keys[j]] {
+			c.fatalf(op, "Iterator Prev want %v, got %v\n", keys[j], iter.GetKey())
+		}
+	}
+
+	if prev.Prev() {
+		c.fatalf(op, "Iterator Prev yield key %v before the first key\n", iter.GetKey())
+	}
+}
@@ -0,0 +1,38 @@
+package conformance_test
+
+import (
+	"testing"
+
+	"github.com/asinglestep/gods/list/skiplist"
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/map/orderedmap/conformance"
+	"github.com/asinglestep/gods/tree/avltree"
+	btree "github.com/asinglestep/gods/tree/b-tree"
+	"github.com/asinglestep/gods/tree/bptree"
+	"github.com/asinglestep/gods/tree/prbtree"
+	"github.com/asinglestep/gods/tree/rbtree"
+	"github.com/asinglestep/gods/tree/treap"
+)
+
+// signCompare 只保证结果符号的比较函数，有序map不能假设比较结果只有-1、0、1
+func signCompare(a, b int) int {
+	return a - b
+}
+
+func Test_SignComparator(t *testing.T) {
+	backends := map[string]func() orderedmap.OrderedMap[int, int]{
+		"rbtree":   func() orderedmap.OrderedMap[int, int] { return rbtree.NewTreeFunc[int, int](signCompare) },
+		"avltree":  func() orderedmap.OrderedMap[int, int] { return avltree.NewTreeFunc[int, int](signCompare) },
+		"treap":    func() orderedmap.OrderedMap[int, int] { return treap.NewTreeFunc[int, int](signCompare) },
+		"btree":    func() orderedmap.OrderedMap[int, int] { return btree.NewTreeFunc[int, int](3, signCompare) },
+		"bptree":   func() orderedmap.OrderedMap[int, int] { return bptree.NewTreeFunc[int, int](3, signCompare) },
+		"prbtree":  func() orderedmap.OrderedMap[int, int] { return prbtree.NewMap(prbtree.NewTreeFunc[int, int](signCompare)) },
+		"skiplist": func() orderedmap.OrderedMap[int, int] { return skiplist.NewListFunc[int, int](signCompare) },
+	}
+
+	for name, newMap := range backends {
+		t.Run(name, func(t *testing.T) {
+			conformance.Run(t, newMap)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package orderedmap
+
+import (
+	"iter"
+)
+
+// OrderedMap 按key有序的map，rbtree、avltree、treap、btree、bptree、skiplist都实现了该接口
+type OrderedMap[K, V any] interface {
+	// Put 插入key、value，key已存在时更新value
+	Put(key K, val V)
+
+	// Get 查找key对应的value
+	//
+	// @return
+	// 是否找到key
+	Get(key K) (V, bool)
+
+	// Delete 删除key，key不存在时不做任何操作
+	Delete(key K)
+
+	// Len 元素个数
+	Len() int
+
+	// Min 最小的key和对应的value
+	Min() (K, V, bool)
+
+	// Max 最大的key和对应的value
+	Max() (K, V, bool)
+
+	// Floor 小于等于key的最大key和对应的value
+	Floor(key K) (K, V, bool)
+
+	// Ceiling 大于等于key的最小key和对应的value
+	Ceiling(key K) (K, V, bool)
+
+	// Lower 小于key的最大key和对应的value
+	Lower(key K) (K, V, bool)
+
+	// Higher 大于key的最小key和对应的value
+	Higher(key K) (K, V, bool)
+
+	// PopMin 删除最小的key，并返回最小的key和对应的value
+	PopMin() (K, V, bool)
+
+	// PopMax 删除最大的key，并返回最大的key和对应的value
+	PopMax() (K, V, bool)
+
+	// Range 按key升序遍历key在[lo, hi]之间的元素
+	Range(lo, hi K) iter.Seq2[K, V]
+
+	// All 按key升序遍历所有元素
+	All() iter.Seq2[K, V]
+
+	// Backward 按key降序遍历所有元素
+	Backward() iter.Seq2[K, V]
+
+	// Iterator 从最小key开始的迭代器
+	Iterator() Iterator[K, V]
+}
+
+// Iterator 迭代器
+type Iterator[K, V any] interface {
+	// Next 移动到下一个元素，没有下一个元素时返回false
+	Next() bool
+
+	// GetKey 当前元素的key
+	GetKey() K
+
+	// GetValue 当前元素的value
+	GetValue() V
+}
@@ -0,0 +1,165 @@
+package orderedmap_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/asinglestep/gods/list/skiplist"
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/tree/avltree"
+	btree "github.com/asinglestep/gods/tree/b-tree"
+	"github.com/asinglestep/gods/tree/bptree"
+	"github.com/asinglestep/gods/tree/rbtree"
+	"github.com/asinglestep/gods/tree/treap"
+)
+
+var backends = map[string]func() orderedmap.OrderedMap[int, int]{
+	"rbtree":   func() orderedmap.OrderedMap[int, int] { return rbtree.NewOrderedTree[int, int]() },
+	"avltree":  func() orderedmap.OrderedMap[int, int] { return avltree.NewOrderedTree[int, int]() },
+	"treap":    func() orderedmap.OrderedMap[int, int] { return treap.NewOrderedTree[int, int]() },
+	"btree":    func() orderedmap.OrderedMap[int, int] { return btree.NewOrderedTree[int, int](3) },
+	"bptree":   func() orderedmap.OrderedMap[int, int] { return bptree.NewOrderedTree[int, int](3) },
+	"skiplist": func() orderedmap.OrderedMap[int, int] { return skiplist.NewOrderedList[int, int]() },
+}
+
+func Test_OrderedMapEmpty(t *testing.T) {
+	for name, newMap := range backends {
+		m := newMap()
+
+		if m.Len() != 0 {
+			t.Fatalf("%v: want len 0, got %v\n", name, m.Len())
+		}
+
+		if _, ok := m.Get(1); ok {
+			t.Fatalf("%v: Get on empty map found a key\n", name)
+		}
+
+		if _, _, ok := m.Min(); ok {
+			t.Fatalf("%v: Min on empty map found a key\n", name)
+		}
+
+		if _, _, ok := m.Max(); ok {
+			t.Fatalf("%v: Max on empty map found a key\n", name)
+		}
+
+		if _, _, ok := m.Floor(1); ok {
+			t.Fatalf("%v: Floor on empty map found a key\n", name)
+		}
+
+		if _, _, ok := m.Ceiling(1); ok {
+			t.Fatalf("%v: Ceiling on empty map found a key\n", name)
+		}
+
+		for k := range m.Range(0, 10) {
+			t.Fatalf("%v: Range on empty map yield %v\n", name, k)
+		}
+
+		if m.Iterator().Next() {
+			t.Fatalf("%v: Iterator on empty map has next\n", name)
+		}
+
+		m.Delete(1)
+	}
+}
+
+func Test_OrderedMap(t *testing.T) {
+	num := 10000
+
+	for name, newMap := range backends {
+		m := newMap()
+
+		// 只插入偶数key
+		iArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+		for _, v := range iArray {
+			m.Put(v*2, v)
+		}
+
+		// 覆盖已存在的key
+		for _, v := range iArray {
+			m.Put(v*2, -v)
+		}
+
+		if m.Len() != num {
+			t.Fatalf("%v: want len %v, got %v\n", name, num, m.Len())
+		}
+
+		for i := 0; i < num; i++ {
+			if val, ok := m.Get(i * 2); !ok || val != -i {
+				t.Fatalf("%v: Get(%v) want %v, got %v %v\n", name, i*2, -i, val, ok)
+			}
+
+			if _, ok := m.Get(i*2 + 1); ok {
+				t.Fatalf("%v: Get(%v) found a missing key\n", name, i*2+1)
+			}
+		}
+
+		if k, _, ok := m.Min(); !ok || k != 0 {
+			t.Fatalf("%v: Min want 0, got %v %v\n", name, k, ok)
+		}
+
+		if k, _, ok := m.Max(); !ok || k != (num-1)*2 {
+			t.Fatalf("%v: Max want %v, got %v %v\n", name, (num-1)*2, k, ok)
+		}
+
+		for i := 0; i < num; i++ {
+			if k, v, ok := m.Floor(i*2 + 1); !ok || k != i*2 || v != -i {
+				t.Fatalf("%v: Floor(%v) want %v, got %v %v\n", name, i*2+1, i*2, k, ok)
+			}
+
+			if k, _, ok := m.Floor(i * 2); !ok || k != i*2 {
+				t.Fatalf("%v: Floor(%v) want %v, got %v %v\n", name, i*2, i*2, k, ok)
+			}
+
+			if k, _, ok := m.Ceiling(i*2 - 1); !ok || k != i*2 {
+				t.Fatalf("%v: Ceiling(%v) want %v, got %v %v\n", name, i*2-1, i*2, k, ok)
+			}
+		}
+
+		if _, _, ok := m.Floor(-1); ok {
+			t.Fatalf("%v: Floor(-1) found a key\n", name)
+		}
+
+		if _, _, ok := m.Ceiling(num * 2); ok {
+			t.Fatalf("%v: Ceiling(%v) found a key\n", name, num*2)
+		}
+
+		want := 100
+		for k, v := range m.Range(99, 301) {
+			if k != want || v != -want/2 {
+				t.Fatalf("%v: Range want %v, got %v\n", name, want, k)
+			}
+
+			want += 2
+		}
+
+		if want != 302 {
+			t.Fatalf("%v: Range stop at %v\n", name, want)
+		}
+
+		// 删除一半的key
+		dArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num / 2)
+		for _, v := range dArray {
+			m.Delete(v * 2)
+			m.Delete(v*2 + 1)
+		}
+
+		if m.Len() != num-num/2 {
+			t.Fatalf("%v: want len %v, got %v\n", name, num-num/2, m.Len())
+		}
+
+		idx := num / 2
+		iter := m.Iterator()
+		for iter.Next() {
+			if iter.GetKey() != idx*2 || iter.GetValue() != -idx {
+				t.Fatalf("%v: Iterator want %v, got %v\n", name, idx*2, iter.GetKey())
+			}
+
+			idx++
+		}
+
+		if idx != num {
+			t.Fatalf("%v: Iterator stop at %v\n", name, idx)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package orderedmap
+
+import (
+	"iter"
+)
+
+// Snapshot 有序map在某一时刻的只读拷贝，遍历时不需要持有有序map的锁
+type Snapshot[K, V any] struct {
+	keys []K
+	vals []V
+}
+
+// Collect 按顺序拷贝seq中的所有key和value
+func Collect[K, V any](seq iter.Seq2[K, V]) *Snapshot[K, V] {
+	s := &Snapshot[K, V]{}
+	for k, v := range seq {
+		s.keys = append(s.keys, k)
+		s.vals = append(s.vals, v)
+	}
+
+	return s
+}
+
+// Len 元素个数
+func (s *Snapshot[K, V]) Len() int {
+	return len(s.keys)
+}
+
+// All 按拷贝时的顺序遍历所有元素
+func (s *Snapshot[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for i := range s.keys {
+			if !yield(s.keys[i], s.vals[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 按拷贝时的逆序遍历所有元素
+func (s *Snapshot[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for i := len(s.keys) - 1; i >= 0; i-- {
+			if !yield(s.keys[i], s.vals[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Iterator 从第一个元素开始的迭代器
+func (s *Snapshot[K, V]) Iterator() *SnapshotIterator[K, V] {
+	return &SnapshotIterator[K, V]{
+		snapshot: s,
+		idx:      -1,
+	}
+}
+
+// SnapshotIterator Snapshot的迭代器
+type SnapshotIterator[K, V any] struct {
+	snapshot *Snapshot[K, V]
+	idx      int // 当前元素的位置，-1表示在第一个元素之前
+}
+
+// Next 移动到下一个元素，没有下一个元素时返回false
+func (iter *SnapshotIterator[K, V]) Next() bool {
+	if iter.idx < iter.snapshot.Len() {
+		iter.idx++
+	}
+
+	return iter.idx < iter.snapshot.Len()
+}
+
+// Prev 移动到上一个元素，没有上一个元素时返回false
+func (iter *SnapshotIterator[K, V]) Prev() bool {
+	if iter.idx >= 0 {
+		iter.idx--
+	}
+
+	return iter.idx >= 0
+}
+
+// GetKey 当前元素的key
+func (iter *SnapshotIterator[K, V]) GetKey() K {
+	return iter.snapshot.keys[iter.idx]
+}
+
+// GetValue 当前元素的value
+func (iter *SnapshotIterator[K, V]) GetValue() V {
+	return iter.snapshot.vals[iter.idx]
+}
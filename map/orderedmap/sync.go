@@ -0,0 +1,173 @@
+package orderedmap
+
+import (
+	"iter"
+	"sync"
+)
+
+// Sync 并发安全的有序map，使用读写锁保护OrderedMap，读操作可以并行执行
+//
+// Range、All、Backward、Iterator在持有读锁时拷贝元素，遍历拷贝时不持有锁，
+// 遍历过程中可以修改有序map，修改不会反映到正在进行的遍历中
+type Sync[K, V any] struct {
+	mu sync.RWMutex
+	m  OrderedMap[K, V]
+}
+
+// NewSync 创建一个并发安全的有序map，m交给Sync之后不能再直接使用
+func NewSync[K, V any](m OrderedMap[K, V]) *Sync[K, V] {
+	return &Sync[K, V]{
+		m: m,
+	}
+}
+
+// View 持有读锁执行fn，fn中只能读取m
+func (s *Sync[K, V]) View(fn func(m OrderedMap[K, V])) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fn(s.m)
+}
+
+// Update 持有写锁执行fn，可以在fn中原子地执行多个操作
+func (s *Sync[K, V]) Update(fn func(m OrderedMap[K, V])) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(s.m)
+}
+
+// Put 插入key、value，key已存在时更新value
+func (s *Sync[K, V]) Put(key K, val V) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.m.Put(key, val)
+}
+
+// Get 查找key对应的value
+func (s *Sync[K, V]) Get(key K) (V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m.Get(key)
+}
+
+// Delete 删除key，key不存在时不做任何操作
+func (s *Sync[K, V]) Delete(key K) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.m.Delete(key)
+}
+
+// Len 元素个数
+func (s *Sync[K, V]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m.Len()
+}
+
+// Min 最小的key和对应的value
+func (s *Sync[K, V]) Min() (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m.Min()
+}
+
+// Max 最大的key和对应的value
+func (s *Sync[K, V]) Max() (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m.Max()
+}
+
+// Floor 小于等于key的最大key和对应的value
+func (s *Sync[K, V]) Floor(key K) (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m.Floor(key)
+}
+
+// Ceiling 大于等于key的最小key和对应的value
+func (s *Sync[K, V]) Ceiling(key K) (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m.Ceiling(key)
+}
+
+// Lower 小于key的最大key和对应的value
+func (s *Sync[K, V]) Lower(key K) (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m.Lower(key)
+}
+
+// Higher 大于key的最小key和对应的value
+func (s *Sync[K, V]) Higher(key K) (K, V, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.m.Higher(key)
+}
+
+// PopMin 删除最小的key，并返回最小的key和对应的value
+func (s *Sync[K, V]) PopMin() (K, V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.m.PopMin()
+}
+
+// PopMax 删除最大的key，并返回最大的key和对应的value
+func (s *Sync[K, V]) PopMax() (K, V, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.m.PopMax()
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的元素，开始遍历时拷贝元素
+func (s *Sync[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.collect(func() iter.Seq2[K, V] { return s.m.Range(lo, hi) }).All()(yield)
+	}
+}
+
+// All 按key升序遍历所有元素，开始遍历时拷贝元素
+func (s *Sync[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.Snapshot().All()(yield)
+	}
+}
+
+// Backward 按key降序遍历所有元素，开始遍历时拷贝元素
+func (s *Sync[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.Snapshot().Backward()(yield)
+	}
+}
+
+// Iterator 从最小key开始的迭代器，创建迭代器时拷贝元素
+func (s *Sync[K, V]) Iterator() Iterator[K, V] {
+	return s.Snapshot().Iterator()
+}
+
+// Snapshot 拷贝当前所有的元素
+func (s *Sync[K, V]) Snapshot() *Snapshot[K, V] {
+	return s.collect(s.m.All)
+}
+
+// collect 持有读锁拷贝seq返回的序列
+func (s *Sync[K, V]) collect(seq func() iter.Seq2[K, V]) *Snapshot[K, V] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return Collect(seq())
+}
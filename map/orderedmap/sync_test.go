@@ -0,0 +1,62 @@
+package orderedmap_test
+
+import (
+	"testing"
+
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/map/orderedmap/conformance"
+)
+
+func Test_Sync(t *testing.T) {
+	for name, newMap := range backends {
+		t.Run(name, func(t *testing.T) {
+			conformance.RunConcurrent(t, orderedmap.NewSync(newMap()))
+		})
+	}
+}
+
+func Test_SyncUpdate(t *testing.T) {
+	m := orderedmap.NewSync(backends["rbtree"]())
+	for i := 0; i < 10; i++ {
+		m.Put(i, i)
+	}
+
+	// 原子地把最小的key移动到最后
+	m.Update(func(m orderedmap.OrderedMap[int, int]) {
+		k, v, _ := m.PopMin()
+		max, _, _ := m.Max()
+		m.Put(max+k+1, v)
+	})
+
+	snapshot := m.Snapshot()
+	if snapshot.Len() != 10 {
+		t.Fatalf("want len 10, got %v\n", snapshot.Len())
+	}
+
+	// 修改不会影响已经拷贝的元素
+	m.Delete(10)
+
+	want := 10
+	iter := snapshot.Iterator()
+	if iter.Prev() {
+		t.Fatalf("Prev before Next want false\n")
+	}
+
+	for want := 1; iter.Next(); want++ {
+		if iter.GetKey() != want {
+			t.Fatalf("Snapshot want %v, got %v\n", want, iter.GetKey())
+		}
+	}
+
+	for k := range snapshot.Backward() {
+		if k != want {
+			t.Fatalf("Snapshot Backward want %v, got %v\n", want, k)
+		}
+
+		want--
+	}
+
+	if want != 0 {
+		t.Fatalf("Snapshot Backward stop at %v\n", want)
+	}
+}
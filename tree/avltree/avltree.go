@@ -2,12 +2,15 @@ package avltree
 
 import (
 	"bytes"
+	"cmp"
 	"container/list"
 	"fmt"
+	"iter"
 	"os/exec"
 	"runtime"
 
 	dot "github.com/asinglestep/godot"
+	"github.com/asinglestep/gods/map/orderedmap"
 	"github.com/asinglestep/gods/utils"
 )
 
@@ -20,42 +23,64 @@ const (
 )
 
 // Tree Tree
-type Tree struct {
-	root       *TreeNode
-	size       int // 节点数
-	comparator utils.Comparator
+type Tree[K, V any] struct {
+	root       *TreeNode[K, V]
+	sentinel   *TreeNode[K, V] // 哨兵节点
+	size       int             // 节点数
+	comparator func(a, b K) int
+	augmenter  augmenter[K, V] // 聚合值，没有注册时为nil
+	multi      bool            // 是否是multimap模式
 }
 
-// NewTree 创建一个avl树
-func NewTree(comparator utils.Comparator) *Tree {
-	t := &Tree{}
-	t.root = Sentinel
+// NewTree 创建一个key、value为interface{}的avl树
+func NewTree(comparator utils.Comparator, opts ...Option) *Tree[interface{}, interface{}] {
+	return NewTreeFunc[interface{}, interface{}](comparator.Compare, opts...)
+}
+
+// NewTreeFunc 创建一个avl树
+//
+// @param
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// opts: multimap模式等选项
+func NewTreeFunc[K, V any](comparator func(a, b K) int, opts ...Option) *Tree[K, V] {
+	o := newOptions(opts)
+
+	t := &Tree[K, V]{}
+	t.sentinel = newSentinel[K, V]()
+	t.root = t.sentinel
 	t.comparator = comparator
+	t.multi = o.multi
 
 	return t
 }
 
-// Insert 插入一个节点
-func (t *Tree) Insert(key, val interface{}) {
+// NewOrderedTree 创建一个key为有序类型的avl树
+func NewOrderedTree[K cmp.Ordered, V any](opts ...Option) *Tree[K, V] {
+	return NewTreeFunc[K, V](cmp.Compare[K], opts...)
+}
+
+// Insert 插入一个节点，key已存在时不做修改，multimap模式下总是插入新节点
+func (t *Tree[K, V]) Insert(key K, val V) {
 	// 插入新节点
-	newNode := NewTreeNode(utils.NewEntry(key, val))
+	newNode := NewTreeNode(utils.NewTypedEntry(key, val), t.sentinel)
 	t.insertNode(newNode)
 	return
 }
 
 // insertNode 插入一个新节点
-func (t *Tree) insertNode(node *TreeNode) {
+func (t *Tree[K, V]) insertNode(node *TreeNode[K, V]) {
 	next := &t.root
-	var parent *TreeNode
+	var parent *TreeNode[K, V]
 
 	for cur := *next; !cur.isSentinel(); cur = *next {
-		res := t.comparator.Compare(node.GetKey(), cur.GetKey())
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), cur.GetKey())
+		if res == 0 && !t.multi {
 			return
 		}
 
+		// multimap模式下相同的key插入到右子树，保持插入顺序
 		parent = cur
-		if res == utils.Lt {
+		if res < 0 {
 			next = &cur.left
 		} else {
 			next = &cur.right
@@ -64,6 +89,7 @@ func (t *Tree) insertNode(node *TreeNode) {
 
 	*next = node
 	node.parent = parent
+	t.augmentPath(node)
 
 	// 插入修复
 	t.insertFixUp(node)
@@ -71,7 +97,7 @@ func (t *Tree) insertNode(node *TreeNode) {
 }
 
 // insertFixUp 插入修复
-func (t *Tree) insertFixUp(node *TreeNode) {
+func (t *Tree[K, V]) insertFixUp(node *TreeNode[K, V]) {
 	var bRotate bool
 	fixNode := node.parent
 
@@ -92,7 +118,7 @@ func (t *Tree) insertFixUp(node *TreeNode) {
 			bRotate = true
 			// 插入节点 在 修复节点的左子树的左子树上，只需要旋转一次
 			// 插入节点 在 修复节点的左子树的右子树上，需要旋转两次
-			bSingleRorate := t.comparator.Compare(node.GetKey(), fixNode.left.GetKey()) == utils.Lt
+			bSingleRorate := t.comparator(node.GetKey(), fixNode.left.GetKey()) < 0
 			fixNode = t.caseLeft2HigherThanRight(fixNode, bSingleRorate)
 
 		case RIGHT_2_HIGHER_THAN_LEFT:
@@ -100,7 +126,8 @@ func (t *Tree) insertFixUp(node *TreeNode) {
 			bRotate = true
 			// 插入节点 在 修复节点的右子树的右子树上，只需要旋转一次
 			// 插入节点 在 修复节点的右子树的左子树上，需要旋转两次
-			bSingleRorate := t.comparator.Compare(node.GetKey(), fixNode.right.GetKey()) == utils.Gt
+			// multimap模式下和右子节点相同的key也插入在右子树上
+			bSingleRorate := t.comparator(node.GetKey(), fixNode.right.GetKey()) >= 0
 			fixNode = t.caseRight2HigherThanLeft(fixNode, bSingleRorate)
 		}
 
@@ -113,18 +140,23 @@ func (t *Tree) insertFixUp(node *TreeNode) {
 	}
 }
 
-// Delete 删除指定的节点
-func (t *Tree) Delete(key interface{}) {
+// Delete 删除指定的节点，multimap模式下删除key对应的所有节点
+func (t *Tree[K, V]) Delete(key K) {
+	if t.multi {
+		t.DeleteAll(key)
+		return
+	}
+
 	node := t.root
 
 	for !node.isSentinel() {
-		res := t.comparator.Compare(key, node.GetKey())
-		if res == utils.Et {
+		res := t.comparator(key, node.GetKey())
+		if res == 0 {
 			t.deleteNode(node)
 			return
 		}
 
-		if res == utils.Lt {
+		if res < 0 {
 			node = node.left
 		} else {
 			node = node.right
@@ -133,7 +165,7 @@ func (t *Tree) Delete(key interface{}) {
 }
 
 // deleteNode 删除节点
-func (t *Tree) deleteNode(node *TreeNode) {
+func (t *Tree[K, V]) deleteNode(node *TreeNode[K, V]) {
 	hasLeft := !node.left.isSentinel()
 	hasRight := !node.right.isSentinel()
 
@@ -164,9 +196,12 @@ func (t *Tree) deleteNode(node *TreeNode) {
 		children = node.left
 	}
 
-	// 改变删除节点的孩子节点的父节点
-	children.parent = parent
+	// 改变删除节点的孩子节点的父节点，哨兵节点可能被多棵树共享（Split、Join），不修改哨兵节点
+	if !children.isSentinel() {
+		children.parent = parent
+	}
 	t.updateChildren(parent, children, node.isLeft())
+	t.augmentPath(parent)
 
 	// 删除节点
 	node.free()
@@ -175,7 +210,7 @@ func (t *Tree) deleteNode(node *TreeNode) {
 }
 
 // deleteFixUp 删除修复
-func (t *Tree) deleteFixUp(node *TreeNode) {
+func (t *Tree[K, V]) deleteFixUp(node *TreeNode[K, V]) {
 	for node != nil {
 		bHeightChange := false
 		parent := node.parent
@@ -215,18 +250,18 @@ func (t *Tree) deleteFixUp(node *TreeNode) {
 	}
 }
 
-// Search 查找key指定的节点
-func (t *Tree) Search(key interface{}) *TreeNode {
-	return t.lookup(key)
+// Search 查找key指定的节点，multimap模式下返回最早插入的节点
+func (t *Tree[K, V]) Search(key K) *TreeNode[K, V] {
+	return t.lookupFirst(key)
 }
 
 // SearchRange 查找key在[min, max]之间的节点
-func (t *Tree) SearchRange(min, max interface{}) []*TreeNode {
-	list := []*TreeNode{}
+func (t *Tree[K, V]) SearchRange(min, max K) []*TreeNode[K, V] {
+	list := []*TreeNode[K, V]{}
 
 	iter := NewIteratorWithNode(t, t.lookupLowerBoundKey(min))
 	for iter.Next() {
-		if t.comparator.Compare(iter.GetKey(), max) == utils.Gt {
+		if t.comparator(iter.GetKey(), max) > 0 {
 			break
 		}
 
@@ -237,9 +272,9 @@ func (t *Tree) SearchRange(min, max interface{}) []*TreeNode {
 }
 
 // SearchRangeLowerBoundKeyWithLimit 查找大于等于key的limit个节点
-func (t *Tree) SearchRangeLowerBoundKeyWithLimit(key interface{}, limit int64) []*TreeNode {
+func (t *Tree[K, V]) SearchRangeLowerBoundKeyWithLimit(key K, limit int64) []*TreeNode[K, V] {
 	var count int64
-	list := make([]*TreeNode, 0, limit)
+	list := make([]*TreeNode[K, V], 0, limit)
 
 	iter := NewIteratorWithNode(t, t.lookupLowerBoundKey(key))
 	for iter.Next() {
@@ -255,9 +290,9 @@ func (t *Tree) SearchRangeLowerBoundKeyWithLimit(key interface{}, limit int64) [
 }
 
 // SearchRangeUpperBoundKeyWithLimit 找到小于等于key的limit个节点
-func (t *Tree) SearchRangeUpperBoundKeyWithLimit(key interface{}, limit int64) []*TreeNode {
+func (t *Tree[K, V]) SearchRangeUpperBoundKeyWithLimit(key K, limit int64) []*TreeNode[K, V] {
 	var count int64
-	list := make([]*TreeNode, 0, limit)
+	list := make([]*TreeNode[K, V], 0, limit)
 
 	iter := NewIteratorWithNode(t, t.lookupUpperBoundKey(key))
 	for iter.Prev() {
@@ -272,8 +307,126 @@ func (t *Tree) SearchRangeUpperBoundKeyWithLimit(key interface{}, limit int64) [
 	return reverse(list)
 }
 
+// Put 插入key、value，key已存在时更新value，multimap模式下更新最早插入的value
+func (t *Tree[K, V]) Put(key K, val V) {
+	if node := t.lookupFirst(key); node != nil {
+		node.entry.SetValue(val)
+		t.augmentPath(node)
+		return
+	}
+
+	t.Insert(key, val)
+}
+
+// Get 查找key对应的value，multimap模式下返回最早插入的value
+func (t *Tree[K, V]) Get(key K) (val V, bFound bool) {
+	_, val, bFound = t.lookupFirst(key).unpack()
+	return val, bFound
+}
+
+// Len 节点数
+func (t *Tree[K, V]) Len() int {
+	return t.size
+}
+
+// Min 最小的key和对应的value
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	return t.minimum().unpack()
+}
+
+// Max 最大的key和对应的value
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	return t.maximum().unpack()
+}
+
+// Floor 小于等于key的最大key和对应的value
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	return t.lookupUpperBoundKey(key).unpack()
+}
+
+// Ceiling 大于等于key的最小key和对应的value
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	return t.lookupLowerBoundKey(key).unpack()
+}
+
+// Lower 小于key的最大key和对应的value
+func (t *Tree[K, V]) Lower(key K) (K, V, bool) {
+	return t.lookupLess(key).unpack()
+}
+
+// Higher 大于key的最小key和对应的value
+func (t *Tree[K, V]) Higher(key K) (K, V, bool) {
+	return t.lookupGreater(key).unpack()
+}
+
+// PopMin 删除最小的key，并返回最小的key和对应的value
+func (t *Tree[K, V]) PopMin() (key K, val V, bFound bool) {
+	node := t.minimum()
+	if node == nil {
+		return key, val, false
+	}
+
+	key, val, bFound = node.unpack()
+	t.deleteNode(node)
+	return key, val, bFound
+}
+
+// PopMax 删除最大的key，并返回最大的key和对应的value
+func (t *Tree[K, V]) PopMax() (key K, val V, bFound bool) {
+	node := t.maximum()
+	if node == nil {
+		return key, val, false
+	}
+
+	key, val, bFound = node.unpack()
+	t.deleteNode(node)
+	return key, val, bFound
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的节点
+func (t *Tree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := t.lookupLowerBoundKey(lo); node != nil; node = node.next() {
+			if t.comparator(node.GetKey(), hi) > 0 {
+				return
+			}
+
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// All 按key升序遍历所有节点
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := t.minimum(); node != nil; node = node.next() {
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 按key降序遍历所有节点
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := t.maximum(); node != nil; node = node.prev() {
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// Iterator 从最小key开始的迭代器
+func (t *Tree[K, V]) Iterator() orderedmap.Iterator[K, V] {
+	return NewIterator(t)
+}
+
 // String String
-func (t *Tree) String() string {
+func (t *Tree[K, V]) String() string {
 	buffer := bytes.Buffer{}
 	node := t.root
 	stack := list.New()
@@ -285,7 +438,7 @@ func (t *Tree) String() string {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		node = e.(*TreeNode)
+		node = e.(*TreeNode[K, V])
 
 		if node.parent != nil {
 			buffer.WriteString(fmt.Sprintf("节点为: %v, \t节点的高度为: %v, \t父节点为: %v\n", node.GetKey(), node.height, node.parent.GetKey()))
@@ -304,10 +457,10 @@ func (t *Tree) String() string {
 }
 
 // Verify 验证是否是一个avl树
-func (t *Tree) Verify() bool {
+func (t *Tree[K, V]) Verify() bool {
 	node := t.root
 	stack := list.New()
-	keys := make([]interface{}, 0)
+	keys := make([]K, 0)
 
 	for !node.isSentinel() {
 		stack.PushBack(node)
@@ -316,7 +469,7 @@ func (t *Tree) Verify() bool {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		node = e.(*TreeNode)
+		node = e.(*TreeNode[K, V])
 
 		// 如果node的平衡因子大于2或者小于-2
 		bf := node.balanceFactor()
@@ -325,6 +478,21 @@ func (t *Tree) Verify() bool {
 			return false
 		}
 
+		if node.height != node.max()+1 {
+			fmt.Printf("节点[%v]的高度错误\n", node.GetKey())
+			return false
+		}
+
+		if node.size != node.left.count()+node.right.count()+1 {
+			fmt.Printf("节点[%v]的节点数错误\n", node.GetKey())
+			return false
+		}
+
+		if (!node.left.isSentinel() && node.left.parent != node) || (!node.right.isSentinel() && node.right.parent != node) {
+			fmt.Printf("节点[%v]的子节点的父节点错误\n", node.GetKey())
+			return false
+		}
+
 		keys = append(keys, node.GetKey())
 
 		node = node.right
@@ -334,9 +502,9 @@ func (t *Tree) Verify() bool {
 		}
 	}
 
-	// 验证顺序
+	// 验证顺序，multimap模式下允许相同的key
 	for i := 0; i < len(keys)-1; i++ {
-		if t.comparator.Compare(keys[i], keys[i+1]) == utils.Gt {
+		if res := t.comparator(keys[i], keys[i+1]); res > 0 || (res == 0 && !t.multi) {
 			fmt.Printf("Key顺序错误\n")
 			return false
 		}
@@ -351,7 +519,7 @@ func (t *Tree) Verify() bool {
 }
 
 // Dot Dot
-func (t *Tree) Dot() error {
+func (t *Tree[K, V]) Dot() error {
 	node := t.root
 	if node.isSentinel() {
 		return nil
@@ -370,7 +538,7 @@ func (t *Tree) Dot() error {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		node = e.(*TreeNode)
+		node = e.(*TreeNode[K, V])
 
 		dNode, dEdge := node.dot()
 		dGraph.AddNode(dNode)
@@ -410,7 +578,7 @@ func (t *Tree) Dot() error {
 }
 
 // updateChild 更新父节点的子节点
-func (t *Tree) updateChildren(parent, children *TreeNode, isLeft bool) {
+func (t *Tree[K, V]) updateChildren(parent, children *TreeNode[K, V], isLeft bool) {
 	if parent == nil {
 		t.root = children
 	} else if isLeft {
@@ -421,44 +589,90 @@ func (t *Tree) updateChildren(parent, children *TreeNode, isLeft bool) {
 }
 
 // caseLeft2HigherThanRight 左子树比右子树高2
-func (t *Tree) caseLeft2HigherThanRight(node *TreeNode, bSingleRorate bool) *TreeNode {
+func (t *Tree[K, V]) caseLeft2HigherThanRight(node *TreeNode[K, V], bSingleRorate bool) *TreeNode[K, V] {
 	if bSingleRorate {
-		return node.rightRotate()
+		return t.rightRotate(node)
 	}
 
-	return node.leftRightRotate()
+	return t.leftRightRotate(node)
 }
 
 // caseRight2HigherThanLeft 右子树比左子树高2
-func (t *Tree) caseRight2HigherThanLeft(node *TreeNode, bSingleRorate bool) *TreeNode {
+func (t *Tree[K, V]) caseRight2HigherThanLeft(node *TreeNode[K, V], bSingleRorate bool) *TreeNode[K, V] {
 	if bSingleRorate {
-		return node.leftRotate()
+		return t.leftRotate(node)
 	}
 
-	return node.rightLeftRotate()
+	return t.rightLeftRotate(node)
+}
+
+// leftRotate 左旋，并更新旋转后节点的聚合值
+func (t *Tree[K, V]) leftRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	r := node.leftRotate()
+	t.augment(node)
+	t.augment(r)
+
+	return r
+}
+
+// rightRotate 右旋，并更新旋转后节点的聚合值
+func (t *Tree[K, V]) rightRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	l := node.rightRotate()
+	t.augment(node)
+	t.augment(l)
+
+	return l
+}
+
+// leftRightRotate 先左旋再右旋，并更新旋转后节点的聚合值
+func (t *Tree[K, V]) leftRightRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	node.left = t.leftRotate(node.left)
+	return t.rightRotate(node)
+}
+
+// rightLeftRotate 先右旋再左旋，并更新旋转后节点的聚合值
+func (t *Tree[K, V]) rightLeftRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	node.right = t.rightRotate(node.right)
+	return t.leftRotate(node)
+}
+
+// augment 重新计算node的节点数和聚合值
+func (t *Tree[K, V]) augment(node *TreeNode[K, V]) {
+	node.size = node.left.count() + node.right.count() + 1
+
+	if t.augmenter != nil {
+		t.augmenter.update(node)
+	}
+}
+
+// augmentPath 重新计算从node到根节点路径上的节点数和聚合值
+func (t *Tree[K, V]) augmentPath(node *TreeNode[K, V]) {
+	for ; node != nil; node = node.parent {
+		t.augment(node)
+	}
 }
 
 // minimum 中序遍历后，树的最小节点
-func (t *Tree) minimum() *TreeNode {
+func (t *Tree[K, V]) minimum() *TreeNode[K, V] {
 	return t.root.minimum()
 }
 
 // maximum 中序遍历后，树的最大节点
-func (t *Tree) maximum() *TreeNode {
+func (t *Tree[K, V]) maximum() *TreeNode[K, V] {
 	return t.root.maximum()
 }
 
 // lookup 查找key所在的节点
-func (t *Tree) lookup(key interface{}) *TreeNode {
+func (t *Tree[K, V]) lookup(key K) *TreeNode[K, V] {
 	node := t.root
 
 	for !node.isSentinel() {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		if res == 0 {
 			return node
 		}
 
-		if res == utils.Lt {
+		if res < 0 {
 			node = node.right
 		} else {
 			node = node.left
@@ -468,18 +682,23 @@ func (t *Tree) lookup(key interface{}) *TreeNode {
 	return nil
 }
 
-// lookupLowerBoundKey 查找第一个大于等于key的node
-func (t *Tree) lookupLowerBoundKey(key interface{}) *TreeNode {
-	var last *TreeNode
+// lookupLowerBoundKey 查找第一个大于等于key的node，multimap模式下key相同时为最早插入的node
+func (t *Tree[K, V]) lookupLowerBoundKey(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
 	node := t.root
 
+	if node.isSentinel() {
+		return nil
+	}
+
 	for {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		// multimap模式下key相同时继续在左子树中查找最早插入的节点
+		if res == 0 && !t.multi {
 			return node
 		}
 
-		if res == utils.Lt {
+		if res < 0 {
 			if node.right.isSentinel() {
 				return last
 			}
@@ -496,18 +715,23 @@ func (t *Tree) lookupLowerBoundKey(key interface{}) *TreeNode {
 	}
 }
 
-// lookupUpperBoundKey 最后一个小于等于key的node
-func (t *Tree) lookupUpperBoundKey(key interface{}) *TreeNode {
-	var last *TreeNode
+// lookupUpperBoundKey 最后一个小于等于key的node，multimap模式下key相同时为最后插入的node
+func (t *Tree[K, V]) lookupUpperBoundKey(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
 	node := t.root
 
+	if node.isSentinel() {
+		return nil
+	}
+
 	for {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		// multimap模式下key相同时继续在右子树中查找最后插入的节点
+		if res == 0 && !t.multi {
 			return node
 		}
 
-		if res == utils.Lt {
+		if res <= 0 {
 			if node.right.isSentinel() {
 				return node
 			}
@@ -523,3 +747,37 @@ func (t *Tree) lookupUpperBoundKey(key interface{}) *TreeNode {
 		}
 	}
 }
+
+// lookupLess 最后一个小于key的node
+func (t *Tree[K, V]) lookupLess(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
+	node := t.root
+
+	for !node.isSentinel() {
+		if t.comparator(node.GetKey(), key) < 0 {
+			last = node
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+
+	return last
+}
+
+// lookupGreater 第一个大于key的node
+func (t *Tree[K, V]) lookupGreater(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
+	node := t.root
+
+	for !node.isSentinel() {
+		if t.comparator(node.GetKey(), key) > 0 {
+			last = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+
+	return last
+}
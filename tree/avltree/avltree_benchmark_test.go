@@ -4,10 +4,22 @@ import (
 	"math/rand"
 	"testing"
 	"time"
+
+	"github.com/asinglestep/gods/utils"
 )
 
 func Benchmark_AvlTreeRandInsert(b *testing.B) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
+	var num = 10000000
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+
+	for _, v := range array {
+		tree.Insert(v, v)
+	}
+}
+
+func Benchmark_AvlTreeOrderedRandInsert(b *testing.B) {
+	tree := NewOrderedTree[int, int]()
 	var num = 10000000
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
 
@@ -18,7 +30,7 @@ func Benchmark_AvlTreeRandInsert(b *testing.B) {
 
 func Benchmark_AvlTreeRandDelete(b *testing.B) {
 
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -34,7 +46,7 @@ func Benchmark_AvlTreeRandDelete(b *testing.B) {
 }
 
 func Benchmark_AvlTreeRandRangeSearchBack(b *testing.B) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -46,3 +58,46 @@ func Benchmark_AvlTreeRandRangeSearchBack(b *testing.B) {
 	sKey := rand.New(rand.NewSource(time.Now().UnixNano())).Intn(num)
 	tree.SearchRangeLowerBoundKeyWithLimit(sKey, 100000)
 }
+
+func Benchmark_AvlTreeUnion(b *testing.B) {
+	var num = 100000
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	array1, array2 := r.Perm(num * 2)[:num], r.Perm(num * 2)[:num]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		t1, t2 := NewOrderedTree[int, int](), NewOrderedTree[int, int]()
+		for j := range array1 {
+			t1.Insert(array1[j], j)
+			t2.Insert(array2[j], j)
+		}
+		b.StartTimer()
+
+		Union(t1, t2, nil)
+	}
+}
+
+func Benchmark_AvlTreeUnionInsert(b *testing.B) {
+	var num = 100000
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	array1, array2 := r.Perm(num * 2)[:num], r.Perm(num * 2)[:num]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		t1, t2 := NewOrderedTree[int, int](), NewOrderedTree[int, int]()
+		for j := range array1 {
+			t1.Insert(array1[j], j)
+			t2.Insert(array2[j], j)
+		}
+		b.StartTimer()
+
+		// 遍历t2逐个插入t1
+		for k, v := range t2.All() {
+			t1.Insert(k, v)
+		}
+	}
+}
@@ -8,42 +8,48 @@ import (
 )
 
 // TreeNode avl节点
-type TreeNode struct {
-	height int32        // 树得高度
-	entry  *utils.Entry // 数据
-	right  *TreeNode    // 右子节点
-	left   *TreeNode    // 左子节点
-	parent *TreeNode    // 父节点
+type TreeNode[K, V any] struct {
+	height int32                   // 树得高度
+	size   int                     // 以当前节点为根节点的子树的节点数
+	entry  *utils.TypedEntry[K, V] // 数据
+	right  *TreeNode[K, V]         // 右子节点
+	left   *TreeNode[K, V]         // 左子节点
+	parent *TreeNode[K, V]         // 父节点
+	agg    any                     // 以当前节点为根节点的子树的聚合值，没有注册聚合值时为nil
 }
 
-var Sentinel = &TreeNode{
-	entry: nil,
+// newSentinel 新建一个哨兵节点
+func newSentinel[K, V any]() *TreeNode[K, V] {
+	return &TreeNode[K, V]{
+		entry: nil,
+	}
 }
 
-// NewTreeNode 新建一个节点
-func NewTreeNode(entry *utils.Entry) *TreeNode {
-	node := &TreeNode{
+// NewTreeNode 新建一个节点，sentinel为节点所在树的哨兵节点
+func NewTreeNode[K, V any](entry *utils.TypedEntry[K, V], sentinel *TreeNode[K, V]) *TreeNode[K, V] {
+	node := &TreeNode[K, V]{
 		entry:  entry,
 		height: 1,
-		right:  Sentinel,
-		left:   Sentinel,
+		size:   1,
+		right:  sentinel,
+		left:   sentinel,
 	}
 
 	return node
 }
 
 // GetKey 获取key
-func (node *TreeNode) GetKey() interface{} {
+func (node *TreeNode[K, V]) GetKey() K {
 	return node.entry.GetKey()
 }
 
 // GetValue 获取value
-func (node *TreeNode) GetValue() interface{} {
+func (node *TreeNode[K, V]) GetValue() V {
 	return node.entry.GetValue()
 }
 
 // rightRotate 右旋
-func (node *TreeNode) rightRotate() *TreeNode {
+func (node *TreeNode[K, V]) rightRotate() *TreeNode[K, V] {
 	l := node.left
 	lr := l.right
 
@@ -62,7 +68,7 @@ func (node *TreeNode) rightRotate() *TreeNode {
 }
 
 // leftRotate 左旋
-func (node *TreeNode) leftRotate() *TreeNode {
+func (node *TreeNode[K, V]) leftRotate() *TreeNode[K, V] {
 	r := node.right
 	rl := r.left
 
@@ -80,20 +86,8 @@ func (node *TreeNode) leftRotate() *TreeNode {
 	return r
 }
 
-// leftRightRotate 先左旋再右旋
-func (node *TreeNode) leftRightRotate() *TreeNode {
-	node.left = node.left.leftRotate()
-	return node.rightRotate()
-}
-
-// rightLeftRotate 先右旋再左旋
-func (node *TreeNode) rightLeftRotate() *TreeNode {
-	node.right = node.right.rightRotate()
-	return node.leftRotate()
-}
-
 // findSuccessor 找到node的后继节点
-func (node *TreeNode) findSuccessor() *TreeNode {
+func (node *TreeNode[K, V]) findSuccessor() *TreeNode[K, V] {
 	node = node.right
 
 	for {
@@ -106,7 +100,7 @@ func (node *TreeNode) findSuccessor() *TreeNode {
 }
 
 // findPrecursor 找到前驱节点
-func (node *TreeNode) findPrecursor() *TreeNode {
+func (node *TreeNode[K, V]) findPrecursor() *TreeNode[K, V] {
 	node = node.left
 
 	for {
@@ -119,7 +113,7 @@ func (node *TreeNode) findPrecursor() *TreeNode {
 }
 
 // balanceFactor node的平衡因子
-func (node *TreeNode) balanceFactor() int32 {
+func (node *TreeNode[K, V]) balanceFactor() int32 {
 	var lh, rh int32
 
 	if !node.left.isSentinel() {
@@ -134,7 +128,7 @@ func (node *TreeNode) balanceFactor() int32 {
 }
 
 // Height 返回树的高度
-func (node *TreeNode) high() int32 {
+func (node *TreeNode[K, V]) high() int32 {
 	if node.isSentinel() {
 		return 0
 	}
@@ -143,7 +137,7 @@ func (node *TreeNode) high() int32 {
 }
 
 // max 取左右子树的最大高度
-func (node *TreeNode) max() int32 {
+func (node *TreeNode[K, V]) max() int32 {
 	var lh, rh int32
 
 	if !node.left.isSentinel() {
@@ -161,13 +155,22 @@ func (node *TreeNode) max() int32 {
 	return rh
 }
 
+// count 以当前节点为根节点的子树的节点数，哨兵节点返回0
+func (node *TreeNode[K, V]) count() int {
+	if node.isSentinel() {
+		return 0
+	}
+
+	return node.size
+}
+
 // isSentinel 是否是哨兵节点
-func (node *TreeNode) isSentinel() bool {
+func (node *TreeNode[K, V]) isSentinel() bool {
 	return node.entry == nil
 }
 
 // isLeft 是否是左节点
-func (node *TreeNode) isLeft() bool {
+func (node *TreeNode[K, V]) isLeft() bool {
 	// node为根节点
 	if node.parent == nil {
 		return false
@@ -177,7 +180,7 @@ func (node *TreeNode) isLeft() bool {
 }
 
 // isRight 是否是右节点
-func (node *TreeNode) isRight() bool {
+func (node *TreeNode[K, V]) isRight() bool {
 	// node为根节点
 	if node.parent == nil {
 		return false
@@ -187,15 +190,24 @@ func (node *TreeNode) isRight() bool {
 }
 
 // free free
-func (node *TreeNode) free() {
+func (node *TreeNode[K, V]) free() {
 	node.parent = nil
 	node.left = nil
 	node.right = nil
 	node.entry = nil
 }
 
+// unpack 获取节点的key和value，node为nil时返回false
+func (node *TreeNode[K, V]) unpack() (key K, val V, bFound bool) {
+	if node == nil {
+		return key, val, false
+	}
+
+	return node.GetKey(), node.GetValue(), true
+}
+
 // minimum 以当前节点为根节点，中序遍历后，树的最小节点
-func (node *TreeNode) minimum() *TreeNode {
+func (node *TreeNode[K, V]) minimum() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -208,7 +220,7 @@ func (node *TreeNode) minimum() *TreeNode {
 }
 
 // maximum 以当前节点为根节点，中序遍历后，树的最大节点
-func (node *TreeNode) maximum() *TreeNode {
+func (node *TreeNode[K, V]) maximum() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -221,7 +233,7 @@ func (node *TreeNode) maximum() *TreeNode {
 }
 
 // next 中序遍历node的下一个节点
-func (node *TreeNode) next() *TreeNode {
+func (node *TreeNode[K, V]) next() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -241,7 +253,7 @@ func (node *TreeNode) next() *TreeNode {
 }
 
 // prev 中序遍历node的上一个节点
-func (node *TreeNode) prev() *TreeNode {
+func (node *TreeNode[K, V]) prev() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -261,18 +273,18 @@ func (node *TreeNode) prev() *TreeNode {
 }
 
 // dot dot
-func (node *TreeNode) dot() (dNode *dot.Node, dEdge *dot.Edge) {
+func (node *TreeNode[K, V]) dot() (dNode *dot.Node, dEdge *dot.Edge) {
 	// 添加node
 	dNode = &dot.Node{}
-	dNode.Name = fmt.Sprintf("%d", node.GetKey())
+	dNode.Name = fmt.Sprintf("%v", node.GetKey())
 	dNode.Attr = map[string]string{
-		"label": fmt.Sprintf("\"<f0> | %d | <f1> \"", node.GetKey()),
+		"label": fmt.Sprintf("\"<f0> | %v | <f1> \"", node.GetKey()),
 	}
 
 	// 添加edge
 	if node.parent != nil {
 		dEdge = &dot.Edge{}
-		dEdge.Src = fmt.Sprintf("%d", node.parent.GetKey())
+		dEdge.Src = fmt.Sprintf("%v", node.parent.GetKey())
 
 		if node.isLeft() {
 			dEdge.SrcPort = ":f0"
@@ -280,14 +292,14 @@ func (node *TreeNode) dot() (dNode *dot.Node, dEdge *dot.Edge) {
 			dEdge.SrcPort = ":f1"
 		}
 
-		dEdge.Dst = fmt.Sprintf("%d", node.GetKey())
+		dEdge.Dst = fmt.Sprintf("%v", node.GetKey())
 	}
 
 	return dNode, dEdge
 }
 
 // reverse 倒序
-func reverse(list []*TreeNode) []*TreeNode {
+func reverse[K, V any](list []*TreeNode[K, V]) []*TreeNode[K, V] {
 	listLen := len(list)
 
 	for i := 0; i < listLen/2; i++ {
@@ -1,33 +1,24 @@
 package avltree
 
 import (
+	"cmp"
 	"fmt"
+	"iter"
+	"math/bits"
 	"math/rand"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
-)
-
-type avltreeComparator struct {
-}
-
-// Compare Compare
-func (ac avltreeComparator) Compare(k1, k2 interface{}) int {
-	i1 := k1.(int)
-	i2 := k2.(int)
-
-	if i1 > i2 {
-		return 1
-	}
-
-	if i1 < i2 {
-		return -1
-	}
 
-	return 0
-}
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/map/orderedmap/conformance"
+	"github.com/asinglestep/gods/utils"
+)
 
 func Test_AvlTreeInsert(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	fmt.Println("插入20")
 	tree.Insert(20, 20)
@@ -92,7 +83,7 @@ func Test_AvlTreeInsert(t *testing.T) {
 }
 
 func Test_AvlTreeRandInsert(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -125,7 +116,7 @@ func Test_AvlTreeRandInsert(t *testing.T) {
 }
 
 func Test_AvlTreeDelete(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(20, 20)
 	tree.Insert(40, 40)
@@ -141,7 +132,7 @@ func Test_AvlTreeDelete(t *testing.T) {
 }
 
 func Test_AvlTreeRandDelete(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -182,7 +173,7 @@ func Test_AvlTreeRandDelete(t *testing.T) {
 }
 
 func Test_AvlTreeRandSearch(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -205,7 +196,7 @@ func Test_AvlTreeRandSearch(t *testing.T) {
 }
 
 func Test_AvlTreeSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(20, 20)
 	tree.Insert(40, 40)
@@ -236,7 +227,7 @@ func Test_AvlTreeSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_AvlTreeRandSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -261,7 +252,7 @@ func Test_AvlTreeRandSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_AvlTreeSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(20, 20)
 	tree.Insert(40, 40)
@@ -291,7 +282,7 @@ func Test_AvlTreeSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_AvlTreeRandSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -316,7 +307,7 @@ func Test_AvlTreeRandSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_AvlTreeSearchRange(t *testing.T) {
-	tree := NewTree(avltreeComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(20, 20)
 	tree.Insert(40, 40)
@@ -344,3 +335,570 @@ func Test_AvlTreeSearchRange(t *testing.T) {
 		t.Fatalf("Test_AvlTreeSearchRange err: len(nodeList) != len(verifArr), len(nodeList): %v, len(verifArr): %v\n", len(nodeList), len(verifArr))
 	}
 }
+
+func Test_AvlTreeOrderedTree(t *testing.T) {
+	tree := NewOrderedTree[int, string]()
+	var num = 100000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+	for _, v := range array {
+		tree.Insert(v, strconv.Itoa(v))
+	}
+
+	dArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num / 2)
+	for _, v := range dArray {
+		tree.Delete(v)
+	}
+
+	if !tree.Verify() {
+		t.Fatal("Test_AvlTreeOrderedTree err")
+	}
+
+	idx := num / 2
+	iter := NewIterator(tree)
+	for iter.Next() {
+		if iter.GetKey() != idx || iter.GetValue() != strconv.Itoa(idx) {
+			t.Fatalf("want %v, got %v: %v\n", idx, iter.GetKey(), iter.GetValue())
+		}
+
+		idx++
+	}
+
+	if idx != num {
+		t.Fatalf("want %v, got %v\n", num, idx)
+	}
+}
+
+func Test_AvlTreeInsertExisting(t *testing.T) {
+	tree := NewOrderedTree[int, string]()
+	tree.Insert(1, "a")
+
+	// Insert不修改已存在的key
+	tree.Insert(1, "b")
+	if v, ok := tree.Get(1); !ok || v != "a" || tree.Len() != 1 {
+		t.Fatalf("Insert want a, got %v %v, len %v\n", v, ok, tree.Len())
+	}
+
+	// Put更新已存在的key
+	tree.Put(1, "c")
+	if v, ok := tree.Get(1); !ok || v != "c" || tree.Len() != 1 {
+		t.Fatalf("Put want c, got %v %v, len %v\n", v, ok, tree.Len())
+	}
+
+	tree.Put(2, "d")
+	if v, ok := tree.Get(2); !ok || v != "d" || tree.Len() != 2 || !tree.Verify() {
+		t.Fatalf("Put want d, got %v %v, len %v\n", v, ok, tree.Len())
+	}
+}
+
+func Test_AvlTreeConformance(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewOrderedTree[int, int]()
+	})
+}
+
+// sumAgg 测试用的聚合值，first为区间中最小的key，用来检查合并的顺序
+type sumAgg struct {
+	sum   int
+	cnt   int
+	first int
+}
+
+func Test_AvlTreeAugment(t *testing.T) {
+	tree := NewOrderedTree[int, int]()
+	num := 2000
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	// 注册前插入的节点
+	m := make(map[int]int)
+	for _, v := range r.Perm(num)[:num/4] {
+		tree.Insert(v, v)
+		m[v] = v
+	}
+
+	aug := Augment(tree, Augmentation[int, int, sumAgg]{
+		Value: func(key, val int) sumAgg {
+			return sumAgg{sum: val, cnt: 1, first: key}
+		},
+		Combine: func(a, b sumAgg) sumAgg {
+			first := a.first
+			if a.cnt == 0 {
+				first = b.first
+			}
+
+			return sumAgg{sum: a.sum + b.sum, cnt: a.cnt + b.cnt, first: first}
+		},
+	})
+
+	for i := 0; i < num*4; i++ {
+		k := r.Intn(num)
+		if r.Intn(3) == 0 {
+			tree.Delete(k)
+			delete(m, k)
+		} else {
+			tree.Put(k, i)
+			m[k] = i
+		}
+	}
+
+	if !tree.Verify() {
+		t.Fatalf("Verify Error\n")
+	}
+
+	for i := 0; i < 1000; i++ {
+		lo := r.Intn(num+2) - 1
+		hi := r.Intn(num+2) - 1
+
+		want := sumAgg{}
+		for k := hi; k >= lo; k-- {
+			if v, ok := m[k]; ok {
+				want.sum += v
+				want.cnt++
+				want.first = k
+			}
+		}
+
+		if got := aug.Query(lo, hi); got != want {
+			t.Fatalf("Query(%v, %v) want %+v, got %+v\n", lo, hi, want, got)
+		}
+	}
+
+	if got := aug.Total(); got.cnt != len(m) {
+		t.Fatalf("Total want %v, got %+v\n", len(m), got)
+	}
+
+	if got := aug.Aggregate(nil); got != (sumAgg{}) {
+		t.Fatalf("Aggregate(nil) want Identity, got %+v\n", got)
+	}
+}
+
+func Test_AvlTreeConcurrentTrees(t *testing.T) {
+	// 每棵树有自己的哨兵节点，不同goroutine中操作不同的树不会产生数据竞争，使用go test -race验证
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+
+			tree := NewOrderedTree[int, int]()
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 2000; j++ {
+				tree.Insert(r.Intn(500), j)
+				tree.Delete(r.Intn(500))
+			}
+
+			if !tree.Verify() {
+				t.Errorf("seed %v: Verify Error\n", seed)
+			}
+		}(int64(i))
+	}
+
+	wg.Wait()
+}
+
+func Test_AvlTreeSplitJoin(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	for round := 0; round < 50; round++ {
+		num := r.Intn(2000)
+		tree := NewOrderedTree[int, int]()
+		for _, v := range r.Perm(num) {
+			tree.Insert(v*2, v)
+		}
+
+		// 拆分点可能在所有key之前、之后或者不存在于树中
+		key := r.Intn(num*2+3) - 1
+		left, right := tree.Split(key)
+		if tree.Len() != 0 || !tree.Verify() {
+			t.Fatalf("seed %v: tree should be empty after Split, got %v\n", seed, tree.Len())
+		}
+
+		if !left.Verify() || !right.Verify() {
+			t.Fatalf("seed %v: Split(%v) Verify Error\n", seed, key)
+		}
+
+		if left.Len()+right.Len() != num {
+			t.Fatalf("seed %v: Split(%v) want %v keys, got %v + %v\n", seed, key, num, left.Len(), right.Len())
+		}
+
+		for k := range left.All() {
+			if k >= key {
+				t.Fatalf("seed %v: Split(%v) left has key %v\n", seed, key, k)
+			}
+		}
+
+		for k := range right.All() {
+			if k < key {
+				t.Fatalf("seed %v: Split(%v) right has key %v\n", seed, key, k)
+			}
+		}
+
+		// 拆分后的树可以继续插入、删除
+		left.Insert(-2, -1)
+		right.Delete(key)
+
+		joined, ok := Join(left, right)
+		if !ok {
+			t.Fatalf("seed %v: Join want ok\n", seed)
+		}
+
+		if !joined.Verify() || left.Len() != 0 || right.Len() != 0 {
+			t.Fatalf("seed %v: Join Verify Error\n", seed)
+		}
+
+		last := -3
+		for k, v := range joined.All() {
+			if k <= last || (k >= 0 && k != v*2) {
+				t.Fatalf("seed %v: Join got key %v value %v after %v\n", seed, k, v, last)
+			}
+
+			last = k
+		}
+	}
+}
+
+func Test_AvlTreeJoinOverlap(t *testing.T) {
+	left, right := NewOrderedTree[int, int](), NewOrderedTree[int, int]()
+	for i := 0; i < 10; i++ {
+		left.Insert(i, i)
+		right.Insert(i+9, i+9)
+	}
+
+	if _, ok := Join(left, right); ok {
+		t.Fatalf("Join overlapping trees want false\n")
+	}
+
+	if left.Len() != 10 || right.Len() != 10 || !left.Verify() || !right.Verify() {
+		t.Fatalf("Join overlapping trees should not change the trees\n")
+	}
+
+	right.Delete(9)
+	joined, ok := Join(left, right)
+	if !ok || joined.Len() != 19 || !joined.Verify() {
+		t.Fatalf("Join want 19 keys, got %v %v\n", joined.Len(), ok)
+	}
+
+	empty := NewOrderedTree[int, int]()
+	if joined, ok = Join(joined, empty); !ok || joined.Len() != 19 || !joined.Verify() {
+		t.Fatalf("Join with empty tree want 19 keys, got %v %v\n", joined.Len(), ok)
+	}
+}
+
+func Test_AvlTreeSplitConcurrent(t *testing.T) {
+	// 拆分后的两棵树共享原来的哨兵节点，在不同goroutine中操作不会产生数据竞争，使用go test -race验证
+	tree := NewOrderedTree[int, int]()
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+
+	left, right := tree.Split(500)
+
+	var wg sync.WaitGroup
+	for i, part := range []*Tree[int, int]{left, right} {
+		wg.Add(1)
+		go func(seed int64, part *Tree[int, int]) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 2000; j++ {
+				part.Insert(int(seed)*500+r.Intn(500), j)
+				part.Delete(int(seed)*500 + r.Intn(500))
+			}
+
+			if !part.Verify() {
+				t.Errorf("seed %v: Verify Error\n", seed)
+			}
+		}(int64(i), part)
+	}
+
+	wg.Wait()
+}
+
+func Test_AvlTreeSetOps(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	// build 随机生成一棵树和对应的map
+	build := func(num, max int) (*Tree[int, int], map[int]int) {
+		tree := NewOrderedTree[int, int]()
+		m := map[int]int{}
+		for i := 0; i < num; i++ {
+			key := r.Intn(max)
+			tree.Insert(key, key*10+1)
+			m[key] = key*10 + 1
+		}
+
+		return tree, m
+	}
+
+	check := func(name string, tree *Tree[int, int], want map[int]int) {
+		if !tree.Verify() || tree.Len() != len(want) {
+			t.Fatalf("seed %v: %v want %v keys, got %v\n", seed, name, len(want), tree.Len())
+		}
+
+		for k, v := range tree.All() {
+			if w, ok := want[k]; !ok || w != v {
+				t.Fatalf("seed %v: %v key %v want %v %v, got %v\n", seed, name, k, w, ok, v)
+			}
+		}
+	}
+
+	// 节点数较多时会并行执行，使用go test -race验证
+	for _, size := range [][2]int{{0, 10}, {10, 0}, {100, 100}, {1000, 10}, {10, 1000}, {20000, 5000}} {
+		max := (size[0] + size[1]) * 2
+
+		t1, m1 := build(size[0], max)
+		t2, m2 := build(size[1], max)
+		for k := range m2 {
+			t2.Put(k, 2)
+			m2[k] = 2
+		}
+
+		want := map[int]int{}
+		for k, v := range m1 {
+			want[k] = v
+		}
+
+		for k, v := range m2 {
+			want[k] += v
+		}
+
+		union := Union(t1, t2, func(key, v1, v2 int) int { return v1 + v2 })
+		check("Union", union, want)
+		if t1.Len() != 0 || t2.Len() != 0 {
+			t.Fatalf("seed %v: t1, t2 should be empty after Union\n", seed)
+		}
+
+		t1, m1 = build(size[0], max)
+		t2, m2 = build(size[1], max)
+		want = map[int]int{}
+		for k, v := range m1 {
+			if _, ok := m2[k]; ok {
+				want[k] = v
+			}
+		}
+
+		check("Intersection", Intersection(t1, t2), want)
+
+		t1, m1 = build(size[0], max)
+		t2, m2 = build(size[1], max)
+		want = map[int]int{}
+		for k, v := range m1 {
+			if _, ok := m2[k]; !ok {
+				want[k] = v
+			}
+		}
+
+		check("Difference", Difference(t1, t2), want)
+	}
+}
+
+func Test_AvlTreeMultimap(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	tree := NewOrderedTree[int, int](WithMultimap())
+	want := map[int][]int{}
+	size := 0
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(100)
+
+		switch op := r.Intn(10); {
+		case op < 6:
+			tree.Insert(key, i)
+			want[key] = append(want[key], i)
+			size++
+		case op < 9:
+			if tree.DeleteOne(key) != (len(want[key]) > 0) {
+				t.Fatalf("seed %v: DeleteOne(%v) want %v\n", seed, key, len(want[key]) > 0)
+			}
+
+			if len(want[key]) > 0 {
+				want[key] = want[key][1:]
+				size--
+			}
+		default:
+			if n := tree.DeleteAll(key); n != len(want[key]) {
+				t.Fatalf("seed %v: DeleteAll(%v) want %v, got %v\n", seed, key, len(want[key]), n)
+			}
+
+			size -= len(want[key])
+			delete(want, key)
+		}
+
+		if tree.Count(key) != len(want[key]) {
+			t.Fatalf("seed %v: Count(%v) want %v, got %v\n", seed, key, len(want[key]), tree.Count(key))
+		}
+
+		if fmt.Sprint(tree.GetAll(key)) != fmt.Sprint(want[key]) {
+			t.Fatalf("seed %v: GetAll(%v) want %v, got %v\n", seed, key, want[key], tree.GetAll(key))
+		}
+
+		if val, ok := tree.Get(key); ok != (len(want[key]) > 0) || (ok && val != want[key][0]) {
+			t.Fatalf("seed %v: Get(%v) want first value %v, got %v\n", seed, key, want[key], val)
+		}
+	}
+
+	if !tree.Verify() || tree.Len() != size {
+		t.Fatalf("seed %v: Multimap Verify Error, want %v nodes, got %v\n", seed, size, tree.Len())
+	}
+
+	// 相同key的value按插入顺序排列
+	got := map[int][]int{}
+	for k, v := range tree.All() {
+		got[k] = append(got[k], v)
+	}
+
+	for k, vals := range want {
+		if len(vals) > 0 && fmt.Sprint(got[k]) != fmt.Sprint(vals) {
+			t.Fatalf("seed %v: All key %v want %v, got %v\n", seed, k, vals, got[k])
+		}
+	}
+
+	// Put更新最早插入的value，Delete删除所有value
+	tree.Insert(1000, 1)
+	tree.Insert(1000, 2)
+	tree.Put(1000, 3)
+	if fmt.Sprint(tree.GetAll(1000)) != "[3 2]" {
+		t.Fatalf("Put want [3 2], got %v\n", tree.GetAll(1000))
+	}
+
+	tree.Delete(1000)
+	if tree.Count(1000) != 0 || !tree.Verify() {
+		t.Fatalf("Delete want no value, got %v\n", tree.GetAll(1000))
+	}
+}
+
+func Test_AvlTreeMultimapRange(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	// key 0~9，每个key有55个value，value按插入顺序排列
+	tree := NewOrderedTree[int, int](WithMultimap())
+	want := map[int][]int{}
+	for _, i := range r.Perm(550) {
+		tree.Insert(i%10, i)
+		want[i%10] = append(want[i%10], i)
+	}
+
+	values := func(seq iter.Seq2[int, int]) []int {
+		res := []int{}
+		for _, v := range seq {
+			res = append(res, v)
+		}
+
+		return res
+	}
+
+	nodeValues := func(list []*TreeNode[int, int]) []int {
+		res := []int{}
+		for _, node := range list {
+			res = append(res, node.GetValue())
+		}
+
+		return res
+	}
+
+	if got := values(tree.Range(5, 5)); !slices.Equal(got, want[5]) {
+		t.Fatalf("seed %v: Range(5, 5) want %v, got %v\n", seed, want[5], got)
+	}
+
+	if got, all := values(tree.Range(3, 6)), slices.Concat(want[3], want[4], want[5], want[6]); !slices.Equal(got, all) {
+		t.Fatalf("seed %v: Range(3, 6) want %v, got %v\n", seed, all, got)
+	}
+
+	if got := nodeValues(tree.SearchRange(5, 5)); !slices.Equal(got, want[5]) {
+		t.Fatalf("seed %v: SearchRange(5, 5) want %v, got %v\n", seed, want[5], got)
+	}
+
+	if got := nodeValues(tree.SearchRangeLowerBoundKeyWithLimit(5, 3)); !slices.Equal(got, want[5][:3]) {
+		t.Fatalf("seed %v: SearchRangeLowerBoundKeyWithLimit(5, 3) want %v, got %v\n", seed, want[5][:3], got)
+	}
+
+	if got := nodeValues(tree.SearchRangeUpperBoundKeyWithLimit(5, 3)); !slices.Equal(got, want[5][52:]) {
+		t.Fatalf("seed %v: SearchRangeUpperBoundKeyWithLimit(5, 3) want %v, got %v\n", seed, want[5][52:], got)
+	}
+
+	// Ceiling为相同key中最早插入的value，Floor为最后插入的value
+	if k, v, ok := tree.Ceiling(5); !ok || k != 5 || v != want[5][0] {
+		t.Fatalf("seed %v: Ceiling(5) want 5: %v, got %v: %v %v\n", seed, want[5][0], k, v, ok)
+	}
+
+	if k, v, ok := tree.Floor(5); !ok || k != 5 || v != want[5][54] {
+		t.Fatalf("seed %v: Floor(5) want 5: %v, got %v: %v %v\n", seed, want[5][54], k, v, ok)
+	}
+
+	tree.DeleteAll(5)
+	if k, v, ok := tree.Ceiling(5); !ok || k != 6 || v != want[6][0] {
+		t.Fatalf("seed %v: Ceiling(5) want 6: %v, got %v: %v %v\n", seed, want[6][0], k, v, ok)
+	}
+
+	if k, v, ok := tree.Floor(5); !ok || k != 4 || v != want[4][54] {
+		t.Fatalf("seed %v: Floor(5) want 4: %v, got %v: %v %v\n", seed, want[4][54], k, v, ok)
+	}
+}
+
+func Test_AvlTreeBuildFromSorted(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 100, 1023, 1024, 5000} {
+		vals := rand.Perm(n)
+		tree, ok := BuildFromSorted(cmp.Compare[int], slices.All(vals))
+		if !ok || !tree.Verify() || tree.Len() != n {
+			t.Fatalf("n %v: BuildFromSorted Verify Error, got %v nodes\n", n, tree.Len())
+		}
+
+		// 高度不超过完全二叉树的高度
+		if h := int(tree.root.height); n > 0 && h != bits.Len(uint(n)) {
+			t.Fatalf("n %v: want height %v, got %v\n", n, bits.Len(uint(n)), h)
+		}
+
+		idx := 0
+		for k, v := range tree.All() {
+			if k != idx || v != vals[idx] {
+				t.Fatalf("n %v: want %v %v, got %v %v\n", n, idx, vals[idx], k, v)
+			}
+
+			idx++
+		}
+
+		// 创建的树可以继续插入、删除
+		for i := 0; i < n; i += 2 {
+			tree.Delete(i)
+			tree.Insert(n+i, i)
+		}
+
+		if !tree.Verify() || tree.Len() != n {
+			t.Fatalf("n %v: Verify Error after Insert and Delete\n", n)
+		}
+	}
+
+	unsorted := func(yield func(int, int) bool) {
+		for _, k := range []int{1, 3, 2} {
+			if !yield(k, k) {
+				return
+			}
+		}
+	}
+
+	if _, ok := BuildFromSorted(cmp.Compare[int], unsorted); ok {
+		t.Fatalf("unsorted input should return false\n")
+	}
+
+	duplicate := slices.All([]int{0, 0, 1})
+	if _, ok := BuildFromSorted(func(a, b int) int { return cmp.Compare(a/2, b/2) }, duplicate); ok {
+		t.Fatalf("duplicate keys should return false\n")
+	}
+
+	tree, ok := BuildFromSorted(func(a, b int) int { return cmp.Compare(a/2, b/2) }, duplicate, WithMultimap())
+	if !ok || !tree.Verify() || tree.Count(0) != 2 {
+		t.Fatalf("multimap BuildFromSorted Error\n")
+	}
+}
+
+func Benchmark_AvlTreeBuildFromSorted(b *testing.B) {
+	vals := make([]int, 100000)
+	for i := 0; i < b.N; i++ {
+		BuildFromSorted(cmp.Compare[int], slices.All(vals))
+	}
+}
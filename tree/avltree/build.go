@@ -0,0 +1,54 @@
+package avltree
+
+import (
+	"iter"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// BuildFromSorted 由按key升序排列的seq创建avl树，时间复杂度O(n)
+//
+// @param
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// seq: 按key升序排列的key、value，key不能重复，multimap模式下允许相同的key
+// opts: multimap模式等选项
+//
+// @return
+// seq没有按key升序排列时返回false
+func BuildFromSorted[K, V any](comparator func(a, b K) int, seq iter.Seq2[K, V], opts ...Option) (*Tree[K, V], bool) {
+	t := NewTreeFunc[K, V](comparator, opts...)
+
+	var nodes []*TreeNode[K, V]
+	for key, val := range seq {
+		if len(nodes) > 0 && !t.sorted(nodes[len(nodes)-1].GetKey(), key) {
+			return nil, false
+		}
+
+		nodes = append(nodes, NewTreeNode(utils.NewTypedEntry(key, val), t.sentinel))
+	}
+
+	t.root = t.build(nodes)
+	t.size = len(nodes)
+
+	return t, true
+}
+
+// build 以nodes中间的节点为根节点创建子树，返回子树的根节点
+//
+// 左右子树的节点数最多相差1，所以高度最多相差1
+func (t *Tree[K, V]) build(nodes []*TreeNode[K, V]) *TreeNode[K, V] {
+	if len(nodes) == 0 {
+		return t.sentinel
+	}
+
+	mid := len(nodes) / 2
+	t.link(nodes[mid], t.build(nodes[:mid]), t.build(nodes[mid+1:]))
+
+	return nodes[mid]
+}
+
+// sorted key是否可以排在prev之后，multimap模式下允许相同的key
+func (t *Tree[K, V]) sorted(prev, key K) bool {
+	res := t.comparator(prev, key)
+	return res < 0 || (res == 0 && t.multi)
+}
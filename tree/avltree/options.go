@@ -0,0 +1,27 @@
+package avltree
+
+// Option 创建avl树的选项
+type Option func(o *options)
+
+// options avl树的选项
+type options struct {
+	multi bool // 是否允许重复的key
+}
+
+// newOptions 默认选项：key不能重复
+func newOptions(opts []Option) *options {
+	o := &options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithMultimap 使用multimap模式，Insert总是插入新节点，相同key的节点按插入顺序排列
+func WithMultimap() Option {
+	return func(o *options) {
+		o.multi = true
+	}
+}
@@ -0,0 +1,144 @@
+package avltree
+
+import (
+	"sync"
+)
+
+// parallelThreshold 两棵子树的节点数之和不小于parallelThreshold时，在新的goroutine中处理左子树
+const parallelThreshold = 1 << 12
+
+// Union 并集，时间复杂度O(m log(n/m + 1))，m、n分别为较小、较大的树的节点数
+//
+// key同时存在于t1、t2中时，value为merge(key, t1中的value, t2中的value)，merge为nil时使用t2中的value；
+// 节点数较多时会在多个goroutine中并行执行，comparator、merge需要支持并发调用；
+// 合并之后t1、t2为空树，返回的树使用t1的比较函数，没有注册聚合值
+func Union[K, V any](t1, t2 *Tree[K, V], merge func(key K, v1, v2 V) V) *Tree[K, V] {
+	tree := t1.subtree(t1.union(t1.root, t2.root, merge))
+	t1.reset()
+	t2.reset()
+
+	return tree
+}
+
+// Intersection 交集，value使用t1中的value，时间复杂度O(m log(n/m + 1))
+//
+// 计算之后t1、t2为空树，返回的树使用t1的比较函数，没有注册聚合值
+func Intersection[K, V any](t1, t2 *Tree[K, V]) *Tree[K, V] {
+	tree := t1.subtree(t1.intersection(t1.root, t2.root))
+	t1.reset()
+	t2.reset()
+
+	return tree
+}
+
+// Difference 差集，t1中存在、t2中不存在的key，时间复杂度O(m log(n/m + 1))
+//
+// 计算之后t1、t2为空树，返回的树使用t1的比较函数，没有注册聚合值
+func Difference[K, V any](t1, t2 *Tree[K, V]) *Tree[K, V] {
+	tree := t1.subtree(t1.difference(t1.root, t2.root))
+	t1.reset()
+	t2.reset()
+
+	return tree
+}
+
+// union 以n1为根节点的子树和以n2为根节点的子树的并集，返回新的根节点
+//
+// 用n1的key拆分n2，分别求左右两部分的并集，再以n1为中间节点合并
+func (t *Tree[K, V]) union(n1, n2 *TreeNode[K, V], merge func(key K, v1, v2 V) V) *TreeNode[K, V] {
+	if n1.isSentinel() {
+		return n2
+	}
+
+	if n2.isSentinel() {
+		return n1
+	}
+
+	left, right := n1.left, n1.right
+	l, m, r := t.splitNode(n2, n1.GetKey())
+	if !m.isSentinel() {
+		val := m.GetValue()
+		if merge != nil {
+			val = merge(n1.GetKey(), n1.GetValue(), m.GetValue())
+		}
+
+		n1.entry.SetValue(val)
+	}
+
+	var ul, ur *TreeNode[K, V]
+	parallel(n1.count()+n2.count(), func() {
+		ul = t.union(left, l, merge)
+	}, func() {
+		ur = t.union(right, r, merge)
+	})
+
+	return t.join(ul, n1, ur)
+}
+
+// intersection 以n1为根节点的子树和以n2为根节点的子树的交集，返回新的根节点
+func (t *Tree[K, V]) intersection(n1, n2 *TreeNode[K, V]) *TreeNode[K, V] {
+	if n1.isSentinel() {
+		return n1
+	}
+
+	if n2.isSentinel() {
+		return n2
+	}
+
+	left, right := n1.left, n1.right
+	l, m, r := t.splitNode(n2, n1.GetKey())
+
+	var il, ir *TreeNode[K, V]
+	parallel(n1.count()+n2.count(), func() {
+		il = t.intersection(left, l)
+	}, func() {
+		ir = t.intersection(right, r)
+	})
+
+	if m.isSentinel() {
+		// n1的key不在n2中
+		return t.join2(il, ir)
+	}
+
+	return t.join(il, n1, ir)
+}
+
+// difference 以n1为根节点的子树和以n2为根节点的子树的差集，返回新的根节点
+//
+// 用n2的key拆分n1，丢弃n1中和n2的key相同的节点，分别求左右两部分的差集，再合并
+func (t *Tree[K, V]) difference(n1, n2 *TreeNode[K, V]) *TreeNode[K, V] {
+	if n1.isSentinel() || n2.isSentinel() {
+		return n1
+	}
+
+	left, right := n2.left, n2.right
+	l, _, r := t.splitNode(n1, n2.GetKey())
+
+	var dl, dr *TreeNode[K, V]
+	parallel(n1.count()+n2.count(), func() {
+		dl = t.difference(l, left)
+	}, func() {
+		dr = t.difference(r, right)
+	})
+
+	return t.join2(dl, dr)
+}
+
+// parallel 执行left、right，两者操作的节点互不相交，size不小于parallelThreshold时并行执行
+func parallel(size int, left, right func()) {
+	if size < parallelThreshold {
+		left()
+		right()
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		left()
+	}()
+
+	right()
+	wg.Wait()
+}
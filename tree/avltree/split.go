@@ -0,0 +1,208 @@
+package avltree
+
+// Split 按key将树拆分成两棵树，left中的key都小于key，right中的key都大于等于key，时间复杂度O(log n)
+//
+// 拆分之后t为空树，left、right使用t的比较函数和模式，没有注册聚合值
+func (t *Tree[K, V]) Split(key K) (left, right *Tree[K, V]) {
+	l, r := t.split(t.root, key)
+	left, right = t.subtree(l), t.subtree(r)
+	t.reset()
+
+	return left, right
+}
+
+// Join 合并两棵树，left中的key都必须小于right中的key，left为multimap模式时允许等于，时间复杂度O(log n)
+//
+// 合并之后left、right为空树，返回的树使用left的比较函数和模式，没有注册聚合值；
+// left、right的key有重叠时返回false，left、right不变
+func Join[K, V any](left, right *Tree[K, V]) (*Tree[K, V], bool) {
+	maxNode, minNode := left.maximum(), right.minimum()
+	if maxNode != nil && minNode != nil {
+		if res := left.comparator(maxNode.GetKey(), minNode.GetKey()); res > 0 || (res == 0 && !left.multi) {
+			return nil, false
+		}
+	}
+
+	tree := left.subtree(left.join2(left.root, right.root))
+	left.reset()
+	right.reset()
+
+	return tree, true
+}
+
+// split 按key拆分以node为根节点的子树，l中的key都小于key，r中的key都大于等于key
+func (t *Tree[K, V]) split(node *TreeNode[K, V], key K) (l, r *TreeNode[K, V]) {
+	if node.isSentinel() {
+		return node, node
+	}
+
+	left, right := node.left, node.right
+	if t.comparator(node.GetKey(), key) < 0 {
+		// node和左子树都小于key
+		l, r = t.split(right, key)
+		return t.join(left, node, l), r
+	}
+
+	// node和右子树都大于等于key
+	l, r = t.split(left, key)
+	return l, t.join(r, node, right)
+}
+
+// splitNode 按key拆分以node为根节点的子树，l中的key都小于key，r中的key都大于key，m为key所在的节点，没有找到key时m为哨兵节点
+func (t *Tree[K, V]) splitNode(node *TreeNode[K, V], key K) (l, m, r *TreeNode[K, V]) {
+	if node.isSentinel() {
+		return node, node, node
+	}
+
+	left, right := node.left, node.right
+	switch res := t.comparator(node.GetKey(), key); {
+	case res < 0:
+		l, m, r = t.splitNode(right, key)
+		return t.join(left, node, l), m, r
+	case res > 0:
+		l, m, r = t.splitNode(left, key)
+		return l, m, t.join(r, node, right)
+	}
+
+	return left, node, right
+}
+
+// splitLast 从以node为根节点的子树中拆出最大的节点，返回剩下的子树和最大的节点
+func (t *Tree[K, V]) splitLast(node *TreeNode[K, V]) (rest, last *TreeNode[K, V]) {
+	if node.right.isSentinel() {
+		return node.left, node
+	}
+
+	left := node.left
+	rest, last = t.splitLast(node.right)
+	return t.join(left, node, rest), last
+}
+
+// join2 合并l、r两棵子树，l中的key都小于r中的key，以l中最大的节点作为中间节点，返回新的根节点
+func (t *Tree[K, V]) join2(l, r *TreeNode[K, V]) *TreeNode[K, V] {
+	if l.isSentinel() {
+		return r
+	}
+
+	rest, last := t.splitLast(l)
+	return t.join(rest, last, r)
+}
+
+// join 以mid为中间节点合并l、r两棵子树，l中的key都小于mid，r中的key都大于mid，返回新的根节点
+//
+// 时间复杂度O(|l.high() - r.high()|)
+func (t *Tree[K, V]) join(l, mid, r *TreeNode[K, V]) *TreeNode[K, V] {
+	switch {
+	case l.high() > r.high()+1:
+		return t.joinRight(l, mid, r)
+	case r.high() > l.high()+1:
+		return t.joinLeft(l, mid, r)
+	}
+
+	t.link(mid, l, r)
+	return mid
+}
+
+// joinRight l比r高2以上，沿着l的右边界向下找到高度不超过r.high()+1的子树，和r一起作为mid的左右子树，再向上修复
+func (t *Tree[K, V]) joinRight(l, mid, r *TreeNode[K, V]) *TreeNode[K, V] {
+	var right *TreeNode[K, V]
+
+	if l.right.high() <= r.high()+1 {
+		t.link(mid, l.right, r)
+		right = mid
+
+		if right.high() > l.left.high()+1 {
+			// mid的左子树比右子树高，先右旋，下面再左旋
+			right = t.linkRightRotate(mid)
+		}
+	} else {
+		right = t.joinRight(l.right, mid, r)
+	}
+
+	t.link(l, l.left, right)
+	if right.high() > l.left.high()+1 {
+		return t.linkLeftRotate(l)
+	}
+
+	return l
+}
+
+// joinLeft r比l高2以上，沿着r的左边界向下找到高度不超过l.high()+1的子树，和l一起作为mid的左右子树，再向上修复
+func (t *Tree[K, V]) joinLeft(l, mid, r *TreeNode[K, V]) *TreeNode[K, V] {
+	var left *TreeNode[K, V]
+
+	if r.left.high() <= l.high()+1 {
+		t.link(mid, l, r.left)
+		left = mid
+
+		if left.high() > r.right.high()+1 {
+			// mid的右子树比左子树高，先左旋，下面再右旋
+			left = t.linkLeftRotate(mid)
+		}
+	} else {
+		left = t.joinLeft(l, mid, r.left)
+	}
+
+	t.link(r, left, r.right)
+	if left.high() > r.right.high()+1 {
+		return t.linkRightRotate(r)
+	}
+
+	return r
+}
+
+// linkLeftRotate 左旋，返回新的根节点
+func (t *Tree[K, V]) linkLeftRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	r := node.right
+	t.link(node, node.left, r.left)
+	t.link(r, node, r.right)
+
+	return r
+}
+
+// linkRightRotate 右旋，返回新的根节点
+func (t *Tree[K, V]) linkRightRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	l := node.left
+	t.link(node, l.right, node.right)
+	t.link(l, l.left, node)
+
+	return l
+}
+
+// link 设置node的左右子节点，并重新计算node的高度和节点数
+//
+// 拆分、合并时不维护聚合值，参与拆分、合并的树可能注册了不同的聚合值
+func (t *Tree[K, V]) link(node, left, right *TreeNode[K, V]) {
+	node.left = left
+	node.right = right
+
+	if !left.isSentinel() {
+		left.parent = node
+	}
+
+	if !right.isSentinel() {
+		right.parent = node
+	}
+
+	node.height = node.max() + 1
+	node.size = left.count() + right.count() + 1
+}
+
+// subtree 创建一棵以root为根节点的树，比较函数和模式和t相同
+func (t *Tree[K, V]) subtree(root *TreeNode[K, V]) *Tree[K, V] {
+	tree := NewTreeFunc[K, V](t.comparator)
+	tree.multi = t.multi
+	if !root.isSentinel() {
+		root.parent = nil
+		tree.root = root
+		tree.size = root.size
+	}
+
+	return tree
+}
+
+// reset 清空树，树中的节点已经转移到其他树中
+func (t *Tree[K, V]) reset() {
+	t.root = t.sentinel
+	t.size = 0
+}
@@ -2,48 +2,69 @@ package btree
 
 import (
 	"bytes"
+	"cmp"
 	"container/list"
 	"fmt"
+	"iter"
 	"os/exec"
 	"runtime"
 
 	dot "github.com/asinglestep/godot"
+	"github.com/asinglestep/gods/map/orderedmap"
 	"github.com/asinglestep/gods/utils"
 )
 
 // Tree Tree
-type Tree struct {
-	root       *TreeNode // 指向根结点
-	comparator utils.Comparator
-	size       int // 节点数
-	maxEntry   int // 每个节点最多有多少个entry
-	minEntry   int // 非根节点最少有多少个entry
+type Tree[K, V any] struct {
+	root       *TreeNode[K, V] // 指向根结点
+	comparator func(a, b K) int
+	size       int    // 节点数
+	maxEntry   int    // 每个节点最多有多少个entry
+	minEntry   int    // 非根节点最少有多少个entry
+	gen        uint64 // 版本号，只能修改版本号相同的节点，见Clone
 }
 
-// NewTree 新建b树
+// NewTree 新建key、value为interface{}的b树
 //
 // @param
 // t: 度数
 // comparator: 比较器
-func NewTree(t int, comparator utils.Comparator) *Tree {
-	tree := &Tree{}
+func NewTree(t int, comparator utils.Comparator) *Tree[interface{}, interface{}] {
+	return NewTreeFunc[interface{}, interface{}](t, comparator.Compare)
+}
+
+// NewTreeFunc 新建b树
+//
+// @param
+// t: 度数
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+func NewTreeFunc[K, V any](t int, comparator func(a, b K) int) *Tree[K, V] {
+	tree := &Tree[K, V]{}
 	tree.maxEntry = 2*t - 1
 	tree.minEntry = t - 1
-	tree.root = NewNode()
+	tree.root = NewNode[K, V]()
 	tree.comparator = comparator
 
 	return tree
 }
 
+// NewOrderedTree 新建key为有序类型的b树
+//
+// @param
+// t: 度数
+func NewOrderedTree[K cmp.Ordered, V any](t int) *Tree[K, V] {
+	return NewTreeFunc[K, V](t, cmp.Compare[K])
+}
+
 // Insert 插入
-func (t *Tree) Insert(key, val interface{}) {
+func (t *Tree[K, V]) Insert(key K, val V) {
 	keyPos := 0
-	node := t.root
+	node := t.mutableRoot()
 
 	// 找到key插入的叶子节点
 	for {
 		left, right, midEntry := t.splitNode(node)
-		if right != nil && t.comparator.Compare(midEntry.GetKey(), key) == utils.Lt {
+		if right != nil && t.comparator(midEntry.GetKey(), key) < 0 {
 			// 分裂后，中间的key比key小，继续在右节点中查找
 			node = right
 		} else {
@@ -58,30 +79,33 @@ func (t *Tree) Insert(key, val interface{}) {
 		}
 
 		// 指向下一个查找到节点
-		node = node.childrens[keyPos]
+		node = t.mutableChild(node, keyPos)
 	}
 
 	// 插入新的entry
-	node.insertEntry(utils.NewEntry(key, val), keyPos)
+	node.insertEntry(utils.NewTypedEntry(key, val), keyPos)
 	t.size++
 }
 
 // Delete 删除
-func (t *Tree) Delete(key interface{}) {
+func (t *Tree[K, V]) Delete(key K) {
 	t.deleteKey(key)
 }
 
 // deleteKey deleteKey
-func (t *Tree) deleteKey(key interface{}) {
-	pos := 0
-	bFound := false
-	node := t.root
+func (t *Tree[K, V]) deleteKey(key K) {
+	if _, _, bFound := t.lookup(t.root, key); !bFound {
+		// 没找到，不需要拷贝节点
+		return
+	}
+
+	node := t.mutableRoot()
 
 	for {
-		node, pos, bFound = t.lookup(node, key)
+		pos, bFound := node.findLowerBoundKeyPosition(t.comparator, key)
 		if !bFound {
-			// 没找到
-			return
+			node = t.mutableChild(node, pos)
+			continue
 		}
 
 		if node.isLeaf() {
@@ -96,13 +120,13 @@ func (t *Tree) deleteKey(key interface{}) {
 		// 找到当前节点的前驱或者后继节点，删除后继或者前驱节点
 		if len(node.childrens[pos+1].entries) > t.minEntry {
 			// 右节点至少有t个关键字，找到后继节点，用后继节点的key替换key
-			ssNode := node.findSuccessor(pos)
+			ssNode := t.findSuccessor(node, pos)
 			node.entries[pos] = ssNode.entries[0]
 			key = ssNode.entries[0].GetKey()
 			node = ssNode
 		} else {
 			// 找到前驱节点，用前驱节点的key替换key
-			preNode := node.findPrecursor(pos)
+			preNode := t.findPrecursor(node, pos)
 			node.entries[pos] = preNode.entries[len(preNode.entries)-1]
 			key = preNode.entries[len(preNode.entries)-1].GetKey()
 			node = preNode
@@ -110,8 +134,28 @@ func (t *Tree) deleteKey(key interface{}) {
 	}
 }
 
+// findPrecursor 找到node中pos位置的前驱节点，路径上的节点都是可以修改的
+func (t *Tree[K, V]) findPrecursor(node *TreeNode[K, V], pos int) *TreeNode[K, V] {
+	node = t.mutableChild(node, pos)
+	for !node.isLeaf() {
+		node = t.mutableChild(node, len(node.childrens)-1)
+	}
+
+	return node
+}
+
+// findSuccessor 找到node中pos位置的后继节点，路径上的节点都是可以修改的
+func (t *Tree[K, V]) findSuccessor(node *TreeNode[K, V], pos int) *TreeNode[K, V] {
+	node = t.mutableChild(node, pos+1)
+	for !node.isLeaf() {
+		node = t.mutableChild(node, 0)
+	}
+
+	return node
+}
+
 // deleteFixUp 删除修复
-func (t *Tree) deleteFixUp(node *TreeNode, key interface{}) {
+func (t *Tree[K, V]) deleteFixUp(node *TreeNode[K, V], key K) {
 	for {
 		parent := node.parent
 		if parent == nil {
@@ -138,8 +182,13 @@ func (t *Tree) deleteFixUp(node *TreeNode, key interface{}) {
 	}
 }
 
-// Search 查找指定的key对应的Entry
-func (t *Tree) Search(key interface{}) *utils.Entry {
+// Search 查找指定的key对应的Entry，树被克隆过时返回Entry的拷贝，见Clone
+func (t *Tree[K, V]) Search(key K) *utils.TypedEntry[K, V] {
+	return t.export(t.search(key))
+}
+
+// search 查找指定的key对应的Entry，返回树中的Entry
+func (t *Tree[K, V]) search(key K) *utils.TypedEntry[K, V] {
 	node, pos, bFound := t.lookup(t.root, key)
 	if !bFound {
 		// 没找到
@@ -149,26 +198,26 @@ func (t *Tree) Search(key interface{}) *utils.Entry {
 	return node.entries[pos]
 }
 
-// SearchRange 查找key在[min, max]之间的Entry
-func (t *Tree) SearchRange(min, max interface{}) []*utils.Entry {
-	entries := []*utils.Entry{}
+// SearchRange 查找key在[min, max]之间的Entry，树被克隆过时返回Entry的拷贝，见Clone
+func (t *Tree[K, V]) SearchRange(min, max K) []*utils.TypedEntry[K, V] {
+	entries := []*utils.TypedEntry[K, V]{}
 
 	iter := NewIteratorLowerBoundKey(t, min)
 	for iter.Next() {
-		if t.comparator.Compare(iter.GetKey(), max) == utils.Gt {
+		if t.comparator(iter.GetKey(), max) > 0 {
 			break
 		}
 
-		entries = append(entries, iter.entry)
+		entries = append(entries, t.export(iter.entry))
 	}
 
 	return entries
 }
 
 // SearchRangeLowerBoundKeyWithLimit 查找大于等于key的limit个节点
-func (t *Tree) SearchRangeLowerBoundKeyWithLimit(key interface{}, limit int64) []*utils.Entry {
+func (t *Tree[K, V]) SearchRangeLowerBoundKeyWithLimit(key K, limit int64) []*utils.TypedEntry[K, V] {
 	var count int64
-	list := make([]*utils.Entry, 0, limit)
+	list := make([]*utils.TypedEntry[K, V], 0, limit)
 
 	iter := NewIteratorLowerBoundKey(t, key)
 	for iter.Next() {
@@ -176,13 +225,161 @@ func (t *Tree) SearchRangeLowerBoundKeyWithLimit(key interface{}, limit int64) [
 			break
 		}
 
-		list = append(list, iter.entry)
+		list = append(list, t.export(iter.entry))
 		count++
 	}
 
 	return list
 }
 
+// SearchRangeUpperBoundKeyWithLimit 查找小于等于key的limit个节点，按key升序返回
+func (t *Tree[K, V]) SearchRangeUpperBoundKeyWithLimit(key K, limit int64) []*utils.TypedEntry[K, V] {
+	var count int64
+	list := make([]*utils.TypedEntry[K, V], 0, limit)
+
+	iter := NewIteratorUpperBoundKey(t, key)
+	for iter.Prev() {
+		if count == limit {
+			break
+		}
+
+		list = append(list, t.export(iter.entry))
+		count++
+	}
+
+	return reverse(list)
+}
+
+// Put 插入key、value，key已存在时更新value
+//
+// entry可能和克隆的树共享，更新时替换为新的entry，不修改原来的entry
+func (t *Tree[K, V]) Put(key K, val V) {
+	if t.search(key) == nil {
+		t.Insert(key, val)
+		return
+	}
+
+	node := t.mutableRoot()
+	for {
+		pos, bFound := node.findLowerBoundKeyPosition(t.comparator, key)
+		if bFound {
+			node.entries[pos] = utils.NewTypedEntry(key, val)
+			return
+		}
+
+		node = t.mutableChild(node, pos)
+	}
+}
+
+// Get 查找key对应的value
+func (t *Tree[K, V]) Get(key K) (val V, bFound bool) {
+	_, val, bFound = unpack(t.search(key))
+	return val, bFound
+}
+
+// Len 节点数
+func (t *Tree[K, V]) Len() int {
+	return t.size
+}
+
+// Min 最小的key和对应的value
+func (t *Tree[K, V]) Min() (key K, val V, bFound bool) {
+	if t.size == 0 {
+		return key, val, false
+	}
+
+	node := t.minimum()
+	return unpack(node.entries[0])
+}
+
+// Max 最大的key和对应的value
+func (t *Tree[K, V]) Max() (key K, val V, bFound bool) {
+	if t.size == 0 {
+		return key, val, false
+	}
+
+	node := t.maximum()
+	return unpack(node.entries[len(node.entries)-1])
+}
+
+// Floor 小于等于key的最大key和对应的value
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	return unpack(t.lookupUpperBoundKey(key))
+}
+
+// Ceiling 大于等于key的最小key和对应的value
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	return unpack(t.lookupLowerBoundKey(key))
+}
+
+// Lower 小于key的最大key和对应的value
+func (t *Tree[K, V]) Lower(key K) (K, V, bool) {
+	return unpack(t.lookupLess(key))
+}
+
+// Higher 大于key的最小key和对应的value
+func (t *Tree[K, V]) Higher(key K) (K, V, bool) {
+	return unpack(t.lookupGreater(key))
+}
+
+// PopMin 删除最小的key，并返回最小的key和对应的value
+func (t *Tree[K, V]) PopMin() (key K, val V, bFound bool) {
+	if key, val, bFound = t.Min(); bFound {
+		t.deleteKey(key)
+	}
+
+	return key, val, bFound
+}
+
+// PopMax 删除最大的key，并返回最大的key和对应的value
+func (t *Tree[K, V]) PopMax() (key K, val V, bFound bool) {
+	if key, val, bFound = t.Max(); bFound {
+		t.deleteKey(key)
+	}
+
+	return key, val, bFound
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的Entry
+func (t *Tree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		iter := NewIteratorLowerBoundKey(t, lo)
+		for iter.Next() {
+			if t.comparator(iter.GetKey(), hi) > 0 {
+				return
+			}
+
+			if !yield(iter.GetKey(), iter.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// All 按key升序遍历所有Entry
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		iter := NewIterator(t)
+		for iter.Next() {
+			if !yield(iter.GetKey(), iter.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 按key降序遍历所有Entry
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.backward(yield)
+	}
+}
+
+// Iterator 从最小key开始的迭代器
+func (t *Tree[K, V]) Iterator() orderedmap.Iterator[K, V] {
+	return NewIterator(t)
+}
+
 // getAdjacentNode 获取key所在节点的相邻节点
 //
 // @param
@@ -190,18 +387,18 @@ func (t *Tree) SearchRangeLowerBoundKeyWithLimit(key interface{}, limit int64) [
 // key: key
 //
 // @return
-// adjNode: 相邻节点
+// adjNode: 相邻节点，是可以修改的
 // pos: 父节点中第一个大于等于key的位置
 // bBig: true - 相邻节点在右侧，false - 相邻节点在左侧
-func (t *Tree) getAdjacentNode(parent *TreeNode, key interface{}) (adjNode *TreeNode, pos int, bBig bool) {
+func (t *Tree[K, V]) getAdjacentNode(parent *TreeNode[K, V], key K) (adjNode *TreeNode[K, V], pos int, bBig bool) {
 	pos, _ = parent.findLowerBoundKeyPosition(t.comparator, key)
 	if pos == 0 || (pos != len(parent.entries) && len(parent.childrens[pos-1].entries) <= t.minEntry) {
 		// 第一个key 或者 key在parent节点上且左侧相邻节点的关键字小于t个，返回右侧相邻节点
-		adjNode = parent.childrens[pos+1]
+		adjNode = t.mutableChild(parent, pos+1)
 		bBig = true
 	} else {
 		// key不在parent节点上 或者 左侧相邻节点的关键字至少有t个，返回左侧相邻节点
-		adjNode = parent.childrens[pos-1]
+		adjNode = t.mutableChild(parent, pos-1)
 	}
 
 	return adjNode, pos, bBig
@@ -215,7 +412,7 @@ func (t *Tree) getAdjacentNode(parent *TreeNode, key interface{}) (adjNode *Tree
 // adjNode: node的相邻节点
 // pos: 父节点中第一个大于等于key的位置
 // bBig: true - 相邻节点在node的右边，false - 相邻节点在node的左边
-func (t *Tree) moveEntry(parent *TreeNode, node *TreeNode, adjNode *TreeNode, pos int, bBig bool) {
+func (t *Tree[K, V]) moveEntry(parent *TreeNode[K, V], node *TreeNode[K, V], adjNode *TreeNode[K, V], pos int, bBig bool) {
 	if bBig {
 		t.moveCaseRightAdjacentNode(parent, node, adjNode, pos)
 	} else {
@@ -231,7 +428,7 @@ func (t *Tree) moveEntry(parent *TreeNode, node *TreeNode, adjNode *TreeNode, po
 // node: node
 // adjNode: node的相邻节点
 // pos: 父节点中第一个大于等于key的位置
-func (t *Tree) moveCaseRightAdjacentNode(parent *TreeNode, node *TreeNode, adjNode *TreeNode, pos int) {
+func (t *Tree[K, V]) moveCaseRightAdjacentNode(parent *TreeNode[K, V], node *TreeNode[K, V], adjNode *TreeNode[K, V], pos int) {
 	pEntry := parent.entries[pos]
 	// 将adjNode的第一个entry放到parent的pos上
 	parent.entries[pos] = adjNode.entries[0]
@@ -242,7 +439,7 @@ func (t *Tree) moveCaseRightAdjacentNode(parent *TreeNode, node *TreeNode, adjNo
 
 	if !node.isLeaf() {
 		// 改变子节点的父节点
-		adjNode.childrens[0].parent = node
+		node.adopt(adjNode.childrens[0])
 		// 相邻节点的孩子节点移到node中
 		node.childrens = append(node.childrens, adjNode.childrens[0])
 		// 删除相邻节点的孩子节点
@@ -257,7 +454,7 @@ func (t *Tree) moveCaseRightAdjacentNode(parent *TreeNode, node *TreeNode, adjNo
 // node: node
 // adjNode: node的相邻节点
 // pos: 父节点中第一个大于等于key的位置
-func (t *Tree) moveCaseLeftAdjacentNode(parent *TreeNode, node *TreeNode, adjNode *TreeNode, pos int) {
+func (t *Tree[K, V]) moveCaseLeftAdjacentNode(parent *TreeNode[K, V], node *TreeNode[K, V], adjNode *TreeNode[K, V], pos int) {
 	pEntry := parent.entries[pos]
 	adjNodeEntryLen := len(adjNode.entries)
 	// 将adjNode最后一个entry放到parent的pos上
@@ -270,7 +467,7 @@ func (t *Tree) moveCaseLeftAdjacentNode(parent *TreeNode, node *TreeNode, adjNod
 	if !node.isLeaf() {
 		adjNodeChildLen := len(adjNode.childrens)
 		// 改变子节点的父节点
-		adjNode.childrens[adjNodeChildLen-1].parent = node
+		node.adopt(adjNode.childrens[adjNodeChildLen-1])
 		// 相邻节点的孩子节点移到node中
 		node.insertChildren(adjNode.childrens[adjNodeChildLen-1], 0)
 		// 删除相邻节点的孩子节点
@@ -289,7 +486,7 @@ func (t *Tree) moveCaseLeftAdjacentNode(parent *TreeNode, node *TreeNode, adjNod
 //
 // @return
 // pEntry: 父节点中和node、adjNode相关联的entry
-func (t *Tree) merge(parent *TreeNode, node *TreeNode, adjNode *TreeNode, pos int, bBig bool) (pEntry *utils.Entry) {
+func (t *Tree[K, V]) merge(parent *TreeNode[K, V], node *TreeNode[K, V], adjNode *TreeNode[K, V], pos int, bBig bool) (pEntry *utils.TypedEntry[K, V]) {
 	if bBig {
 		pEntry = parent.entries[pos]
 		// 相邻节点合并到node中
@@ -309,7 +506,7 @@ func (t *Tree) merge(parent *TreeNode, node *TreeNode, adjNode *TreeNode, pos in
 }
 
 // mergeNode src、entry合并到dst中
-func (t *Tree) mergeNode(dst *TreeNode, src *TreeNode, entry *utils.Entry) {
+func (t *Tree[K, V]) mergeNode(dst *TreeNode[K, V], src *TreeNode[K, V], entry *utils.TypedEntry[K, V]) {
 	dst.entries = append(dst.entries, entry)
 	dst.entries = append(dst.entries, src.entries...)
 
@@ -332,7 +529,7 @@ func (t *Tree) mergeNode(dst *TreeNode, src *TreeNode, entry *utils.Entry) {
 // left: 分裂之后，midEntry的左节点
 // right: 分裂之后，midEntry的右节点
 // midEntry: node中间的entry
-func (t *Tree) splitNode(node *TreeNode) (left *TreeNode, right *TreeNode, midEntry *utils.Entry) {
+func (t *Tree[K, V]) splitNode(node *TreeNode[K, V]) (left *TreeNode[K, V], right *TreeNode[K, V], midEntry *utils.TypedEntry[K, V]) {
 	// 是满节点，对该节点进行分裂
 	if !node.isFull(t.maxEntry) {
 		return node, nil, nil
@@ -353,12 +550,12 @@ func (t *Tree) splitNode(node *TreeNode) (left *TreeNode, right *TreeNode, midEn
 	parent.insertEntry(midEntry, pos)
 
 	// midEntry的右节点
-	right = &TreeNode{}
+	right = t.newNode()
 	right.parent = parent
-	right.entries = make([]*utils.Entry, mid)
+	right.entries = make([]*utils.TypedEntry[K, V], mid)
 	copy(right.entries[:], node.entries[mid+1:])
 	if !node.isLeaf() {
-		right.childrens = make([]*TreeNode, mid+1)
+		right.childrens = make([]*TreeNode[K, V], mid+1)
 		copy(right.childrens[:], node.childrens[mid+1:])
 		right.updateChildrensParent(right)
 	}
@@ -376,9 +573,9 @@ func (t *Tree) splitNode(node *TreeNode) (left *TreeNode, right *TreeNode, midEn
 }
 
 // splitRootNode 分裂根节点
-func (t *Tree) splitRootNode(node *TreeNode) *TreeNode {
-	parent := &TreeNode{}
-	parent.childrens = make([]*TreeNode, 1)
+func (t *Tree[K, V]) splitRootNode(node *TreeNode[K, V]) *TreeNode[K, V] {
+	parent := t.newNode()
+	parent.childrens = make([]*TreeNode[K, V], 1)
 	parent.childrens[0] = node
 	node.parent = parent
 	t.root = parent
@@ -394,7 +591,7 @@ func (t *Tree) splitRootNode(node *TreeNode) *TreeNode {
 // @return
 // node: key所在的节点
 // pos: key在节点中的位置
-func (t *Tree) lookup(sNode *TreeNode, key interface{}) (node *TreeNode, pos int, bFound bool) {
+func (t *Tree[K, V]) lookup(sNode *TreeNode[K, V], key K) (node *TreeNode[K, V], pos int, bFound bool) {
 	node = sNode
 
 	for {
@@ -413,8 +610,101 @@ func (t *Tree) lookup(sNode *TreeNode, key interface{}) (node *TreeNode, pos int
 	}
 }
 
+// lookupLowerBoundKey 查找第一个大于等于key的Entry
+func (t *Tree[K, V]) lookupLowerBoundKey(key K) *utils.TypedEntry[K, V] {
+	var last *utils.TypedEntry[K, V]
+	node := t.root
+
+	for {
+		pos, bFound := node.findLowerBoundKeyPosition(t.comparator, key)
+		if bFound {
+			return node.entries[pos]
+		}
+
+		if pos < len(node.entries) {
+			// 子节点中的key都小于node.entries[pos]
+			last = node.entries[pos]
+		}
+
+		if node.isLeaf() {
+			return last
+		}
+
+		node = node.childrens[pos]
+	}
+}
+
+// lookupUpperBoundKey 查找最后一个小于等于key的Entry
+func (t *Tree[K, V]) lookupUpperBoundKey(key K) *utils.TypedEntry[K, V] {
+	var last *utils.TypedEntry[K, V]
+	node := t.root
+
+	for {
+		pos, bFound := node.findLowerBoundKeyPosition(t.comparator, key)
+		if bFound {
+			return node.entries[pos]
+		}
+
+		if pos > 0 {
+			// 子节点中的key都大于node.entries[pos-1]
+			last = node.entries[pos-1]
+		}
+
+		if node.isLeaf() {
+			return last
+		}
+
+		node = node.childrens[pos]
+	}
+}
+
+// lookupLess 查找最后一个小于key的Entry
+func (t *Tree[K, V]) lookupLess(key K) *utils.TypedEntry[K, V] {
+	var last *utils.TypedEntry[K, V]
+	node := t.root
+
+	for {
+		pos, _ := node.findLowerBoundKeyPosition(t.comparator, key)
+		if pos > 0 {
+			// 子节点中的key都大于node.entries[pos-1]
+			last = node.entries[pos-1]
+		}
+
+		if node.isLeaf() {
+			return last
+		}
+
+		node = node.childrens[pos]
+	}
+}
+
+// lookupGreater 查找第一个大于key的Entry
+func (t *Tree[K, V]) lookupGreater(key K) *utils.TypedEntry[K, V] {
+	var last *utils.TypedEntry[K, V]
+	node := t.root
+
+	for {
+		pos, bFound := node.findLowerBoundKeyPosition(t.comparator, key)
+		if bFound {
+			// 跳过等于key的Entry
+			pos++
+		}
+
+		if pos < len(node.entries) {
+			// 子节点中的key都小于node.entries[pos]
+			last = node.entries[pos]
+		}
+
+		if node.isLeaf() {
+			return last
+		}
+
+		node = node.childrens[pos]
+	}
+}
+
 // dCaseRoot 删除修复 - 修复根节点
-func (t *Tree) dCaseRoot(node *TreeNode) {
+func (t *Tree[K, V]) dCaseRoot(node *TreeNode[K, V]) {
 	if len(node.entries) != 0 {
 		return
 	}
@@ -426,30 +716,44 @@ func (t *Tree) dCaseRoot(node *TreeNode) {
 		// 合并时，将根节点唯一的key合并到子节点中
 		// 需要修改t.root的指向
 		t.root = node.childrens[0]
-		node.childrens[0].parent = nil
+		if t.root.gen == t.gen {
+			t.root.parent = nil
+		}
 	}
 }
 
 // minimum 中序遍历后，树的最小节点
-func (t *Tree) minimum() *TreeNode {
+func (t *Tree[K, V]) minimum() *TreeNode[K, V] {
 	return t.root.minimum()
 }
 
 // maximum 中序遍历后，树的最大节点
-func (t *Tree) maximum() *TreeNode {
+func (t *Tree[K, V]) maximum() *TreeNode[K, V] {
 	return t.root.maximum()
 }
 
 // Verify 验证是否是一个b树
-func (t *Tree) Verify() bool {
-	entires := make([]*utils.Entry, 0, t.size)
+func (t *Tree[K, V]) Verify() bool {
+	entires := make([]*utils.TypedEntry[K, V], 0, t.size)
+	leafDepth := 0
+	for node := t.root; !node.isLeaf(); node = node.childrens[0] {
+		leafDepth++
+	}
+
+	// 和其他树共享的节点的父节点可能是其他树中的节点，只验证属于当前树的节点的父节点
+	if t.root.gen == t.gen && t.root.parent != nil {
+		fmt.Printf("根节点[%v]的父节点不为nil\n", t.root.entries)
+		return false
+	}
+
 	queue := list.New()
-	queue.PushBack(t.root)
+	queue.PushBack(&verifyNode[K, V]{t.root, nil, 0})
 
 	for queue.Len() != 0 {
 		e := queue.Remove(queue.Front())
-		node := e.(*TreeNode)
-		if node.parent != nil {
+		v := e.(*verifyNode[K, V])
+		node := v.node
+		if v.parent != nil {
 			// 每个非根节点至少有t-1个关键字
 			if len(node.entries) < t.minEntry {
 				fmt.Printf("非根节点[%v]的关键字小于%v\n", node.entries, t.minEntry)
@@ -465,7 +769,7 @@ func (t *Tree) Verify() bool {
 
 		if !node.isLeaf() {
 			// 非根的内节点至少有t个子女
-			if node.parent != nil && len(node.childrens) < t.minEntry+1 {
+			if v.parent != nil && len(node.childrens) < t.minEntry+1 {
 				fmt.Printf("非根的内节点[%v]的子女小于%v\n", node.entries, t.minEntry+1)
 				return false
 			}
@@ -476,10 +780,33 @@ func (t *Tree) Verify() bool {
 				return false
 			}
 
+			// 内节点的子女比关键字多1个
+			if len(node.childrens) != len(node.entries)+1 {
+				fmt.Printf("内节点[%v]的子女数不等于关键字数加1\n", node.entries)
+				return false
+			}
+
 			// 添加node的所有子节点到queue中
-			for i := range node.childrens {
-				queue.PushBack(node.childrens[i])
+			for i, children := range node.childrens {
+				if children.gen == t.gen {
+					// 属于当前树的节点，祖先节点也属于当前树
+					if node.gen != t.gen {
+						fmt.Printf("节点[%v]属于当前树，父节点不属于当前树\n", children.entries)
+						return false
+					}
+
+					if children.parent != node {
+						fmt.Printf("节点[%v]的父节点错误\n", children.entries)
+						return false
+					}
+				}
+
+				queue.PushBack(&verifyNode[K, V]{node.childrens[i], node, v.depth + 1})
 			}
+		} else if v.depth != leafDepth {
+			// 所有叶子节点的深度相同
+			fmt.Printf("叶子节点[%v]的深度错误\n", node.entries)
+			return false
 		}
 	}
 
@@ -490,7 +817,7 @@ func (t *Tree) Verify() bool {
 
 	// 验证顺序
 	for i := 0; i < len(entires)-1; i++ {
-		if t.comparator.Compare(entires[i].GetKey(), entires[i+1].GetKey()) == utils.Gt {
+		if t.comparator(entires[i].GetKey(), entires[i+1].GetKey()) > 0 {
 			fmt.Printf("Key顺序错误\n")
 			return false
 		}
@@ -505,38 +832,46 @@ func (t *Tree) Verify() bool {
 	return true
 }
 
+// verifyNode 验证时队列中的节点
+type verifyNode[K, V any] struct {
+	node   *TreeNode[K, V]
+	parent *TreeNode[K, V] // 父节点，根节点为nil
+	depth  int             // 节点的深度，根节点的深度为0
+}
+
 // String String
-func (t *Tree) String() string {
+func (t *Tree[K, V]) String() string {
 	buffer := bytes.Buffer{}
 	offset := 0
 	queue := list.New()
-	queue.PushBack(t.root)
+	queue.PushBack(&verifyNode[K, V]{t.root, nil, 0})
 
 	for queue.Len() != 0 {
 		e := queue.Remove(queue.Front())
-		node := e.(*TreeNode)
+		v := e.(*verifyNode[K, V])
 
-		buffer.WriteString(node.printBTreeNode(&offset))
+		buffer.WriteString(v.node.printBTreeNode(v.parent, &offset))
 
-		for i := range node.childrens {
-			queue.PushBack(node.childrens[i])
+		for i := range v.node.childrens {
+			queue.PushBack(&verifyNode[K, V]{v.node.childrens[i], v.node, v.depth + 1})
 		}
 	}
 
 	return buffer.String()
 }
 
-type dotNode struct {
-	node     *TreeNode
-	nDotName string // 当前节点的dot name
-	pDotName string // 父节点的dot name
+type dotNode[K, V any] struct {
+	node     *TreeNode[K, V]
+	parent   *TreeNode[K, V] // 父节点，根节点为nil
+	nDotName string          // 当前节点的dot name
+	pDotName string          // 父节点的dot name
 }
 
 // Dot Dot
-func (t *Tree) Dot() error {
+func (t *Tree[K, V]) Dot() error {
 	nameIdx := 0
 	stack := list.New()
-	stack.PushBack(&dotNode{t.root, fmt.Sprintf("node%d", nameIdx), ""})
+	stack.PushBack(&dotNode[K, V]{t.root, nil, fmt.Sprintf("node%d", nameIdx), ""})
 	nameIdx++
 
 	dGraph := dot.NewGraph()
@@ -548,9 +883,9 @@ func (t *Tree) Dot() error {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		d := e.(*dotNode)
+		d := e.(*dotNode[K, V])
 
-		dNode, dEdge := d.node.dot(t.comparator, d.nDotName, d.pDotName)
+		dNode, dEdge := d.node.dot(t.comparator, d.parent, d.nDotName, d.pDotName)
 		dGraph.AddNode(dNode)
 		if dEdge != nil {
 			dGraph.AddEdge(dEdge)
@@ -559,7 +894,7 @@ func (t *Tree) Dot() error {
 		// 将根节点和内节点的所有子节点加入到stack
 		if !d.node.isLeaf() {
 			for _, v := range d.node.childrens {
-				stack.PushBack(&dotNode{v, fmt.Sprintf("node%d", nameIdx), d.nDotName})
+				stack.PushBack(&dotNode[K, V]{v, d.node, fmt.Sprintf("node%d", nameIdx), d.nDotName})
 				nameIdx++
 			}
 		}
@@ -5,10 +5,12 @@ import (
 	"math/rand"
 	"testing"
 	"time"
+
+	"github.com/asinglestep/gods/utils"
 )
 
 func Benchmark_BTreeRandInsert(b *testing.B) {
-	tree := NewTree(DEGREE, btreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -23,8 +25,22 @@ func Benchmark_BTreeRandInsert(b *testing.B) {
 	fmt.Printf("Bptree Insert %v elements, use %v ms\n", num, time.Now().Sub(sTime).Nanoseconds()/1e6)
 }
 
+func Benchmark_BTreeOrderedRandInsert(b *testing.B) {
+	tree := NewOrderedTree[int, int](DEGREE)
+	var num = 10000000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+
+	sTime := time.Now()
+	for _, v := range array {
+		tree.Insert(v, v)
+	}
+
+	fmt.Printf("BTree Ordered Insert %v elements, use %v ms\n", num, time.Now().Sub(sTime).Nanoseconds()/1e6)
+}
+
 func Benchmark_BTreeRandDelete(b *testing.B) {
-	tree := NewTree(DEGREE, btreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 10000000
 
 	iArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -39,7 +55,7 @@ func Benchmark_BTreeRandDelete(b *testing.B) {
 }
 
 func Benchmark_BTreeRangeSearch(b *testing.B) {
-	tree := NewTree(DEGREE, btreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 10000000
 
 	insertArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -9,22 +9,23 @@ import (
 )
 
 // TreeNode TreeNode
-type TreeNode struct {
-	parent    *TreeNode      // 父节点
-	childrens []*TreeNode    // 子节点
-	entries   []*utils.Entry // 节点数据
+type TreeNode[K, V any] struct {
+	parent    *TreeNode[K, V]           // 父节点，只有属于当前树的节点的父节点是有效的
+	childrens []*TreeNode[K, V]         // 子节点
+	entries   []*utils.TypedEntry[K, V] // 节点数据
+	gen       uint64                    // 节点所属的树的版本号，和树的版本号相同时可以直接修改，见Clone
 }
 
 // NewNode NewNode
-func NewNode() *TreeNode {
-	n := &TreeNode{}
+func NewNode[K, V any]() *TreeNode[K, V] {
+	n := &TreeNode[K, V]{}
 
 	return n
 }
 
 // insertEntry 将新的entry插入到node的pos位置上
-func (node *TreeNode) insertEntry(entry *utils.Entry, pos int) {
-	newEntries := make([]*utils.Entry, len(node.entries)+1)
+func (node *TreeNode[K, V]) insertEntry(entry *utils.TypedEntry[K, V], pos int) {
+	newEntries := make([]*utils.TypedEntry[K, V], len(node.entries)+1)
 	newEntries[pos] = entry
 	copy(newEntries[:pos], node.entries[:pos])
 	copy(newEntries[pos+1:], node.entries[pos:])
@@ -32,8 +33,8 @@ func (node *TreeNode) insertEntry(entry *utils.Entry, pos int) {
 }
 
 // insertChildren 将children插入到node的pos位置上
-func (node *TreeNode) insertChildren(children *TreeNode, pos int) {
-	newCs := make([]*TreeNode, len(node.childrens)+1)
+func (node *TreeNode[K, V]) insertChildren(children *TreeNode[K, V], pos int) {
+	newCs := make([]*TreeNode[K, V], len(node.childrens)+1)
 	newCs[pos] = children
 	copy(newCs[:pos], node.childrens[:pos])
 	copy(newCs[pos+1:], node.childrens[pos:])
@@ -41,14 +42,21 @@ func (node *TreeNode) insertChildren(children *TreeNode, pos int) {
 }
 
 // updateChildrensParent 更新的node的childrens的父节点
-func (node *TreeNode) updateChildrensParent(parent *TreeNode) {
+func (node *TreeNode[K, V]) updateChildrensParent(parent *TreeNode[K, V]) {
 	for i := range node.childrens {
-		node.childrens[i].parent = parent
+		parent.adopt(node.childrens[i])
+	}
+}
+
+// adopt 将children的父节点设置为node，children和其他树共享时不修改children
+func (node *TreeNode[K, V]) adopt(children *TreeNode[K, V]) {
+	if children.gen == node.gen {
+		children.parent = node
 	}
 }
 
 // findLowerBoundKeyPosition 在节点中查找第一个大于等于key的位置，没有比key大的，则返回node.entries的长度
-func (node *TreeNode) findLowerBoundKeyPosition(comparator utils.Comparator, key interface{}) (pos int, bFound bool) {
+func (node *TreeNode[K, V]) findLowerBoundKeyPosition(comparator func(a, b K) int, key K) (pos int, bFound bool) {
 	if len(node.entries) == 0 {
 		return 0, false
 	}
@@ -57,7 +65,7 @@ func (node *TreeNode) findLowerBoundKeyPosition(comparator utils.Comparator, key
 
 	for i < j {
 		h := int(uint(i+j) >> 1)
-		if comparator.Compare(node.entries[h].GetKey(), key) == utils.Lt {
+		if comparator(node.entries[h].GetKey(), key) < 0 {
 			i = h + 1
 		} else {
 			j = h
@@ -68,52 +76,32 @@ func (node *TreeNode) findLowerBoundKeyPosition(comparator utils.Comparator, key
 		return i, false
 	}
 
-	if comparator.Compare(node.entries[i].GetKey(), key) == utils.Et {
+	if comparator(node.entries[i].GetKey(), key) == 0 {
 		return i, true
 	}
 
 	return i, false
 }
 
-// findPrecursor 找到pos位置的前驱节点
-func (node *TreeNode) findPrecursor(pos int) *TreeNode {
-	node = node.childrens[pos]
-	for !node.isLeaf() {
-		node = node.childrens[len(node.childrens)-1]
-	}
-
-	return node
-}
-
-// findSuccessor 找到pos位置的后继节点
-func (node *TreeNode) findSuccessor(pos int) *TreeNode {
-	node = node.childrens[pos+1]
-	for !node.isLeaf() {
-		node = node.childrens[0]
-	}
-
-	return node
-}
-
 // isLeaf 是否是叶子节点
-func (node *TreeNode) isLeaf() bool {
+func (node *TreeNode[K, V]) isLeaf() bool {
 	return node.childrens == nil
 }
 
 // isFull 是否是满节点
-func (node *TreeNode) isFull(maxEntry int) bool {
+func (node *TreeNode[K, V]) isFull(maxEntry int) bool {
 	return len(node.entries) == maxEntry
 }
 
 // free free
-func (node *TreeNode) free() {
+func (node *TreeNode[K, V]) free() {
 	node.parent = nil
 	node.childrens = nil
 	node.entries = nil
 }
 
 // minimum 以当前节点为根节点，中序遍历后，树的最小节点
-func (node *TreeNode) minimum() *TreeNode {
+func (node *TreeNode[K, V]) minimum() *TreeNode[K, V] {
 	for !node.isLeaf() {
 		node = node.childrens[0]
 	}
@@ -122,7 +110,7 @@ func (node *TreeNode) minimum() *TreeNode {
 }
 
 // maximum 以当前节点为根节点，中序遍历后，树的最大节点
-func (node *TreeNode) maximum() *TreeNode {
+func (node *TreeNode[K, V]) maximum() *TreeNode[K, V] {
 	for !node.isLeaf() {
 		node = node.childrens[len(node.childrens)-1]
 	}
@@ -130,14 +118,53 @@ func (node *TreeNode) maximum() *TreeNode {
 	return node
 }
 
+// backward 以当前节点为根节点，按key降序遍历，yield返回false时停止遍历并返回false
+func (node *TreeNode[K, V]) backward(yield func(K, V) bool) bool {
+	for i := len(node.entries) - 1; i >= 0; i-- {
+		if !node.isLeaf() && !node.childrens[i+1].backward(yield) {
+			return false
+		}
+
+		if !yield(node.entries[i].GetKey(), node.entries[i].GetValue()) {
+			return false
+		}
+	}
+
+	if !node.isLeaf() {
+		return node.childrens[0].backward(yield)
+	}
+
+	return true
+}
+
+// unpack 获取entry的key和value，entry为nil时返回false
+func unpack[K, V any](entry *utils.TypedEntry[K, V]) (key K, val V, bFound bool) {
+	if entry == nil {
+		return key, val, false
+	}
+
+	return entry.GetKey(), entry.GetValue(), true
+}
+
+// reverse 反转list
+func reverse[K, V any](list []*utils.TypedEntry[K, V]) []*utils.TypedEntry[K, V] {
+	listLen := len(list)
+
+	for i := 0; i < listLen/2; i++ {
+		list[i], list[listLen-i-1] = list[listLen-i-1], list[i]
+	}
+
+	return list
+}
+
 // printBTreeNode printBTreeNode
-func (node *TreeNode) printBTreeNode(offset *int) (str string) {
-	if node.parent != nil {
-		if *offset == len(node.parent.entries) {
-			str = fmt.Sprintf("节点key: %v, \t此节点为父节点key[%v]的右节点\n", node.printBTreeNodeKeys(), node.parent.entries[*offset-1].GetKey())
+func (node *TreeNode[K, V]) printBTreeNode(parent *TreeNode[K, V], offset *int) (str string) {
+	if parent != nil {
+		if *offset == len(parent.entries) {
+			str = fmt.Sprintf("节点key: %v, \t此节点为父节点key[%v]的右节点\n", node.printBTreeNodeKeys(), parent.entries[*offset-1].GetKey())
 			*offset = 0
 		} else {
-			str = fmt.Sprintf("节点key: %v, \t此节点为父节点key[%v]的左节点\n", node.printBTreeNodeKeys(), node.parent.entries[*offset].GetKey())
+			str = fmt.Sprintf("节点key: %v, \t此节点为父节点key[%v]的左节点\n", node.printBTreeNodeKeys(), parent.entries[*offset].GetKey())
 			(*offset)++
 		}
 	} else {
@@ -152,7 +179,7 @@ func (node *TreeNode) printBTreeNode(offset *int) (str string) {
 }
 
 // printBTreeNodeKeys printBTreeNodeKeys
-func (node *TreeNode) printBTreeNodeKeys() string {
+func (node *TreeNode[K, V]) printBTreeNodeKeys() string {
 	keys := make([]string, 0, len(node.entries))
 
 	for _, v := range node.entries {
@@ -163,12 +190,12 @@ func (node *TreeNode) printBTreeNodeKeys() string {
 }
 
 // dot dot
-func (node *TreeNode) dot(comparator utils.Comparator, nName string, pName string) (dNode *dot.Node, dEdge *dot.Edge) {
+func (node *TreeNode[K, V]) dot(comparator func(a, b K) int, parent *TreeNode[K, V], nName string, pName string) (dNode *dot.Node, dEdge *dot.Edge) {
 	// 添加一个node
 	attrValues := make([]string, 0, len(node.entries))
 
 	for i, entry := range node.entries {
-		attrValues = append(attrValues, fmt.Sprintf("<f%d> | %d ", i, entry.GetKey()))
+		attrValues = append(attrValues, fmt.Sprintf("<f%d> | %v ", i, entry.GetKey()))
 	}
 
 	attr := "\"" + strings.Join(attrValues, "|") + fmt.Sprintf("| <f%d>", len(node.entries)) + "\""
@@ -180,8 +207,8 @@ func (node *TreeNode) dot(comparator utils.Comparator, nName string, pName strin
 	}
 
 	// 添加一个edge
-	if node.parent != nil {
-		pos, _ := node.parent.findLowerBoundKeyPosition(comparator, node.entries[0].GetKey())
+	if parent != nil {
+		pos, _ := parent.findLowerBoundKeyPosition(comparator, node.entries[0].GetKey())
 		dEdge = &dot.Edge{}
 		dEdge.Src = pName
 		dEdge.SrcPort = ":f" + fmt.Sprintf("%d", pos)
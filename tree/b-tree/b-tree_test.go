@@ -1,10 +1,20 @@
 package btree
 
 import (
+	"cmp"
 	"fmt"
+	"iter"
+	"maps"
 	"math/rand"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/map/orderedmap/conformance"
+	"github.com/asinglestep/gods/utils"
 )
 
 // 0, 1, 5, 2, 3, 6, 4, 7, 9, 8
@@ -12,30 +22,11 @@ import (
 //			1				5,		7
 //		0		2		4		6		8,	9
 
-type btreeComparator struct {
-}
-
-// Compare Compare
-func (tc btreeComparator) Compare(k1, k2 interface{}) int {
-	i1 := k1.(int)
-	i2 := k2.(int)
-
-	if i1 > i2 {
-		return 1
-	}
-
-	if i1 < i2 {
-		return -1
-	}
-
-	return 0
-}
-
 const DEGREE = 10
 
 // Insert Test
 func Test_BTreeRandInsert(t *testing.T) {
-	tree := NewTree(DEGREE, btreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -70,7 +61,7 @@ func Test_BatchBTreeRandInsert(t *testing.T) {
 	count := 1000
 
 	for i := 0; i < count; i++ {
-		tree := NewTree(DEGREE, btreeComparator{})
+		tree := NewTree(DEGREE, utils.IntComparator)
 		var num = 100
 
 		array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -87,7 +78,7 @@ func Test_BatchBTreeRandInsert(t *testing.T) {
 
 // Delete Test
 func Test_BTreeDelete(t *testing.T) {
-	tree := NewTree(DEGREE, btreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	array := []int{0, 1, 5, 2, 3, 6, 4, 7, 9, 8}
 
 	for _, v := range array {
@@ -138,7 +129,7 @@ func Test_BTreeDelete(t *testing.T) {
 }
 
 func Test_BTreeRandDelete(t *testing.T) {
-	tree := NewTree(DEGREE, btreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 1000000
 
 	insertArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -176,7 +167,7 @@ func Test_BatchBTreeRandDelete(t *testing.T) {
 	count := 1000
 
 	for i := 0; i < count; i++ {
-		tree := NewTree(DEGREE, btreeComparator{})
+		tree := NewTree(DEGREE, utils.IntComparator)
 		var num = 100
 
 		insertArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -200,7 +191,7 @@ func Test_BatchBTreeRandDelete(t *testing.T) {
 }
 
 func Test_BTreeSearch(t *testing.T) {
-	tree := NewTree(DEGREE, btreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 1000000
 
 	insertArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -222,7 +213,7 @@ func Test_BTreeSearch(t *testing.T) {
 func Test_BTreeeRangeSearch(t *testing.T) {
 	var num = 1000000
 	var diff = 1000
-	tree := NewTree(DEGREE, btreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 
 	insertArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
 	for _, v := range insertArray {
@@ -255,7 +246,7 @@ func Test_BTreeeRangeSearch(t *testing.T) {
 }
 
 func Test_BTreeSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(2, btreeComparator{})
+	tree := NewTree(2, utils.IntComparator)
 
 	tree.Insert(30, 30)
 	tree.Insert(20, 20)
@@ -285,7 +276,7 @@ func Test_BTreeSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_BTreeRandSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(DEGREE, btreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -310,3 +301,470 @@ func Test_BTreeRandSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
 		}
 	}
 }
+
+func Test_BTreeSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
+	tree := NewTree(2, utils.IntComparator)
+
+	tree.Insert(30, 30)
+	tree.Insert(20, 20)
+	tree.Insert(10, 10)
+	tree.Insert(40, 40)
+	tree.Insert(50, 50)
+	tree.Insert(100, 100)
+	tree.Insert(60, 60)
+	tree.Insert(80, 80)
+	tree.Insert(90, 90)
+
+	entries := tree.SearchRangeUpperBoundKeyWithLimit(85, 4)
+
+	verifArr := []int{40, 50, 60, 80}
+	for i, v := range entries {
+		if v.GetKey().(int) != verifArr[i] {
+			t.Fatalf("Test_BTreeSearchRangeUpperBoundKeyWithLimit err: v.GetKey().(int) != verifArr[%d], v.GetKey().(int): %v, verifArr[%d]: %v\n", i, v.GetKey().(int), i, verifArr[i])
+		}
+	}
+
+	if len(entries) != len(verifArr) {
+		t.Fatalf("Test_BTreeSearchRangeUpperBoundKeyWithLimit err: len(nodeList) != len(verifArr), len(nodeList): %v, len(verifArr): %v\n", len(entries), len(verifArr))
+	}
+
+	if entries := tree.SearchRangeUpperBoundKeyWithLimit(5, 4); len(entries) != 0 {
+		t.Fatalf("Test_BTreeSearchRangeUpperBoundKeyWithLimit err: want no entries less than 5, got %v\n", len(entries))
+	}
+}
+
+func Test_BTreeRandSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
+	tree := NewTree(DEGREE, utils.IntComparator)
+	var num = 1000000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+
+	for _, v := range array {
+		tree.Insert(v, v)
+	}
+
+	sKey := rand.New(rand.NewSource(time.Now().UnixNano())).Intn(num)
+	entries := tree.SearchRangeUpperBoundKeyWithLimit(sKey, 1000)
+	for i, entry := range entries {
+		if sKey-(len(entries)-i-1) != entry.GetKey().(int) {
+			t.Fatal("Test_BTreeRandSearchRangeUpperBoundKeyWithLimit err")
+		}
+	}
+
+	if len(entries) != min(sKey+1, 1000) {
+		t.Fatalf("Test_BTreeRandSearchRangeUpperBoundKeyWithLimit err: want %v entries, got %v\n", min(sKey+1, 1000), len(entries))
+	}
+}
+
+func Test_BTreeOrderedTree(t *testing.T) {
+	tree := NewOrderedTree[int, string](DEGREE)
+	var num = 100000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+	for _, v := range array {
+		tree.Insert(v, strconv.Itoa(v))
+	}
+
+	dArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num / 2)
+	for _, v := range dArray {
+		tree.Delete(v)
+	}
+
+	if !tree.Verify() {
+		t.Fatal("Test_BTreeOrderedTree err")
+	}
+
+	idx := num / 2
+	iter := NewIterator(tree)
+	for iter.Next() {
+		if iter.GetKey() != idx || iter.GetValue() != strconv.Itoa(idx) {
+			t.Fatalf("want %v, got %v: %v\n", idx, iter.GetKey(), iter.GetValue())
+		}
+
+		idx++
+	}
+
+	if idx != num {
+		t.Fatalf("want %v, got %v\n", num, idx)
+	}
+}
+
+func Test_BTreeConformance(t *testing.T) {
+	for _, degree := range []int{2, DEGREE} {
+		t.Run(strconv.Itoa(degree), func(t *testing.T) {
+			conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+				return NewOrderedTree[int, int](degree)
+			})
+		})
+	}
+}
+
+func Test_SyncBTreeConformance(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewSyncTree(NewOrderedTree[int, int](DEGREE))
+	})
+}
+
+func Test_SyncBTreeConcurrent(t *testing.T) {
+	conformance.RunConcurrent(t, NewSyncTree(NewOrderedTree[int, int](DEGREE)))
+}
+
+func Test_SyncBTreeSearch(t *testing.T) {
+	tree := NewSyncTree(NewOrderedTree[int, int](DEGREE))
+	for i := 0; i < 100; i++ {
+		tree.Insert(i*2, i)
+	}
+
+	if found := tree.Search(20); found == nil || found.GetKey() != 20 || found.GetValue() != 10 {
+		t.Fatalf("Search(20) want 20: 10, got %v\n", found)
+	}
+
+	if found := tree.Search(21); found != nil {
+		t.Fatalf("Search(21) want nil, got %v\n", found)
+	}
+
+	keys := func(list []*utils.TypedEntry[int, int]) []int {
+		res := []int{}
+		for _, e := range list {
+			res = append(res, e.GetKey())
+		}
+
+		return res
+	}
+
+	if got := keys(tree.SearchRange(9, 20)); !slices.Equal(got, []int{10, 12, 14, 16, 18, 20}) {
+		t.Fatalf("SearchRange(9, 20) got %v\n", got)
+	}
+
+	if got := keys(tree.SearchRangeLowerBoundKeyWithLimit(195, 5)); !slices.Equal(got, []int{196, 198}) {
+		t.Fatalf("SearchRangeLowerBoundKeyWithLimit(195, 5) got %v\n", got)
+	}
+
+	if got := keys(tree.SearchRangeUpperBoundKeyWithLimit(5, 5)); !slices.Equal(got, []int{0, 2, 4}) {
+		t.Fatalf("SearchRangeUpperBoundKeyWithLimit(5, 5) got %v\n", got)
+	}
+
+	// 返回的entry是拷贝，修改拷贝不影响树
+	tree.Search(20).SetValue(-1)
+	tree.SearchRange(0, 20)[0].SetValue(-1)
+	if v, _ := tree.Get(20); v != 10 {
+		t.Fatalf("Get(20) want 10, got %v\n", v)
+	}
+
+	if v, _ := tree.Get(0); v != 0 {
+		t.Fatalf("Get(0) want 0, got %v\n", v)
+	}
+
+	// 读取返回的拷贝时不持有锁，同时修改树不会产生数据竞争，使用go test -race验证
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 2000; i++ {
+			tree.Put(i%200, -i)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		for _, e := range tree.SearchRange(0, 200) {
+			_ = e.GetValue()
+		}
+	}
+
+	wg.Wait()
+}
+
+func Test_BTreeBuildFromSorted(t *testing.T) {
+	for _, degree := range []int{2, 3, 5} {
+		for _, fill := range []float64{0, 0.1, 0.5, 0.75, 1} {
+			for _, n := range []int{0, 1, 2, 3, 4, 7, 8, 100, 1000, 5000} {
+				vals := rand.Perm(n)
+				tree, ok := BuildFromSorted(degree, cmp.Compare[int], slices.All(vals), fill)
+				if !ok || !tree.Verify() || tree.Len() != n {
+					t.Fatalf("degree %v fill %v n %v: BuildFromSorted Verify Error, got %v entries\n", degree, fill, n, tree.Len())
+				}
+
+				idx := 0
+				for k, v := range tree.All() {
+					if k != idx || v != vals[idx] {
+						t.Fatalf("degree %v fill %v n %v: want %v %v, got %v %v\n", degree, fill, n, idx, vals[idx], k, v)
+					}
+
+					idx++
+				}
+
+				// 创建的树可以继续插入、删除
+				for i := 0; i < n; i += 2 {
+					tree.Delete(i)
+					tree.Insert(n+i, i)
+				}
+
+				if !tree.Verify() || tree.Len() != n {
+					t.Fatalf("degree %v fill %v n %v: Verify Error after Insert and Delete\n", degree, fill, n)
+				}
+			}
+		}
+	}
+
+	unsorted := func(yield func(int, int) bool) {
+		for _, k := range []int{1, 3, 2} {
+			if !yield(k, k) {
+				return
+			}
+		}
+	}
+
+	if _, ok := BuildFromSorted(3, cmp.Compare[int], unsorted, 1); ok {
+		t.Fatalf("unsorted input should return false\n")
+	}
+
+	// 比较函数只保证结果的符号
+	sub := func(a, b int) int { return a - b }
+	step := func(keys ...int) iter.Seq2[int, int] {
+		return func(yield func(int, int) bool) {
+			for _, k := range keys {
+				if !yield(k, k) {
+					return
+				}
+			}
+		}
+	}
+
+	if tree, ok := BuildFromSorted(3, sub, step(0, 3, 6, 9, 12), 1); !ok || !tree.Verify() || tree.Len() != 5 {
+		t.Fatalf("sorted input with a - b comparator should return true\n")
+	}
+
+	if _, ok := BuildFromSorted(3, sub, step(0, 9, 3), 1); ok {
+		t.Fatalf("unsorted input with a - b comparator should return false\n")
+	}
+}
+
+func Benchmark_BTreeBuildFromSorted(b *testing.B) {
+	vals := make([]int, 100000)
+	for i := 0; i < b.N; i++ {
+		BuildFromSorted(32, cmp.Compare[int], slices.All(vals), 1)
+	}
+}
+
+func Test_BTreeIteratorPrev(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	for _, degree := range []int{2, 3, DEGREE} {
+		tree := NewOrderedTree[int, int](degree)
+
+		// 空树
+		if NewReverseIterator(tree).Prev() || NewIteratorUpperBoundKey(tree, 0).Prev() || NewIteratorLowerBoundKey(tree, 0).Next() {
+			t.Fatalf("degree %v: empty tree iterator should return false\n", degree)
+		}
+
+		// 偶数key
+		num := 2000
+		for _, v := range r.Perm(num) {
+			tree.Insert(2*v, v)
+		}
+
+		idx := num - 1
+		iter := NewReverseIterator(tree)
+		for iter.Prev() {
+			if iter.GetKey() != 2*idx || iter.GetValue() != idx {
+				t.Fatalf("seed %v degree %v: reverse want %v, got %v\n", seed, degree, 2*idx, iter.GetKey())
+			}
+
+			idx--
+		}
+
+		if idx != -1 {
+			t.Fatalf("seed %v degree %v: reverse iterator stopped at %v\n", seed, degree, idx)
+		}
+
+		for i := 0; i < 200; i++ {
+			key := r.Intn(2*num+2) - 1
+
+			// 小于等于key的最大的偶数
+			want := min(key-(key%2+2)%2, 2*num-2)
+			iter := NewIteratorUpperBoundKey(tree, key)
+			if ok := iter.Prev(); ok != (want >= 0) || (ok && iter.GetKey() != want) {
+				t.Fatalf("seed %v degree %v: UpperBound(%v) want %v\n", seed, degree, key, want)
+			}
+
+			// 大于等于key的最小的偶数
+			want = key + (key%2+2)%2
+			iter = NewIteratorLowerBoundKey(tree, key)
+			if ok := iter.Next(); ok != (want < 2*num) || (ok && iter.GetKey() != want) {
+				t.Fatalf("seed %v degree %v: LowerBound(%v) want %v\n", seed, degree, key, want)
+			}
+
+			if !iter.Next() {
+				continue
+			}
+
+			// 交替调用Next和Prev
+			cur := iter.GetKey()
+			for j := 0; j < 10 && iter.Prev(); j++ {
+				if iter.GetKey() != cur-2 {
+					t.Fatalf("seed %v degree %v: Prev want %v, got %v\n", seed, degree, cur-2, iter.GetKey())
+				}
+
+				if !iter.Next() || iter.GetKey() != cur {
+					t.Fatalf("seed %v degree %v: Next want %v\n", seed, degree, cur)
+				}
+
+				if !iter.Next() {
+					break
+				}
+
+				cur = iter.GetKey()
+			}
+		}
+	}
+}
+
+func Test_BTreeClone(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	// 验证tree和m中的key、value相同
+	check := func(name string, tree *Tree[int, int], m map[int]int) {
+		if !tree.Verify() {
+			t.Fatalf("seed %v: %v verify failed\n", seed, name)
+		}
+
+		keys := make([]int, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		i := 0
+		for k, v := range tree.All() {
+			if i >= len(keys) || k != keys[i] || v != m[k] {
+				t.Fatalf("seed %v: %v key %v value %v is not expected\n", seed, name, k, v)
+			}
+
+			i++
+		}
+
+		if i != len(keys) || tree.Len() != len(keys) {
+			t.Fatalf("seed %v: %v want %v keys, got %v\n", seed, name, len(keys), i)
+		}
+	}
+
+	// 随机插入、更新、删除
+	mutate := func(tree *Tree[int, int], m map[int]int, n int) {
+		for i := 0; i < n; i++ {
+			key := r.Intn(4000)
+			switch r.Intn(3) {
+			case 0:
+				if _, ok := m[key]; !ok {
+					tree.Insert(key, key)
+					m[key] = key
+				}
+			case 1:
+				val := r.Int()
+				tree.Put(key, val)
+				m[key] = val
+			default:
+				tree.Delete(key)
+				delete(m, key)
+			}
+		}
+	}
+
+	for _, degree := range []int{2, 3, DEGREE} {
+		tree := NewOrderedTree[int, int](degree)
+		m := map[int]int{}
+		mutate(tree, m, 5000)
+
+		trees := []*Tree[int, int]{tree}
+		refs := []map[int]int{m}
+
+		// 克隆之后分别修改每棵树，再从修改后的树继续克隆
+		for round := 0; round < 5; round++ {
+			for i := range len(trees) {
+				trees = append(trees, trees[i].Clone())
+				refs = append(refs, maps.Clone(refs[i]))
+			}
+
+			for i := range trees {
+				mutate(trees[i], refs[i], 500)
+			}
+
+			for i := range trees {
+				check(fmt.Sprintf("degree %v round %v tree %v", degree, round, i), trees[i], refs[i])
+			}
+		}
+
+		// 清空克隆的树，原来的树不受影响
+		clone := tree.Clone()
+		for clone.Len() != 0 {
+			clone.PopMin()
+		}
+
+		check(fmt.Sprintf("degree %v emptied clone", degree), clone, map[int]int{})
+		check(fmt.Sprintf("degree %v origin", degree), tree, refs[0])
+	}
+}
+
+func Test_BTreeCloneConcurrent(t *testing.T) {
+	tree := NewOrderedTree[int, int](3)
+	for _, v := range rand.Perm(10000) {
+		tree.Insert(v, v)
+	}
+
+	// 两棵树共享节点，在不同的goroutine中同时修改，使用-race运行时可以检查是否修改了共享的节点
+	clone := tree.Clone()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for i := 0; i < 10000; i += 2 {
+			clone.Delete(i)
+			clone.Put(i+1, -i)
+		}
+	}()
+
+	for i := 0; i < 10000; i += 3 {
+		tree.Delete(i)
+		tree.Insert(i+10000, i)
+	}
+
+	<-done
+
+	if !tree.Verify() || !clone.Verify() {
+		t.Fatalf("verify failed\n")
+	}
+
+	for i := 0; i < 10000; i++ {
+		if _, ok := tree.Get(i); ok != (i%3 != 0) {
+			t.Fatalf("tree: key %v want %v\n", i, i%3 != 0)
+		}
+
+		if val, ok := clone.Get(i); ok != (i%2 != 0) || (ok && val != -(i-1)) {
+			t.Fatalf("clone: key %v value %v is not expected\n", i, val)
+		}
+	}
+}
+
+func Test_BTreeCloneEntry(t *testing.T) {
+	tree := NewOrderedTree[int, int](DEGREE)
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i)
+	}
+
+	// 两棵树共享entry，修改Search、SearchRange等方法返回的entry不能影响另一棵树
+	clone := tree.Clone()
+	clone.Search(1).SetValue(-1)
+	tree.Search(2).SetValue(-1)
+	clone.SearchRange(3, 3)[0].SetValue(-1)
+	tree.SearchRangeLowerBoundKeyWithLimit(4, 1)[0].SetValue(-1)
+	clone.SearchRangeUpperBoundKeyWithLimit(5, 1)[0].SetValue(-1)
+	for _, tr := range []*Tree[int, int]{tree, clone} {
+		for i := 0; i < 100; i++ {
+			if v, ok := tr.Get(i); !ok || v != i {
+				t.Fatalf("Get(%v) want %v, got %v %v\n", i, i, v, ok)
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package btree
+
+import (
+	"iter"
+	"math"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// BuildFromSorted 由按key升序排列的seq创建b树，时间复杂度O(n)
+//
+// @param
+// t: 度数
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// seq: 按key升序排列的key、value，key不能重复
+// fillFactor: 节点的填充率，每个节点约有fillFactor*(2t-1)个entry，不少于t-1个，不在(0, 1]之间时为1
+//
+// @return
+// seq没有按key升序排列时返回false
+func BuildFromSorted[K, V any](t int, comparator func(a, b K) int, seq iter.Seq2[K, V], fillFactor float64) (*Tree[K, V], bool) {
+	tree := NewTreeFunc[K, V](t, comparator)
+
+	var entries []*utils.TypedEntry[K, V]
+	for key, val := range seq {
+		if len(entries) > 0 && tree.comparator(entries[len(entries)-1].GetKey(), key) >= 0 {
+			return nil, false
+		}
+
+		entries = append(entries, utils.NewTypedEntry(key, val))
+	}
+
+	b := newBuilder(tree, len(entries), fillFactor)
+	tree.root = b.build(entries, b.height, true)
+	tree.size = len(entries)
+
+	return tree, true
+}
+
+// builder 自顶向下创建b树，先确定树的高度，再把entry平均分配到每个子树中
+type builder[K, V any] struct {
+	tree   *Tree[K, V]
+	height int   // 树的高度，只有根节点时为0
+	min    []int // min[h]: 高度为h的非根子树最少有多少个entry
+	max    []int // max[h]: 高度为h的子树最多有多少个entry
+	fill   []int // fill[h]: 高度为h的子树按填充率有多少个entry
+}
+
+// newBuilder 根据entry数n和填充率计算树的高度
+func newBuilder[K, V any](tree *Tree[K, V], n int, fillFactor float64) *builder[K, V] {
+	if fillFactor <= 0 || fillFactor > 1 {
+		fillFactor = 1
+	}
+
+	keys := int(math.Ceil(fillFactor * float64(tree.maxEntry)))
+	keys = max(tree.minEntry, min(keys, tree.maxEntry))
+
+	b := &builder[K, V]{tree: tree}
+	b.min = append(b.min, tree.minEntry)
+	b.max = append(b.max, tree.maxEntry)
+	b.fill = append(b.fill, keys)
+
+	// 按填充率能放下n个entry的最小高度
+	for b.fill[b.height] < n {
+		h := b.height
+		b.min = append(b.min, tree.minEntry+(tree.minEntry+1)*b.min[h])
+		b.max = append(b.max, tree.maxEntry+(tree.maxEntry+1)*b.max[h])
+		b.fill = append(b.fill, keys+(keys+1)*b.fill[h])
+		b.height++
+	}
+
+	// 根节点至少有2个子节点，entry数不够时降低高度
+	for b.height > 0 && n < 2*b.min[b.height-1]+1 {
+		b.height--
+	}
+
+	return b
+}
+
+// build 创建高度为h的子树，返回子树的根节点
+func (b *builder[K, V]) build(entries []*utils.TypedEntry[K, V], h int, bRoot bool) *TreeNode[K, V] {
+	node := NewNode[K, V]()
+	if h == 0 {
+		node.entries = entries[:len(entries):len(entries)]
+		return node
+	}
+
+	// k个子节点之间有k-1个entry，其余的entry平均分配到子节点中
+	k := b.childrens(len(entries), h, bRoot)
+	size, rem := (len(entries)-k+1)/k, (len(entries)-k+1)%k
+
+	node.entries = make([]*utils.TypedEntry[K, V], 0, k-1)
+	node.childrens = make([]*TreeNode[K, V], 0, k)
+
+	lo := 0
+	for i := 0; i < k; i++ {
+		hi := lo + size
+		if i < rem {
+			hi++
+		}
+
+		children := b.build(entries[lo:hi], h-1, false)
+		children.parent = node
+		node.childrens = append(node.childrens, children)
+
+		if i < k-1 {
+			node.entries = append(node.entries, entries[hi])
+			hi++
+		}
+
+		lo = hi
+	}
+
+	return node
+}
+
+// childrens 高度为h、有n个entry的子树的根节点的子节点数
+//
+// 子节点数按填充率计算，并且保证每个子树的entry数在[min[h-1], max[h-1]]之间
+func (b *builder[K, V]) childrens(n, h int, bRoot bool) int {
+	lo, hi := b.tree.minEntry+1, b.tree.maxEntry+1
+	if bRoot {
+		lo = 2
+	}
+
+	// k个子树最多有k*max[h-1]个entry，最少有k*min[h-1]个entry
+	lo = max(lo, (n+1+b.max[h-1])/(b.max[h-1]+1))
+	hi = min(hi, (n+1)/(b.min[h-1]+1))
+
+	k := (n + 1 + b.fill[h-1]) / (b.fill[h-1] + 1)
+	return max(lo, min(k, hi))
+}
@@ -0,0 +1,77 @@
+package btree
+
+import (
+	"slices"
+	"sync/atomic"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// generation 写时复制的版本号，每次Clone都会为两棵树分配新的版本号
+var generation atomic.Uint64
+
+// Clone 写时复制地克隆b树，时间复杂度O(1)
+//
+// 克隆之后两棵树共享所有节点，任意一棵树修改节点时，先从根节点开始拷贝路径上不属于自己的节点，再修改拷贝的节点，
+// 所以一棵树的修改不会影响另一棵树。两棵树可以在不同的goroutine中分别使用，同一棵树的并发访问仍然需要调用方加锁
+//
+// 两棵树共享entry，克隆之后Search、SearchRange等方法返回entry的拷贝，修改返回的entry不会影响任意一棵树，
+// 克隆之前返回的entry仍然和两棵树共享，不能再修改
+func (t *Tree[K, V]) Clone() *Tree[K, V] {
+	clone := *t
+	t.gen = generation.Add(1)
+	clone.gen = generation.Add(1)
+
+	return &clone
+}
+
+// export 返回给调用方的entry，树被克隆过时entry可能和其他树共享，返回entry的拷贝
+func (t *Tree[K, V]) export(entry *utils.TypedEntry[K, V]) *utils.TypedEntry[K, V] {
+	if entry == nil || t.gen == 0 {
+		return entry
+	}
+
+	return utils.NewTypedEntry(entry.GetKey(), entry.GetValue())
+}
+
+// newNode 创建一个属于当前树的节点
+func (t *Tree[K, V]) newNode() *TreeNode[K, V] {
+	node := NewNode[K, V]()
+	node.gen = t.gen
+
+	return node
+}
+
+// mutableRoot 返回可以修改的根节点，根节点不属于当前树时先拷贝根节点
+func (t *Tree[K, V]) mutableRoot() *TreeNode[K, V] {
+	if t.root.gen != t.gen {
+		t.root = t.root.clone(t.gen)
+	}
+
+	return t.root
+}
+
+// mutableChild 返回node中pos位置可以修改的子节点，node必须属于当前树
+//
+// 子节点不属于当前树时拷贝子节点，拷贝的节点替换node中原来的子节点
+func (t *Tree[K, V]) mutableChild(node *TreeNode[K, V], pos int) *TreeNode[K, V] {
+	children := node.childrens[pos]
+	if children.gen != t.gen {
+		children = children.clone(t.gen)
+		children.parent = node
+		node.childrens[pos] = children
+	}
+
+	return children
+}
+
+// clone 拷贝节点，拷贝的节点属于版本号为gen的树，和原节点共享子节点和entry
+//
+// 拷贝的节点没有父节点，由调用方设置
+func (node *TreeNode[K, V]) clone(gen uint64) *TreeNode[K, V] {
+	return &TreeNode[K, V]{
+		gen:       gen,
+		childrens: slices.Clone(node.childrens),
+		entries:   slices.Clone(node.entries),
+	}
+}
@@ -5,80 +5,242 @@ import (
 )
 
 // Iterator Iterator
-type Iterator struct {
-	tree     *Tree
-	node     *TreeNode
-	entry    *utils.Entry
-	entryPos int
+//
+// 迭代器记录从根节点到当前entry的路径，不使用节点的父节点，所以可以迭代和其他树共享节点的树
+type Iterator[K, V any] struct {
+	tree   *Tree[K, V]
+	stack  []iteratorFrame[K, V]   // 从根节点到当前entry所在节点的路径，没有entry时为空
+	entry  *utils.TypedEntry[K, V] // 当前entry
+	bBegin bool                    // 第一次调用Next或Prev时，返回起始位置的entry
+}
+
+// iteratorFrame 路径上的节点
+type iteratorFrame[K, V any] struct {
+	node *TreeNode[K, V]
+	pos  int // 最后一个节点为当前entry在节点中的位置，其余节点为路径上的子节点在节点中的位置
 }
 
 // NewIterator NewIterator
-func NewIterator(tree *Tree) *Iterator {
-	iter := &Iterator{}
-	iter.tree = tree
-	iter.node = iter.tree.minimum()
-	iter.entry = nil
-	iter.entryPos = 0
+func NewIterator[K, V any](tree *Tree[K, V]) *Iterator[K, V] {
+	iter := newIterator(tree)
+	iter.pushMinimum(tree.root)
+	iter.moveTo()
+
+	return iter
+}
+
+// NewReverseIterator 从最大key开始的迭代器，调用Prev按key降序迭代
+func NewReverseIterator[K, V any](tree *Tree[K, V]) *Iterator[K, V] {
+	iter := newIterator(tree)
+	iter.pushMaximum(tree.root)
+	iter.moveTo()
 
 	return iter
 }
 
 // NewIteratorLowerBoundKey 从大于等于key的位置开始迭代
-func NewIteratorLowerBoundKey(tree *Tree, key interface{}) *Iterator {
-	iter := &Iterator{}
-	iter.tree = tree
+func NewIteratorLowerBoundKey[K, V any](tree *Tree[K, V], key K) *Iterator[K, V] {
+	iter := newIterator(tree)
+	node, pos, bFound := iter.pushLookup(key)
+	if bFound || pos < len(node.entries) {
+		iter.moveTo()
+		return iter
+	}
 
-	node, pos, _ := tree.lookup(tree.root, key)
-	if node.isLeaf() {
-		iter.node = node
-		iter.entryPos = pos
-		if pos != len(iter.node.entries) {
-			// 从当前entry开始
-			iter.entry = iter.node.entries[pos]
-		} else {
-			// 从最后一个entry开始
-			iter.entry = iter.node.entries[pos-1]
+	// 叶子节点中的key都小于key，从最后一个entry的下一个entry开始，空树时没有起始位置
+	iter.top().pos--
+	if iter.moveTo() {
+		iter.next()
+	}
+
+	return iter
+}
+
+// NewIteratorUpperBoundKey 从小于等于key的位置开始迭代，调用Prev按key降序迭代
+func NewIteratorUpperBoundKey[K, V any](tree *Tree[K, V], key K) *Iterator[K, V] {
+	iter := newIterator(tree)
+	_, pos, bFound := iter.pushLookup(key)
+	if bFound || pos > 0 {
+		if !bFound {
+			iter.top().pos--
 		}
-	} else {
-		// 从 以当前节点为根节点，中序遍历后，树的最大节点 开始
-		iter.node = node.childrens[pos].maximum()
-		iter.entryPos = len(iter.node.entries)
-		iter.entry = iter.node.entries[len(iter.node.entries)-1]
+
+		iter.moveTo()
+		return iter
+	}
+
+	// 叶子节点中的key都大于key，从第一个entry的上一个entry开始，空树时没有起始位置
+	if iter.moveTo() {
+		iter.prev()
 	}
 
 	return iter
 }
 
+// newIterator 创建一个没有起始位置的迭代器
+func newIterator[K, V any](tree *Tree[K, V]) *Iterator[K, V] {
+	iter := &Iterator[K, V]{}
+	iter.tree = tree
+	iter.bBegin = true
+
+	return iter
+}
+
 // Next Next
-func (iter *Iterator) Next() bool {
-	if iter.entryPos < len(iter.node.entries) {
-		iter.entry = iter.node.entries[iter.entryPos]
-		iter.entryPos++
-		return true
-	}
-
-	parent := iter.node.parent
-	for parent != nil {
-		pos, _ := parent.findLowerBoundKeyPosition(iter.tree.comparator, iter.entry.GetKey())
-		if pos+1 < len(parent.childrens) {
-			iter.entry = parent.entries[pos]
-			iter.node = parent.childrens[pos+1].minimum()
-			iter.entryPos = 0
-			return true
+func (iter *Iterator[K, V]) Next() bool {
+	if iter.entry == nil {
+		return false
+	}
+
+	if iter.bBegin {
+		iter.bBegin = false
+	} else {
+		iter.next()
+	}
+
+	return iter.entry != nil
+}
+
+// Prev Prev
+func (iter *Iterator[K, V]) Prev() bool {
+	if iter.entry == nil {
+		return false
+	}
+
+	if iter.bBegin {
+		iter.bBegin = false
+	} else {
+		iter.prev()
+	}
+
+	return iter.entry != nil
+}
+
+// next 移动到下一个entry，没有下一个entry时entry为nil
+func (iter *Iterator[K, V]) next() {
+	top := iter.top()
+	if !top.node.isLeaf() {
+		// 右侧子树的最小entry
+		top.pos++
+		iter.pushMinimum(top.node.childrens[top.pos])
+		iter.moveTo()
+		return
+	}
+
+	if top.pos+1 < len(top.node.entries) {
+		top.pos++
+		iter.moveTo()
+		return
+	}
+
+	// 向上查找第一个右侧有entry的子节点，子节点右侧的entry大于当前key
+	iter.stack = iter.stack[:len(iter.stack)-1]
+	for len(iter.stack) != 0 && iter.top().pos == len(iter.top().node.entries) {
+		iter.stack = iter.stack[:len(iter.stack)-1]
+	}
+
+	iter.moveTo()
+}
+
+// prev 移动到上一个entry，没有上一个entry时entry为nil
+func (iter *Iterator[K, V]) prev() {
+	top := iter.top()
+	if !top.node.isLeaf() {
+		// 左侧子树的最大entry
+		iter.pushMaximum(top.node.childrens[top.pos])
+		iter.moveTo()
+		return
+	}
+
+	if top.pos > 0 {
+		top.pos--
+		iter.moveTo()
+		return
+	}
+
+	// 向上查找第一个左侧有entry的子节点，子节点左侧的entry小于当前key
+	iter.stack = iter.stack[:len(iter.stack)-1]
+	for len(iter.stack) != 0 && iter.top().pos == 0 {
+		iter.stack = iter.stack[:len(iter.stack)-1]
+	}
+
+	if len(iter.stack) != 0 {
+		iter.top().pos--
+	}
+
+	iter.moveTo()
+}
+
+// pushMinimum 将node到以node为根节点的子树的最小entry的路径加入到stack
+func (iter *Iterator[K, V]) pushMinimum(node *TreeNode[K, V]) {
+	for !node.isLeaf() {
+		iter.stack = append(iter.stack, iteratorFrame[K, V]{node, 0})
+		node = node.childrens[0]
+	}
+
+	iter.stack = append(iter.stack, iteratorFrame[K, V]{node, 0})
+}
+
+// pushMaximum 将node到以node为根节点的子树的最大entry的路径加入到stack
+func (iter *Iterator[K, V]) pushMaximum(node *TreeNode[K, V]) {
+	for !node.isLeaf() {
+		iter.stack = append(iter.stack, iteratorFrame[K, V]{node, len(node.childrens) - 1})
+		node = node.childrens[len(node.childrens)-1]
+	}
+
+	iter.stack = append(iter.stack, iteratorFrame[K, V]{node, len(node.entries) - 1})
+}
+
+// pushLookup 将根节点到key所在节点的路径加入到stack，没找到时为根节点到叶子节点的路径
+//
+// @return
+// node: key所在的节点，没找到时为叶子节点
+// pos: key在节点中的位置，没找到时为叶子节点中第一个大于key的位置
+func (iter *Iterator[K, V]) pushLookup(key K) (node *TreeNode[K, V], pos int, bFound bool) {
+	node = iter.tree.root
+
+	for {
+		pos, bFound = node.findLowerBoundKeyPosition(iter.tree.comparator, key)
+		iter.stack = append(iter.stack, iteratorFrame[K, V]{node, pos})
+		if bFound || node.isLeaf() {
+			return node, pos, bFound
 		}
 
-		parent = parent.parent
+		node = node.childrens[pos]
+	}
+}
+
+// top stack中的最后一个节点
+func (iter *Iterator[K, V]) top() *iteratorFrame[K, V] {
+	return &iter.stack[len(iter.stack)-1]
+}
+
+// moveTo 移动到stack中最后一个节点的pos位置的entry，stack为空或者pos不在节点中时没有entry
+//
+// @return
+// 是否有entry
+func (iter *Iterator[K, V]) moveTo() bool {
+	iter.entry = nil
+	if len(iter.stack) == 0 {
+		return false
+	}
+
+	top := iter.top()
+	if top.pos < 0 || top.pos >= len(top.node.entries) {
+		iter.stack = iter.stack[:0]
+		return false
 	}
 
-	return false
+	iter.entry = top.node.entries[top.pos]
+	return true
 }
 
 // GetKey GetKey
-func (iter *Iterator) GetKey() interface{} {
+func (iter *Iterator[K, V]) GetKey() K {
 	return iter.entry.GetKey()
 }
 
 // GetValue GetValue
-func (iter *Iterator) GetValue() interface{} {
+func (iter *Iterator[K, V]) GetValue() V {
 	return iter.entry.GetValue()
 }
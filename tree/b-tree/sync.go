@@ -0,0 +1,112 @@
+package btree
+
+import (
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/utils"
+)
+
+// SyncTree 并发安全的b树，读操作可以并行执行，Range、All、Backward、Iterator遍历时不持有锁，见orderedmap.Sync
+//
+// Search、SearchRange等查找方法在持有读锁时拷贝entry，返回的entry不在树中，读取时不需要持有锁，修改也不会影响树
+type SyncTree[K, V any] struct {
+	*orderedmap.Sync[K, V]
+	tree *Tree[K, V]
+}
+
+// NewSyncTree 创建一个并发安全的b树，tree交给SyncTree之后不能再直接使用
+func NewSyncTree[K, V any](tree *Tree[K, V]) *SyncTree[K, V] {
+	return &SyncTree[K, V]{
+		Sync: orderedmap.NewSync[K, V](tree),
+		tree: tree,
+	}
+}
+
+// Insert 插入
+func (t *SyncTree[K, V]) Insert(key K, val V) {
+	t.Put(key, val)
+}
+
+// Verify 验证b树的性质
+func (t *SyncTree[K, V]) Verify() (bOK bool) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		bOK = t.tree.Verify()
+	})
+
+	return bOK
+}
+
+// String String
+func (t *SyncTree[K, V]) String() (str string) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		str = t.tree.String()
+	})
+
+	return str
+}
+
+// Clone 写时复制地克隆b树，返回的树不是并发安全的，修改返回的树不会影响SyncTree，反之亦然
+func (t *SyncTree[K, V]) Clone() (clone *Tree[K, V]) {
+	// Clone会修改tree的版本号
+	t.Update(func(orderedmap.OrderedMap[K, V]) {
+		clone = t.tree.Clone()
+	})
+
+	return clone
+}
+
+// Search 查找key对应的entry，返回entry的拷贝
+func (t *SyncTree[K, V]) Search(key K) (entry *utils.TypedEntry[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		if found := t.tree.Search(key); found != nil {
+			entry = utils.NewTypedEntry(found.GetKey(), found.GetValue())
+		}
+	})
+
+	return entry
+}
+
+// SearchRange 查找key在[min, max]之间的entry，返回entry的拷贝
+func (t *SyncTree[K, V]) SearchRange(min, max K) (entries []*utils.TypedEntry[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		entries = snapshot(t.tree.SearchRange(min, max))
+	})
+
+	return entries
+}
+
+// SearchRangeLowerBoundKeyWithLimit 查找大于等于key的limit个entry，返回entry的拷贝
+func (t *SyncTree[K, V]) SearchRangeLowerBoundKeyWithLimit(key K, limit int64) (entries []*utils.TypedEntry[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		entries = snapshot(t.tree.SearchRangeLowerBoundKeyWithLimit(key, limit))
+	})
+
+	return entries
+}
+
+// SearchRangeUpperBoundKeyWithLimit 查找小于等于key的limit个entry，按key升序返回entry的拷贝
+func (t *SyncTree[K, V]) SearchRangeUpperBoundKeyWithLimit(key K, limit int64) (entries []*utils.TypedEntry[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		entries = snapshot(t.tree.SearchRangeUpperBoundKeyWithLimit(key, limit))
+	})
+
+	return entries
+}
+
+// Dot 持有读锁生成b树的图片
+func (t *SyncTree[K, V]) Dot() (err error) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		err = t.tree.Dot()
+	})
+
+	return err
+}
+
+// snapshot 拷贝entries中的每个entry，调用方读取拷贝时不需要持有锁，修改拷贝也不会影响树
+func snapshot[K, V any](entries []*utils.TypedEntry[K, V]) []*utils.TypedEntry[K, V] {
+	list := make([]*utils.TypedEntry[K, V], 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, utils.NewTypedEntry(entry.GetKey(), entry.GetValue()))
+	}
+
+	return list
+}
@@ -2,23 +2,26 @@ package bptree
 
 import (
 	"bytes"
+	"cmp"
 	"container/list"
 	"fmt"
+	"iter"
 	"os/exec"
 	"runtime"
 
 	dot "github.com/asinglestep/godot"
+	"github.com/asinglestep/gods/map/orderedmap"
 	"github.com/asinglestep/gods/utils"
 )
 
 // iNode iNode
-type iNode interface {
+type iNode[K, V any] interface {
 	// split 分裂节点
 	// @return
-	split(*Tree) iNode
+	split(*Tree[K, V]) iNode[K, V]
 
 	// adjacent 获取相邻节点
-	adjacent(*Tree) iNode
+	adjacent(*Tree[K, V]) iNode[K, V]
 
 	// moveKey 移动当前节点的key到相邻节点中或者将相邻节点的key移到当前节点中
 	//
@@ -27,7 +30,7 @@ type iNode interface {
 	//
 	// @return
 	// 当前节点的父节点
-	moveKey(t *Tree, adj iNode) *TreeNode
+	moveKey(t *Tree[K, V], adj iNode[K, V]) *TreeNode[K, V]
 
 	// merge 将相邻节点和当前节点合并
 	//
@@ -36,25 +39,25 @@ type iNode interface {
 	//
 	// @return
 	// 当前节点的父节点
-	merge(t *Tree, adj iNode) iNode
+	merge(t *Tree[K, V], adj iNode[K, V]) iNode[K, V]
 
 	// setParent 设置父节点
 	//
 	// @param
 	// 父节点
-	setParent(*TreeNode)
+	setParent(*TreeNode[K, V])
 
 	// getParent 获取父节点
 	//
 	// @return
 	// 当前节点的父节点
-	getParent() *TreeNode
+	getParent() *TreeNode[K, V]
 
 	// findKeyPosition 找到key的位置
-	findKeyPosition(comparator utils.Comparator, key interface{}) (pos int, bFound bool)
+	findKeyPosition(comparator func(a, b K) int, key K) (pos int, bFound bool)
 
 	// getPosKey 获取pos位置的key
-	getPosKey(pos int) interface{}
+	getPosKey(pos int) K
 
 	// getKeys 获取节点key的数量
 	getKeys() int
@@ -65,47 +68,85 @@ type iNode interface {
 	// isFull 节点是否是满节点
 	isFull(int) bool
 
+	// getGen 获取节点所属的树的版本号
+	getGen() uint64
+
+	// clone 拷贝节点，拷贝的节点属于版本号为gen的树，没有父节点
+	clone(gen uint64) iNode[K, V]
+
 	// verify 验证节点
-	verify(*Tree) bool
+	//
+	// @param
+	// parent: 父节点，根节点为nil
+	verify(t *Tree[K, V], parent *TreeNode[K, V]) bool
 
 	// print 打印节点
-	print() string
+	//
+	// @param
+	// parent: 父节点，根节点为nil
+	print(parent *TreeNode[K, V]) string
 
 	// dot 生成dot
 	//
 	// @param
+	// parent: 父节点，根节点为nil
 	// dotName: 当前节点dot name
 	// pDotName: 父节点dot name
-	dot(t *Tree, dotName string, pDotName string) (*dot.Node, *dot.Edge)
+	dot(t *Tree[K, V], parent *TreeNode[K, V], dotName string, pDotName string) (*dot.Node, *dot.Edge)
 }
 
 // Tree Tree
-type Tree struct {
-	root       iNode // 指向根节点
-	comparator utils.Comparator
+type Tree[K, V any] struct {
+	root       iNode[K, V] // 指向根节点
+	comparator func(a, b K) int
 	maxKeys    int
 	minKeys    int
 	size       int
+	multi      bool         // 是否是multimap模式
+	gen        uint64       // 版本号，只能修改版本号相同的节点，见Clone
+	pager      *pager[K, V] // 分页存储时节点所在的缓冲池，在内存中时为nil，见PagedTree
+}
+
+// NewTree 新建key、value为interface{}的b+树
+//
+// @param
+// t: 最小度数
+func NewTree(t int, comparator utils.Comparator, opts ...Option) *Tree[interface{}, interface{}] {
+	return NewTreeFunc[interface{}, interface{}](t, comparator.Compare, opts...)
 }
 
-// NewTree NewTree
+// NewTreeFunc 新建b+树
 //
 // @param
 // t: 最小度数
-func NewTree(t int, comparator utils.Comparator) *Tree {
-	tree := &Tree{}
-	tree.root = NewTreeLeaf()
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// opts: multimap模式等选项
+func NewTreeFunc[K, V any](t int, comparator func(a, b K) int, opts ...Option) *Tree[K, V] {
+	o := newOptions(opts)
+
+	tree := &Tree[K, V]{}
+	tree.root = NewTreeLeaf[K, V]()
 	tree.comparator = comparator
 	tree.maxKeys = 2 * t
 	tree.minKeys = t
+	tree.multi = o.multi
 
 	return tree
 }
 
-// Insert 插入
-func (t *Tree) Insert(key, val interface{}) {
-	keyPos := 0
-	iNode := t.root
+// NewOrderedTree 新建key为有序类型的b+树
+//
+// @param
+// t: 最小度数
+func NewOrderedTree[K cmp.Ordered, V any](t int, opts ...Option) *Tree[K, V] {
+	return NewTreeFunc[K, V](t, cmp.Compare[K], opts...)
+}
+
+// Insert 插入，key已存在时更新value，multimap模式下总是插入新的entry
+func (t *Tree[K, V]) Insert(key K, val V) {
+	var keyPos int
+	var bFound bool
+	iNode := t.mutableRoot()
 
 	for {
 		// 满节点进行分裂
@@ -113,246 +154,361 @@ func (t *Tree) Insert(key, val interface{}) {
 			iNode = iNode.split(t)
 		}
 
-		keyPos, _ = iNode.findKeyPosition(t.comparator, key)
+		keyPos, bFound = t.findInsertPosition(iNode, key)
 		// 是叶子节点，退出
 		if iNode.isLeaf() {
 			break
 		}
 
-		node := iNode.(*TreeNode)
-		iNode = node.getChildrenAndUpdateFirstKeyIfNeed(keyPos, key)
+		node := iNode.(*TreeNode[K, V])
+		iNode = node.getChildrenAndUpdateFirstKeyIfNeed(t, keyPos, key)
 	}
 
 	// 插入到叶子节点
-	leaf := iNode.(*TreeLeaf)
+	leaf := iNode.(*TreeLeaf[K, V])
+	if bFound && !t.multi {
+		// key已存在，更新value，entry可能和克隆的树共享，替换为新的entry
+		leaf.entries[keyPos-1] = utils.NewTypedEntry(key, val)
+		return
+	}
+
 	// 插入新的key
-	leaf.insertEntry(utils.NewEntry(key, val), keyPos)
+	leaf.insertEntry(utils.NewTypedEntry(key, val), keyPos)
 	t.size++
 }
 
-// Delete 删除
-func (t *Tree) Delete(key interface{}) {
+// Delete 删除，multimap模式下删除key对应的所有entry
+func (t *Tree[K, V]) Delete(key K) {
+	if t.multi {
+		t.DeleteAll(key)
+		return
+	}
+
 	t.deleteKey(key)
 }
 
 // deleteKey 删除key
-func (t *Tree) deleteKey(key interface{}) {
-	iNode := t.root
+//
+// @return
+// 是否找到key
+func (t *Tree[K, V]) deleteKey(key K) bool {
+	c := NewCursor(t)
+	if !c.Seek(key) || t.comparator(c.GetKey(), key) != 0 {
+		return false
+	}
 
-	// 找到key在节点的位置
-	pos, bFound := iNode.findKeyPosition(t.comparator, key)
-	if !bFound {
-		// 查找的key不存在
-		// 根节点的最小key是所有关键字的最小值
-		return
+	c.delete()
+	return true
+}
+
+// Search 查找key对应的数据，multimap模式下返回最早插入的entry，树被克隆过时返回entry的拷贝，见Clone
+func (t *Tree[K, V]) Search(key K) *utils.TypedEntry[K, V] {
+	return t.export(t.search(key))
+}
+
+// search 查找key对应的数据，multimap模式下返回最早插入的entry，返回树中的entry
+func (t *Tree[K, V]) search(key K) *utils.TypedEntry[K, V] {
+	c := NewCursor(t)
+	if !c.Seek(key) || t.comparator(c.GetKey(), key) != 0 {
+		return nil
 	}
 
-	for !iNode.isLeaf() {
-		// 是根节点或者内节点继续在子节点中查找
-		node := iNode.(*TreeNode)
-		if bFound {
-			// 在当前节点中找到了要删除的key
-			// 找到pos对应的子节点
-			iNode = node.childrens[pos]
-
-			// 之后都选择第一个子节点
-			// 当前节点包含要删除的key，则要删除的key一定是childrens[pos]的第一个key
-			for !iNode.isLeaf() {
-				node := iNode.(*TreeNode)
-				iNode = node.childrens[0]
-			}
+	return c.entry()
+}
 
-			pos = 0
+// SearchRange 查找[min, max]之间的数false
+func (t *Tree[K, V]) SearchRange(min, max K) []*utils.TypedEntry[K, V] {
+	entries := []*utils.TypedEntry[K, V]{}
+	iter := NewIteratorLowerBoundKey(t, min)
+	for iter.Next() {
+		if t.comparator(iter.entry.GetKey(), max) > 0 {
 			break
 		}
 
-		// 继续在其子节点childrens[pos-1]中查找key的位置
-		iNode = node.childrens[pos-1]
-		pos, bFound = iNode.findKeyPosition(t.comparator, key)
+		entries = append(entries, t.export(iter.entry))
 	}
 
-	if !bFound {
-		// 要删除的key不存在
-		return
+	return entries
+}
+
+// SearchRangeDesc 按key降序查找[min, max]之间的数据
+func (t *Tree[K, V]) SearchRangeDesc(min, max K) []*utils.TypedEntry[K, V] {
+	entries := []*utils.TypedEntry[K, V]{}
+	cursor := NewCursor(t)
+	for ok := cursor.SeekLE(max); ok; ok = cursor.Prev() {
+		if t.comparator(cursor.GetKey(), min) < 0 {
+			break
+		}
+
+		entries = append(entries, t.export(cursor.entry()))
 	}
 
-	// 删除key
-	leaf := iNode.(*TreeLeaf)
-	leaf.entries = append(leaf.entries[:pos], leaf.entries[pos+1:]...)
-	t.deleteFixUp(leaf, key)
-	t.size--
+	return entries
 }
 
-// deleteFixUp 删除修复
-//
-// @param
-// node: 要修复的节点
-// key: 删除的key
-func (t *Tree) deleteFixUp(node iNode, key interface{}) {
-	for {
-		parent := node.getParent()
-		if parent == nil {
-			// 修复根节点
-			t.dCaseRoot(node)
-			return
-		}
+// SearchRangeLowerBoundKeyWithLimit 查找大于等于key的limit个数据
+func (t *Tree[K, V]) SearchRangeLowerBoundKeyWithLimit(key K, limit int64) []*utils.TypedEntry[K, V] {
+	var count int64
+	list := make([]*utils.TypedEntry[K, V], 0, limit)
 
-		if node.getKeys() >= t.minKeys {
-			// 修复节点的key的数量大于等于t
-			t.replaceKey(parent, key)
-			return
-		}
+	cursor := NewCursor(t)
+	for ok := cursor.Seek(key); ok && count < limit; ok = cursor.Next() {
+		list = append(list, t.export(cursor.entry()))
+		count++
+	}
 
-		// 获取相邻节点
-		adj := node.adjacent(t)
-		if adj.getKeys() > t.minKeys {
-			// 相邻节点的key的数量大于t
-			parent := node.moveKey(t, adj)
-			t.replaceKey(parent, key)
-			return
-		}
+	return list
+}
 
-		node = node.merge(t, adj)
-		pos, bFound := node.findKeyPosition(t.comparator, key)
-		if bFound {
-			// node节点中包含delKey
-			// 用其子节点的第一个key替换
-			n := node.(*TreeNode)
-			n.keys[pos] = n.childrens[pos].getPosKey(0)
-		}
+// SearchRangeUpperBoundKeyWithLimit 查找小于等于key的limit个数据，按key升序返回
+func (t *Tree[K, V]) SearchRangeUpperBoundKeyWithLimit(key K, limit int64) []*utils.TypedEntry[K, V] {
+	var count int64
+	list := make([]*utils.TypedEntry[K, V], 0, limit)
+
+	cursor := NewCursor(t)
+	for ok := cursor.SeekLE(key); ok && count < limit; ok = cursor.Prev() {
+		list = append(list, t.export(cursor.entry()))
+		count++
 	}
+
+	return reverse(list)
 }
 
-// Search 查找key对应的数据
-func (t *Tree) Search(key interface{}) *utils.Entry {
-	iNode, pos, bFound := t.lookup(t.root, key)
-	if !bFound {
-		return nil
+// Put 插入key、value，key已存在时更新value，multimap模式下更新最早插入的value
+//
+// entry可能和克隆的树共享，更新时替换为新的entry，不修改原来的entry
+func (t *Tree[K, V]) Put(key K, val V) {
+	c := NewCursor(t)
+	if !c.Seek(key) || t.comparator(c.GetKey(), key) != 0 {
+		t.Insert(key, val)
+		return
 	}
 
-	leaf := iNode.(*TreeLeaf)
-	return leaf.entries[pos]
+	c.mutable()
+	c.leaf.entries[c.pos] = utils.NewTypedEntry(key, val)
 }
 
-// SearchRange 查找[min, max]之间的数false
-func (t *Tree) SearchRange(min, max interface{}) []*utils.Entry {
-	iNode, pos, _ := t.lookup(t.root, min)
-	if !iNode.isLeaf() {
-		// 取pos位置的子节点
-		iNode = t.getPosChildren(iNode, pos)
-		pos = 0
+// Get 查找key对应的value，multimap模式下返回最早插入的value
+func (t *Tree[K, V]) Get(key K) (val V, bFound bool) {
+	_, val, bFound = unpack(t.search(key))
+	return val, bFound
+}
+
+// Len 节点数
+func (t *Tree[K, V]) Len() int {
+	return t.size
+}
+
+// Min 最小的key和对应的value
+func (t *Tree[K, V]) Min() (key K, val V, bFound bool) {
+	if t.size == 0 {
+		return key, val, false
 	}
 
-	for !iNode.isLeaf() {
-		iNode = t.getPosChildren(iNode, 0)
+	leaf := t.minimum()
+	return unpack(leaf.entries[0])
+}
+
+// Max 最大的key和对应的value
+func (t *Tree[K, V]) Max() (key K, val V, bFound bool) {
+	if t.size == 0 {
+		return key, val, false
 	}
 
-	leaf := iNode.(*TreeLeaf)
-	entries := []*utils.Entry{}
-	iter := NewIteratorWithLeaf(t, leaf, pos)
-	for iter.Next() {
-		if t.comparator.Compare(iter.entry.GetKey(), max) == utils.Gt {
-			break
-		}
+	leaf := t.maximum()
+	return unpack(leaf.entries[len(leaf.entries)-1])
+}
 
-		entries = append(entries, iter.entry)
+// Floor 小于等于key的最大key和对应的value
+func (t *Tree[K, V]) Floor(key K) (k K, v V, bFound bool) {
+	c := NewCursor(t)
+	if !c.SeekLE(key) {
+		return k, v, false
 	}
 
-	return entries
+	return unpack(c.entry())
 }
 
-// dCaseRoot 删除修复 - 修复节点为根节点
-func (t *Tree) dCaseRoot(node iNode) {
-	if !node.isLeaf() && node.getKeys() == 1 {
-		n := node.(*TreeNode)
-		t.root = n.childrens[0]
-		n.childrens[0].setParent(nil)
+// Ceiling 大于等于key的最小key和对应的value
+func (t *Tree[K, V]) Ceiling(key K) (k K, v V, bFound bool) {
+	c := NewCursor(t)
+	if !c.Seek(key) {
+		return k, v, false
 	}
+
+	return unpack(c.entry())
 }
 
-// replaceKey 从node节点开始向上查找，如果该节点中包含key，则用子节点的第一个key替换
-func (t *Tree) replaceKey(node *TreeNode, key interface{}) {
-	for node != nil {
-		// 修复节点的父节点是否包含要删除的key
-		pos, bFound := node.findKeyPosition(t.comparator, key)
-		if !bFound {
-			// node中不包含要删除的key，退出
-			return
-		}
+// Lower 小于key的最大key和对应的value
+func (t *Tree[K, V]) Lower(key K) (k K, v V, bFound bool) {
+	c := NewCursor(t)
+	if c.Seek(key) {
+		// 第一个大于等于key的entry的上一个entry
+		c.Prev()
+	} else {
+		// 所有的key都小于key
+		c.Last()
+	}
 
-		// node节点中包含要删除的key
-		// 用子节点的第一个key替换
-		node.keys[pos] = node.childrens[pos].getPosKey(0)
-		node = node.getParent()
+	if !c.Valid() {
+		return k, v, false
 	}
+
+	return unpack(c.entry())
 }
 
-// getPosChildren 获取pos位置的子节点
-func (t *Tree) getPosChildren(iNode iNode, pos int) iNode {
-	node := iNode.(*TreeNode)
-	return node.childrens[pos]
+// Higher 大于key的最小key和对应的value
+func (t *Tree[K, V]) Higher(key K) (k K, v V, bFound bool) {
+	c := NewCursor(t)
+	if c.SeekLE(key) {
+		// 最后一个小于等于key的entry的下一个entry
+		c.Next()
+	} else {
+		// 所有的key都大于key
+		c.First()
+	}
+
+	if !c.Valid() {
+		return k, v, false
+	}
+
+	return unpack(c.entry())
 }
 
-// getPosEntry 获取pos位置的entry
-func (t *Tree) getPosEntry(iNode iNode, pos int) *utils.Entry {
-	leaf := iNode.(*TreeLeaf)
-	return leaf.entries[pos]
+// PopMin 删除最小的key，并返回最小的key和对应的value
+func (t *Tree[K, V]) PopMin() (key K, val V, bFound bool) {
+	if c := NewCursor(t); c.First() {
+		key, val, bFound = unpack(c.entry())
+		c.delete()
+	}
+
+	return key, val, bFound
 }
 
-// lookup 查找key所在的节点
-//
-// @param
-// sNode: 从sNode开始查找
-// key: 要查找的key
-//
-// @return
-// node: key所在的节点
-// pos: key在节点中的位置
-// bFound: 是否找到key
-func (t *Tree) lookup(sNode iNode, key interface{}) (node iNode, pos int, bFound bool) {
-	for {
-		pos, bFound = sNode.findKeyPosition(t.comparator, key)
-		if bFound {
-			if sNode.isLeaf() {
-				return sNode, pos, bFound
+// PopMax 删除最大的key，并返回最大的key和对应的value
+func (t *Tree[K, V]) PopMax() (key K, val V, bFound bool) {
+	if c := NewCursor(t); c.Last() {
+		key, val, bFound = unpack(c.entry())
+		c.delete()
+	}
+
+	return key, val, bFound
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的Entry
+func (t *Tree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		iter := NewIteratorLowerBoundKey(t, lo)
+		for iter.Next() {
+			if t.comparator(iter.GetKey(), hi) > 0 {
+				return
 			}
 
-			sNode = t.getPosChildren(sNode, pos)
-			for !sNode.isLeaf() {
-				sNode = t.getPosChildren(sNode, 0)
+			if !yield(iter.GetKey(), iter.GetValue()) {
+				return
 			}
+		}
+	}
+}
 
-			return sNode, 0, bFound
+// All 按key升序遍历所有Entry
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		iter := NewIterator(t)
+		for iter.Next() {
+			if !yield(iter.GetKey(), iter.GetValue()) {
+				return
+			}
 		}
+	}
+}
 
-		if pos == 0 || sNode.isLeaf() {
-			// 第一个key比要查找的key大 或者 node是叶子节点的key大于要查找的key
-			return sNode, pos, bFound
+// Backward 按key降序遍历所有Entry，从最大key开始调用Cursor.Prev
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c := NewCursor(t)
+		for ok := c.Last(); ok; ok = c.Prev() {
+			if !yield(c.GetKey(), c.GetValue()) {
+				return
+			}
 		}
+	}
+}
+
+// Iterator 从最小key开始的迭代器
+func (t *Tree[K, V]) Iterator() orderedmap.Iterator[K, V] {
+	return NewIterator(t)
+}
+
+// Cursor 双向移动的游标，需要调用First、Last、Seek或SeekLE定位
+func (t *Tree[K, V]) Cursor() *Cursor[K, V] {
+	return NewCursor(t)
+}
 
-		sNode = t.getPosChildren(sNode, pos-1)
+// dCaseRoot 删除修复 - 修复节点为根节点
+func (t *Tree[K, V]) dCaseRoot(node iNode[K, V]) {
+	if !node.isLeaf() && node.getKeys() == 1 {
+		n := node.(*TreeNode[K, V])
+		t.root = t.child(n, 0)
+		if t.root.getGen() == t.gen {
+			t.root.setParent(nil)
+		}
+	}
+}
+
+// getPosChildren 获取pos位置的子节点
+func (t *Tree[K, V]) getPosChildren(iNode iNode[K, V], pos int) iNode[K, V] {
+	return t.child(iNode.(*TreeNode[K, V]), pos)
+}
+
+// child 获取node中pos位置的子节点，分页存储时子节点不在缓冲池中则从文件中加载
+func (t *Tree[K, V]) child(node *TreeNode[K, V], pos int) iNode[K, V] {
+	if t.pager != nil {
+		return t.pager.child(node, pos)
+	}
+
+	return node.childrens[pos]
+}
+
+// getPosEntry 获取pos位置的entry
+func (t *Tree[K, V]) getPosEntry(iNode iNode[K, V], pos int) *utils.TypedEntry[K, V] {
+	leaf := iNode.(*TreeLeaf[K, V])
+	return leaf.entries[pos]
+}
+
+// findInsertPosition 在节点中查找第一个大于key的位置，相同的key插入到已有key的后面
+//
+// @return
+// pos: 插入的位置
+// bFound: 节点中是否有相同的key，有相同的key时pos-1为最后一个相同的key的位置
+func (t *Tree[K, V]) findInsertPosition(iNode iNode[K, V], key K) (pos int, bFound bool) {
+	pos, bFound = iNode.findKeyPosition(t.comparator, key)
+	for bFound && pos < iNode.getKeys() && t.comparator(iNode.getPosKey(pos), key) == 0 {
+		pos++
 	}
+
+	return pos, bFound
 }
 
 // minimum 中序遍历后，树的最小节点
-func (t *Tree) minimum() *TreeLeaf {
+func (t *Tree[K, V]) minimum() *TreeLeaf[K, V] {
 	iNode := t.root
 	for !iNode.isLeaf() {
 		iNode = t.getPosChildren(iNode, 0)
 	}
 
-	leaf := iNode.(*TreeLeaf)
+	leaf := iNode.(*TreeLeaf[K, V])
 	return leaf
 }
 
 // maximum 中序遍历后，树的最大节点
-func (t *Tree) maximum() *TreeLeaf {
+func (t *Tree[K, V]) maximum() *TreeLeaf[K, V] {
 	iNode := t.root
 	for !iNode.isLeaf() {
 		iNode = t.getPosChildren(iNode, iNode.getKeys()-1)
 	}
 
-	leaf := iNode.(*TreeLeaf)
+	leaf := iNode.(*TreeLeaf[K, V])
 	return leaf
 }
 
@@ -361,41 +517,57 @@ func (t *Tree) maximum() *TreeLeaf {
 // @param
 // inode: 要分裂的节点
 // key: 要分裂的节点的第一个key
-func (t *Tree) splitRootNode(inode iNode, key interface{}) *TreeNode {
-	parent := NewTreeNode()
-	parent.childrens = make([]iNode, 1)
+func (t *Tree[K, V]) splitRootNode(inode iNode[K, V], key K) *TreeNode[K, V] {
+	parent := NewTreeNode[K, V]()
+	parent.gen = t.gen
+	parent.childrens = make([]iNode[K, V], 1)
 	parent.childrens[0] = inode
-	parent.keys = make([]interface{}, 1)
+	parent.keys = make([]K, 1)
 	parent.keys[0] = key
 	t.root = parent
 	return parent
 }
 
 // Verify Verify
-func (t *Tree) Verify() bool {
+func (t *Tree[K, V]) Verify() bool {
+	leafDepth := 0
+	for iNode := t.root; !iNode.isLeaf(); iNode = t.getPosChildren(iNode, 0) {
+		leafDepth++
+	}
+
+	// 和其他树共享的节点的父节点可能是其他树中的节点，只验证属于当前树的节点的父节点
+	if t.root.getGen() == t.gen && t.root.getParent() != nil {
+		fmt.Printf("根节点的父节点不为nil\n")
+		return false
+	}
+
 	queue := list.New()
-	queue.PushBack(t.root)
+	queue.PushBack(&verifyNode[K, V]{t.root, nil, 0})
 
 	for queue.Len() != 0 {
 		e := queue.Remove(queue.Front())
-		iNode := e.(iNode)
+		v := e.(*verifyNode[K, V])
 
-		// 验证
-		if !iNode.verify(t) {
+		// 将根节点和内节点的所有子节点加入到stack，分页存储时先加载子节点再验证
+		if !v.node.isLeaf() {
+			node := v.node.(*TreeNode[K, V])
+			for i := range node.childrens {
+				queue.PushBack(&verifyNode[K, V]{t.child(node, i), node, v.depth + 1})
+			}
+		} else if v.depth != leafDepth {
+			// 所有叶子节点的深度相同
+			fmt.Printf("叶子节点的深度错误\n")
 			return false
 		}
 
-		// 将根节点和内节点的所有子节点加入到stack
-		if !iNode.isLeaf() {
-			node := iNode.(*TreeNode)
-			for _, v := range node.childrens {
-				queue.PushBack(v)
-			}
+		// 验证
+		if !v.node.verify(t, v.parent) {
+			return false
 		}
 	}
 
 	iter := NewIterator(t)
-	keys := make([]interface{}, 0)
+	keys := make([]K, 0)
 	for iter.Next() {
 		keys = append(keys, iter.GetKey())
 	}
@@ -405,27 +577,42 @@ func (t *Tree) Verify() bool {
 		return false
 	}
 
+	// 验证顺序，multimap模式下允许相同的key
+	for i := 0; i < len(keys)-1; i++ {
+		if res := t.comparator(keys[i], keys[i+1]); res > 0 || (res == 0 && !t.multi) {
+			fmt.Printf("Key顺序错误\n")
+			return false
+		}
+	}
+
 	return true
 }
 
+// verifyNode 验证时队列中的节点
+type verifyNode[K, V any] struct {
+	node   iNode[K, V]
+	parent *TreeNode[K, V] // 父节点，根节点为nil
+	depth  int             // 节点的深度，根节点的深度为0
+}
+
 // String String
-func (t *Tree) String() string {
+func (t *Tree[K, V]) String() string {
 	buffer := bytes.Buffer{}
 	queue := list.New()
-	queue.PushBack(t.root)
+	queue.PushBack(&verifyNode[K, V]{t.root, nil, 0})
 
 	for queue.Len() != 0 {
 		e := queue.Remove(queue.Front())
-		iNode := e.(iNode)
+		v := e.(*verifyNode[K, V])
 
 		// 打印节点
-		buffer.WriteString(iNode.print())
+		buffer.WriteString(v.node.print(v.parent))
 
 		// 将根节点和内节点的所有子节点加入到stack
-		if !iNode.isLeaf() {
-			node := iNode.(*TreeNode)
-			for _, v := range node.childrens {
-				queue.PushBack(v)
+		if !v.node.isLeaf() {
+			node := v.node.(*TreeNode[K, V])
+			for i := range node.childrens {
+				queue.PushBack(&verifyNode[K, V]{t.child(node, i), node, v.depth + 1})
 			}
 		}
 	}
@@ -433,17 +620,18 @@ func (t *Tree) String() string {
 	return buffer.String()
 }
 
-type dotNode struct {
-	node     iNode
-	nDotName string // 当前节点的dot name
-	pDotName string // 父节点的dot name
+type dotNode[K, V any] struct {
+	node     iNode[K, V]
+	parent   *TreeNode[K, V] // 父节点，根节点为nil
+	nDotName string          // 当前节点的dot name
+	pDotName string          // 父节点的dot name
 }
 
 // Dot Dot
-func (t *Tree) Dot() error {
+func (t *Tree[K, V]) Dot() error {
 	nameIdx := 0
 	stack := list.New()
-	stack.PushBack(&dotNode{t.root, fmt.Sprintf("node%d", nameIdx), ""})
+	stack.PushBack(&dotNode[K, V]{t.root, nil, fmt.Sprintf("node%d", nameIdx), ""})
 	nameIdx++
 
 	dGraph := dot.NewGraph()
@@ -455,9 +643,9 @@ func (t *Tree) Dot() error {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		d := e.(*dotNode)
+		d := e.(*dotNode[K, V])
 
-		dNode, dEdge := d.node.dot(t, d.nDotName, d.pDotName)
+		dNode, dEdge := d.node.dot(t, d.parent, d.nDotName, d.pDotName)
 		dGraph.AddNode(dNode)
 		if dEdge != nil {
 			dGraph.AddEdge(dEdge)
@@ -465,9 +653,10 @@ func (t *Tree) Dot() error {
 
 		// 将根节点和内节点的所有子节点加入到stack
 		if !d.node.isLeaf() {
-			node := d.node.(*TreeNode)
-			for _, v := range node.childrens {
-				stack.PushBack(&dotNode{v, fmt.Sprintf("node%d", nameIdx), d.nDotName})
+			node := d.node.(*TreeNode[K, V])
+			for i := range node.childrens {
+				v := t.child(node, i)
+				stack.PushBack(&dotNode[K, V]{v, node, fmt.Sprintf("node%d", nameIdx), d.nDotName})
 				nameIdx++
 			}
 		}
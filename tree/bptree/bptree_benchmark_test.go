@@ -5,10 +5,12 @@ import (
 	"math/rand"
 	"testing"
 	"time"
+
+	"github.com/asinglestep/gods/utils"
 )
 
 func Benchmark_BptreeRandInsert(b *testing.B) {
-	tree := NewTree(DEGREE, bptreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -22,8 +24,22 @@ func Benchmark_BptreeRandInsert(b *testing.B) {
 	fmt.Printf("Bptree Insert %v elements, use %v ms\n", num, time.Now().Sub(sTime).Nanoseconds()/1e6)
 }
 
+func Benchmark_BptreeOrderedRandInsert(b *testing.B) {
+	tree := NewOrderedTree[int, int](DEGREE)
+	var num = 10000000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+
+	sTime := time.Now()
+	for _, v := range array {
+		tree.Insert(v, v)
+	}
+
+	fmt.Printf("Bptree Ordered Insert %v elements, use %v ms\n", num, time.Now().Sub(sTime).Nanoseconds()/1e6)
+}
+
 func Benchmark_BptreeRandDelete(b *testing.B) {
-	tree := NewTree(DEGREE, bptreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -41,7 +57,7 @@ func Benchmark_BptreeRandDelete(b *testing.B) {
 }
 
 func Benchmark_BptreeRandSearchRange(b *testing.B) {
-	tree := NewTree(DEGREE, bptreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 10000000
 
 	iArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -9,22 +9,21 @@ import (
 )
 
 // TreeLeaf TreeLeaf
-type TreeLeaf struct {
-	parent  *TreeNode      // 指向父节点
-	entries []*utils.Entry // 数据
-	next    *TreeLeaf      // 指向下一个叶子节点的指针
-	prev    *TreeLeaf      // 指向上一个叶子节点的指针
+type TreeLeaf[K, V any] struct {
+	parent  *TreeNode[K, V]           // 指向父节点，只有属于当前树的节点的父节点是有效的
+	entries []*utils.TypedEntry[K, V] // 数据
+	gen     uint64                    // 节点所属的树的版本号，和树的版本号相同时可以直接修改，见Clone
 }
 
 // NewTreeLeaf NewTreeLeaf
-func NewTreeLeaf() *TreeLeaf {
-	leaf := &TreeLeaf{}
+func NewTreeLeaf[K, V any]() *TreeLeaf[K, V] {
+	leaf := &TreeLeaf[K, V]{}
 
 	return leaf
 }
 
 // split 分裂节点
-func (leaf *TreeLeaf) split(t *Tree) iNode {
+func (leaf *TreeLeaf[K, V]) split(t *Tree[K, V]) iNode[K, V] {
 	parent := leaf.parent
 	if parent == nil {
 		// 根节点为满的
@@ -35,20 +34,19 @@ func (leaf *TreeLeaf) split(t *Tree) iNode {
 	mid := t.minKeys
 	midEntry := leaf.entries[mid]
 
-	// 新的右节点，修改父节点，next指针，prev指针，entries
-	right := NewTreeLeaf()
+	// 新的右节点，修改父节点，entries
+	right := NewTreeLeaf[K, V]()
+	right.gen = t.gen
 	right.parent = parent
-	right.next = leaf.next
-	right.prev = leaf
-	right.entries = leaf.entries[mid:]
+	right.entries = make([]*utils.TypedEntry[K, V], len(leaf.entries)-mid)
+	copy(right.entries, leaf.entries[mid:])
 
-	// 新的左节点，修改父节点，next指针，entries
+	// 新的左节点，修改父节点，entries
 	leaf.parent = parent
-	leaf.next = right
-	leaf.entries = leaf.entries[:mid]
+	leaf.entries = leaf.entries[:mid:mid]
 
-	// 找到key在父节点中的位置
-	pos, _ := parent.findKeyPosition(t.comparator, midEntry.GetKey())
+	// right在父节点中的位置，multimap模式下父节点中可能有相同的key，不能按key查找
+	pos := parent.childrenPosition(leaf) + 1
 	// 将key插入到父节点中
 	parent.insertKey(midEntry.GetKey(), pos)
 	// 将right节点加入到父节点中，keys和childrens数量相同
@@ -57,55 +55,18 @@ func (leaf *TreeLeaf) split(t *Tree) iNode {
 	return parent
 }
 
-// adjacent 获取相邻节点
-func (leaf *TreeLeaf) adjacent(t *Tree) (adj iNode) {
-	var left, right iNode
-	parent := leaf.parent
-
-	pos, bFound := parent.findKeyPosition(t.comparator, leaf.entries[0].GetKey())
-	if pos == 0 {
-		// 第一个子节点的相邻节点为parent.childrens[pos+1]
-		return parent.childrens[1]
-	}
-
-	if bFound {
-		if pos == len(parent.keys)-1 {
-			// 当前节点是最后一个节点
-			return parent.childrens[pos-1]
-		}
-
-		left = parent.childrens[pos-1]
-		right = parent.childrens[pos+1]
-	} else {
-		if pos == 1 {
-			// 当前节点是第一个节点
-			return parent.childrens[1]
-		}
-
-		if pos == len(parent.keys) {
-			// parent的key都小于leaf.entries[0].Key
-			// 当前节点的位置为pos-1
-			// 相邻节点为parent.childrens[pos-2]
-			adj = parent.childrens[pos-2]
-		}
-
-		left = parent.childrens[pos-2]
-		right = parent.childrens[pos]
-	}
-
-	if left.getKeys() > t.minKeys {
-		return left
-	}
-
-	return right
+// adjacent 获取相邻节点，返回的相邻节点是可以修改的
+func (leaf *TreeLeaf[K, V]) adjacent(t *Tree[K, V]) (adj iNode[K, V]) {
+	return leaf.parent.adjacentChildren(t, leaf)
 }
 
 // moveKey 移动相邻节点的key到leaf中
-func (leaf *TreeLeaf) moveKey(t *Tree, adj iNode) *TreeNode {
-	adjNode := adj.(*TreeLeaf)
+func (leaf *TreeLeaf[K, V]) moveKey(t *Tree[K, V], adj iNode[K, V]) *TreeNode[K, V] {
+	adjNode := adj.(*TreeLeaf[K, V])
 	parent := leaf.parent
 
-	if t.comparator.Compare(leaf.entries[0].GetKey(), adjNode.entries[0].GetKey()) == utils.Lt {
+	pos := parent.childrenPosition(leaf)
+	if pos+1 < len(parent.childrens) && parent.childrens[pos+1] == adj {
 		leaf.moveCaseRightAdjacentNode(t, adjNode, parent)
 	} else {
 		leaf.moveCaseLeftAdjacentNode(t, adjNode, parent)
@@ -115,9 +76,9 @@ func (leaf *TreeLeaf) moveKey(t *Tree, adj iNode) *TreeNode {
 }
 
 // moveCaseRightAdjacentNode 相邻节点在右侧
-func (leaf *TreeLeaf) moveCaseRightAdjacentNode(t *Tree, adj *TreeLeaf, parent *TreeNode) {
+func (leaf *TreeLeaf[K, V]) moveCaseRightAdjacentNode(t *Tree[K, V], adj *TreeLeaf[K, V], parent *TreeNode[K, V]) {
 	// 找到相邻节点在父节点中的位置
-	pos, _ := parent.findKeyPosition(t.comparator, adj.entries[0].GetKey())
+	pos := parent.childrenPosition(adj)
 
 	// 将相邻节点的第一个key加入到当前节点
 	leaf.entries = append(leaf.entries, adj.entries[0])
@@ -130,13 +91,9 @@ func (leaf *TreeLeaf) moveCaseRightAdjacentNode(t *Tree, adj *TreeLeaf, parent *
 }
 
 // moveCaseLeftAdjacentNode 相邻节点在左侧
-func (leaf *TreeLeaf) moveCaseLeftAdjacentNode(t *Tree, adj *TreeLeaf, parent *TreeNode) {
+func (leaf *TreeLeaf[K, V]) moveCaseLeftAdjacentNode(t *Tree[K, V], adj *TreeLeaf[K, V], parent *TreeNode[K, V]) {
 	// 找到当前节点在父节点中的位置
-	pos, bFound := parent.findKeyPosition(t.comparator, leaf.entries[0].GetKey())
-	if !bFound {
-		// 删除的是子节点的最小值，导致删除后在父节点中找不到这个key
-		pos--
-	}
+	pos := parent.childrenPosition(leaf)
 
 	// 将相邻节点的最后一个key加入到当前节点
 	leaf.insertEntry(adj.entries[len(adj.entries)-1], 0)
@@ -149,28 +106,21 @@ func (leaf *TreeLeaf) moveCaseLeftAdjacentNode(t *Tree, adj *TreeLeaf, parent *T
 }
 
 // merge 合并相邻节点
-func (leaf *TreeLeaf) merge(t *Tree, adj iNode) iNode {
-	var key interface{}
-	adjNode := adj.(*TreeLeaf)
+func (leaf *TreeLeaf[K, V]) merge(t *Tree[K, V], adj iNode[K, V]) iNode[K, V] {
+	adjNode := adj.(*TreeLeaf[K, V])
 	parent := adjNode.parent
 
-	if t.comparator.Compare(leaf.entries[0].GetKey(), adjNode.entries[0].GetKey()) == utils.Lt {
-		key = adjNode.entries[0].GetKey()
+	// 被合并的节点在父节点中的位置
+	pos := parent.childrenPosition(leaf)
+	if pos+1 < len(parent.childrens) && parent.childrens[pos+1] == adj {
 		// 将相邻节点合并到当前节点
+		pos++
 		leaf.mergeFrom(adjNode)
 	} else {
-		// 在相邻节点的右侧，找到当前节点在父节点中的位置
-		key = leaf.entries[0].GetKey()
 		// 将当前节点合并到相邻节点
 		adjNode.mergeFrom(leaf)
 	}
 
-	pos, bFound := parent.findKeyPosition(t.comparator, key)
-	if !bFound {
-		// 删除的是子节点的最小值，导致删除后在父节点中找不到这个key
-		pos--
-	}
-
 	// 删除父节点的key和子节点
 	parent.keys = append(parent.keys[:pos], parent.keys[pos+1:]...)
 	parent.childrens = append(parent.childrens[:pos], parent.childrens[pos+1:]...)
@@ -178,17 +128,17 @@ func (leaf *TreeLeaf) merge(t *Tree, adj iNode) iNode {
 }
 
 // setParent 设置父节点
-func (leaf *TreeLeaf) setParent(parent *TreeNode) {
+func (leaf *TreeLeaf[K, V]) setParent(parent *TreeNode[K, V]) {
 	leaf.parent = parent
 }
 
 // getParent 获取父节点
-func (leaf *TreeLeaf) getParent() *TreeNode {
+func (leaf *TreeLeaf[K, V]) getParent() *TreeNode[K, V] {
 	return leaf.parent
 }
 
 // findKeyPosition 在节点中查找第一个大于等于key的位置，没有比key大的，则返回node.entries的长度
-func (leaf *TreeLeaf) findKeyPosition(comparator utils.Comparator, key interface{}) (pos int, bFound bool) {
+func (leaf *TreeLeaf[K, V]) findKeyPosition(comparator func(a, b K) int, key K) (pos int, bFound bool) {
 	if len(leaf.entries) == 0 {
 		return 0, false
 	}
@@ -197,7 +147,7 @@ func (leaf *TreeLeaf) findKeyPosition(comparator utils.Comparator, key interface
 
 	for i < j {
 		h := int(uint(i+j) >> 1)
-		if comparator.Compare(leaf.entries[h].GetKey(), key) == utils.Lt {
+		if comparator(leaf.entries[h].GetKey(), key) < 0 {
 			i = h + 1
 		} else {
 			j = h
@@ -208,7 +158,7 @@ func (leaf *TreeLeaf) findKeyPosition(comparator utils.Comparator, key interface
 		return i, false
 	}
 
-	if comparator.Compare(leaf.entries[i].GetKey(), key) == utils.Et {
+	if comparator(leaf.entries[i].GetKey(), key) == 0 {
 		bFound = true
 	}
 
@@ -216,36 +166,34 @@ func (leaf *TreeLeaf) findKeyPosition(comparator utils.Comparator, key interface
 }
 
 // getPosKey 获取pos位置的key
-func (leaf *TreeLeaf) getPosKey(pos int) interface{} {
+func (leaf *TreeLeaf[K, V]) getPosKey(pos int) K {
 	return leaf.entries[pos].GetKey()
 }
 
 // getKeys 获取key的数量
-func (leaf *TreeLeaf) getKeys() int {
+func (leaf *TreeLeaf[K, V]) getKeys() int {
 	return len(leaf.entries)
 }
 
 // isLeaf 是否是叶子节点
-func (leaf *TreeLeaf) isLeaf() bool {
+func (leaf *TreeLeaf[K, V]) isLeaf() bool {
 	return true
 }
 
 // isFull 是否是满节点
-func (leaf *TreeLeaf) isFull(max int) bool {
+func (leaf *TreeLeaf[K, V]) isFull(max int) bool {
 	return len(leaf.entries) == max
 }
 
 // free free
-func (leaf *TreeLeaf) free() {
+func (leaf *TreeLeaf[K, V]) free() {
 	leaf.parent = nil
 	leaf.entries = nil
-	leaf.prev = nil
-	leaf.next = nil
 }
 
 // verif 验证
-func (leaf *TreeLeaf) verify(t *Tree) bool {
-	if leaf.parent != nil {
+func (leaf *TreeLeaf[K, V]) verify(t *Tree[K, V], parent *TreeNode[K, V]) bool {
+	if parent != nil {
 		// 非根节点至少有t个关键字
 		if len(leaf.entries) < t.minKeys {
 			fmt.Printf("非根节点少于%v个关键字\n", t.minKeys)
@@ -263,9 +211,9 @@ func (leaf *TreeLeaf) verify(t *Tree) bool {
 }
 
 // String String
-func (leaf *TreeLeaf) print() string {
-	if leaf.parent != nil {
-		return fmt.Sprintf("叶子节点key: %v, \t此节点为父节点key[%v]的子节点, \t父节点为[%v]\n", leaf.printKeys(), leaf.entries[0].GetKey(), leaf.parent.printKeys())
+func (leaf *TreeLeaf[K, V]) print(parent *TreeNode[K, V]) string {
+	if parent != nil {
+		return fmt.Sprintf("叶子节点key: %v, \t此节点为父节点key[%v]的子节点, \t父节点为[%v]\n", leaf.printKeys(), leaf.entries[0].GetKey(), parent.printKeys())
 	}
 
 	if len(leaf.entries) == 0 {
@@ -275,7 +223,7 @@ func (leaf *TreeLeaf) print() string {
 	return fmt.Sprintf("叶子节点key: %v, \t此节点为根节点\n", leaf.printKeys())
 }
 
-func (leaf *TreeLeaf) printKeys() string {
+func (leaf *TreeLeaf[K, V]) printKeys() string {
 	keys := make([]string, 0, len(leaf.entries))
 	for _, entry := range leaf.entries {
 		keys = append(keys, fmt.Sprintf("%v", entry.GetKey()))
@@ -285,12 +233,12 @@ func (leaf *TreeLeaf) printKeys() string {
 }
 
 // dot dot
-func (leaf *TreeLeaf) dot(t *Tree, nName string, pName string) (dNode *dot.Node, dEdge *dot.Edge) {
+func (leaf *TreeLeaf[K, V]) dot(t *Tree[K, V], parent *TreeNode[K, V], nName string, pName string) (dNode *dot.Node, dEdge *dot.Edge) {
 	// 添加一个node
 	attrValues := make([]string, 0, len(leaf.entries))
 
 	for i, v := range leaf.entries {
-		attrValues = append(attrValues, fmt.Sprintf("<f%d> %d ", i, v.GetKey()))
+		attrValues = append(attrValues, fmt.Sprintf("<f%d> %v ", i, v.GetKey()))
 	}
 
 	attr := "\"" + strings.Join(attrValues, "|") + "\""
@@ -302,8 +250,8 @@ func (leaf *TreeLeaf) dot(t *Tree, nName string, pName string) (dNode *dot.Node,
 	}
 
 	// 添加一个edge
-	if leaf.parent != nil {
-		pos, _ := leaf.parent.findKeyPosition(t.comparator, leaf.entries[0].GetKey())
+	if parent != nil {
+		pos, _ := parent.findKeyPosition(t.comparator, leaf.entries[0].GetKey())
 		dEdge = &dot.Edge{}
 		dEdge.Src = pName
 		dEdge.SrcPort = ":f" + fmt.Sprintf("%d", pos)
@@ -313,9 +261,18 @@ func (leaf *TreeLeaf) dot(t *Tree, nName string, pName string) (dNode *dot.Node,
 	return dNode, dEdge
 }
 
+// unpack 获取entry的key和value，entry为nil时返回false
+func unpack[K, V any](entry *utils.TypedEntry[K, V]) (key K, val V, bFound bool) {
+	if entry == nil {
+		return key, val, false
+	}
+
+	return entry.GetKey(), entry.GetValue(), true
+}
+
 // insertEntry 将新的entry插入到pos位置上
-func (leaf *TreeLeaf) insertEntry(entry *utils.Entry, pos int) {
-	newEntries := make([]*utils.Entry, len(leaf.entries)+1)
+func (leaf *TreeLeaf[K, V]) insertEntry(entry *utils.TypedEntry[K, V], pos int) {
+	newEntries := make([]*utils.TypedEntry[K, V], len(leaf.entries)+1)
 	newEntries[pos] = entry
 	copy(newEntries[:pos], leaf.entries[:pos])
 	copy(newEntries[pos+1:], leaf.entries[pos:])
@@ -323,12 +280,18 @@ func (leaf *TreeLeaf) insertEntry(entry *utils.Entry, pos int) {
 }
 
 // mergeLeaf src的entries 合并到 leaf 中
-func (leaf *TreeLeaf) mergeFrom(src *TreeLeaf) {
+func (leaf *TreeLeaf[K, V]) mergeFrom(src *TreeLeaf[K, V]) {
 	leaf.entries = append(leaf.entries, src.entries...)
-	leaf.next = src.next
-	if src.next != nil {
-		src.next.prev = leaf
+	src.free()
+}
+
+// reverse 反转list
+func reverse[K, V any](list []*utils.TypedEntry[K, V]) []*utils.TypedEntry[K, V] {
+	listLen := len(list)
+
+	for i := 0; i < listLen/2; i++ {
+		list[i], list[listLen-i-1] = list[listLen-i-1], list[i]
 	}
 
-	src.free()
+	return list
 }
@@ -5,25 +5,25 @@ import (
 	"strings"
 
 	dot "github.com/asinglestep/godot"
-	"github.com/asinglestep/gods/utils"
 )
 
 // TreeNode TreeNode
-type TreeNode struct {
-	parent    *TreeNode     // 指向父节点
-	childrens []iNode       // 子节点
-	keys      []interface{} // 关键字
+type TreeNode[K, V any] struct {
+	parent    *TreeNode[K, V] // 指向父节点，只有属于当前树的节点的父节点是有效的
+	childrens []iNode[K, V]   // 子节点
+	keys      []K             // 关键字
+	gen       uint64          // 节点所属的树的版本号，和树的版本号相同时可以直接修改，见Clone
 }
 
 // NewTreeNode NewTreeNode
-func NewTreeNode() *TreeNode {
-	node := &TreeNode{}
+func NewTreeNode[K, V any]() *TreeNode[K, V] {
+	node := &TreeNode[K, V]{}
 
 	return node
 }
 
 // split 分裂节点
-func (node *TreeNode) split(t *Tree) iNode {
+func (node *TreeNode[K, V]) split(t *Tree[K, V]) iNode[K, V] {
 	parent := node.parent
 	if parent == nil {
 		// 根节点为满的
@@ -35,23 +35,22 @@ func (node *TreeNode) split(t *Tree) iNode {
 	midKey := node.keys[mid]
 
 	// 新的右节点，修改父节点，keys，childrens
-	// rightKeys := make([]interface{}, mid)
-	// copy(rKeys[:], node.keys[mid:])
-	// rightChildrens := make([]iNode, mid)
-	// copy(rightChildrens[:], node.childrens[mid:])
-	right := NewTreeNode()
+	right := NewTreeNode[K, V]()
+	right.gen = t.gen
 	right.parent = parent
-	right.keys = node.keys[mid:]
-	right.childrens = node.childrens[mid:]
+	right.keys = make([]K, len(node.keys)-mid)
+	copy(right.keys, node.keys[mid:])
+	right.childrens = make([]iNode[K, V], len(node.childrens)-mid)
+	copy(right.childrens, node.childrens[mid:])
 	right.updateChildrensParent(right)
 
 	// 新的左节点，修改父节点，keys，childrens
 	node.parent = parent
-	node.keys = node.keys[:mid]
-	node.childrens = node.childrens[:mid]
+	node.keys = node.keys[:mid:mid]
+	node.childrens = node.childrens[:mid:mid]
 
-	// 找到key在父节点中的位置
-	pos, _ := parent.findKeyPosition(t.comparator, midKey)
+	// right在父节点中的位置，multimap模式下父节点中可能有相同的key，不能按key查找
+	pos := parent.childrenPosition(node) + 1
 	// 将key插入到父节点中
 	parent.insertKey(midKey, pos)
 	// 将right节点加入到父节点中
@@ -59,55 +58,38 @@ func (node *TreeNode) split(t *Tree) iNode {
 	return parent
 }
 
-// adjacent 获取相邻节点
-func (node *TreeNode) adjacent(t *Tree) (adj iNode) {
-	var left, right iNode
-	parent := node.parent
+// adjacent 获取相邻节点，返回的相邻节点是可以修改的
+func (node *TreeNode[K, V]) adjacent(t *Tree[K, V]) (adj iNode[K, V]) {
+	return node.parent.adjacentChildren(t, node)
+}
 
-	pos, bFound := parent.findKeyPosition(t.comparator, node.keys[0])
+// adjacentChildren 获取子节点children的相邻节点，相邻节点不属于当前树时先拷贝相邻节点
+func (node *TreeNode[K, V]) adjacentChildren(t *Tree[K, V], children iNode[K, V]) iNode[K, V] {
+	pos := node.childrenPosition(children)
 	if pos == 0 {
-		// 第一个子节点的相邻节点为parent.childrens[1]
-		return parent.childrens[1]
+		// 第一个子节点的相邻节点为node.childrens[1]
+		return t.mutableChild(node, 1)
 	}
 
-	if bFound {
-		if pos == len(parent.keys)-1 {
-			// 当前节点是最后一个节点
-			return parent.childrens[pos-1]
-		}
-
-		left = parent.childrens[pos-1]
-		right = parent.childrens[pos+1]
-	} else {
-		if pos == 1 {
-			// 当前节点是第一个节点
-			return parent.childrens[1]
-		}
-
-		if pos == len(parent.keys) {
-			// parent的key都小于node.keys[0]
-			// 当前节点的位置为pos-1
-			// 相邻节点为parent.childrens[pos-2]
-			return parent.childrens[pos-2]
-		}
-
-		left = parent.childrens[pos-2]
-		right = parent.childrens[pos]
+	if pos == len(node.childrens)-1 {
+		// 当前节点是最后一个节点
+		return t.mutableChild(node, pos-1)
 	}
 
-	if left.getKeys() > t.minKeys {
-		return left
+	if t.child(node, pos-1).getKeys() > t.minKeys {
+		return t.mutableChild(node, pos-1)
 	}
 
-	return right
+	return t.mutableChild(node, pos+1)
 }
 
 // moveKey 移动相邻节点的key到node中
-func (node *TreeNode) moveKey(t *Tree, adj iNode) *TreeNode {
-	adjNode := adj.(*TreeNode)
+func (node *TreeNode[K, V]) moveKey(t *Tree[K, V], adj iNode[K, V]) *TreeNode[K, V] {
+	adjNode := adj.(*TreeNode[K, V])
 	parent := node.parent
 
-	if t.comparator.Compare(node.keys[0], adjNode.keys[0]) == utils.Lt {
+	pos := parent.childrenPosition(node)
+	if pos+1 < len(parent.childrens) && parent.childrens[pos+1] == adj {
 		node.moveCaseRightAdjacentNode(t, adjNode, parent)
 	} else {
 		node.moveCaseLeftAdjacentNode(t, adjNode, parent)
@@ -117,12 +99,12 @@ func (node *TreeNode) moveKey(t *Tree, adj iNode) *TreeNode {
 }
 
 // moveCaseRightAdjacentNode 相邻节点在右侧
-func (node *TreeNode) moveCaseRightAdjacentNode(t *Tree, adj *TreeNode, parent *TreeNode) {
+func (node *TreeNode[K, V]) moveCaseRightAdjacentNode(t *Tree[K, V], adj *TreeNode[K, V], parent *TreeNode[K, V]) {
 	// 找到相邻节点在父节点的位置
-	pos, _ := parent.findKeyPosition(t.comparator, adj.keys[0])
+	pos := parent.childrenPosition(adj)
 
 	// 修改相邻节点的子节点的父节点
-	adj.childrens[0].setParent(node)
+	node.adopt(adj.childrens[0])
 
 	// 将相邻节点的第一个key和子节点移到当前节点
 	node.keys = append(node.keys, adj.keys[0])
@@ -137,20 +119,16 @@ func (node *TreeNode) moveCaseRightAdjacentNode(t *Tree, adj *TreeNode, parent *
 }
 
 // moveCaseLeftAdjacentNode 相邻节点在左侧
-func (node *TreeNode) moveCaseLeftAdjacentNode(t *Tree, adj *TreeNode, parent *TreeNode) {
+func (node *TreeNode[K, V]) moveCaseLeftAdjacentNode(t *Tree[K, V], adj *TreeNode[K, V], parent *TreeNode[K, V]) {
 	// 找到当前节点在父节点的位置
-	pos, bFound := parent.findKeyPosition(t.comparator, node.keys[0])
-	if bFound {
-		// 删除的是子节点的最小值，导致删除后在父节点中找不到这个key
-		pos--
-	}
+	pos := parent.childrenPosition(node)
 
 	// 将相邻节点的最后一个key插入到当前节点
 	node.insertKey(adj.keys[len(adj.keys)-1], 0)
 	// 将相邻节点的最后一个子节点插入到当前节点
 	node.insertChildren(adj.childrens[len(adj.childrens)-1], 0)
 	// 修改当前节点的第一个子节点的父节点
-	node.childrens[0].setParent(node)
+	node.adopt(node.childrens[0])
 
 	// 删除相邻节点的key和子节点
 	adj.keys = adj.keys[:len(adj.keys)-1]
@@ -161,25 +139,19 @@ func (node *TreeNode) moveCaseLeftAdjacentNode(t *Tree, adj *TreeNode, parent *T
 }
 
 // merge 合并相邻节点
-func (node *TreeNode) merge(t *Tree, adj iNode) iNode {
-	var key interface{}
-	adjNode := adj.(*TreeNode)
+func (node *TreeNode[K, V]) merge(t *Tree[K, V], adj iNode[K, V]) iNode[K, V] {
+	adjNode := adj.(*TreeNode[K, V])
 	parent := node.parent
 
-	if t.comparator.Compare(node.keys[0], adjNode.keys[0]) == utils.Lt {
-		key = adjNode.keys[0]
+	// 被合并的节点在父节点中的位置
+	pos := parent.childrenPosition(node)
+	if pos+1 < len(parent.childrens) && parent.childrens[pos+1] == adj {
+		pos++
 		node.mergeFrom(adjNode)
 	} else {
-		key = node.keys[0]
 		adjNode.mergeFrom(node)
 	}
 
-	pos, bFound := parent.findKeyPosition(t.comparator, key)
-	if !bFound {
-		// 删除的是子节点的最小值，导致删除后在父节点中找不到这个key
-		pos--
-	}
-
 	// 删除父节点的key和子节点
 	parent.keys = append(parent.keys[:pos], parent.keys[pos+1:]...)
 	parent.childrens = append(parent.childrens[:pos], parent.childrens[pos+1:]...)
@@ -187,19 +159,19 @@ func (node *TreeNode) merge(t *Tree, adj iNode) iNode {
 }
 
 // setParent 设置父节点
-func (node *TreeNode) setParent(parent *TreeNode) {
+func (node *TreeNode[K, V]) setParent(parent *TreeNode[K, V]) {
 	node.parent = parent
 }
 
 // getParent 获取父节点
-func (node *TreeNode) getParent() *TreeNode {
+func (node *TreeNode[K, V]) getParent() *TreeNode[K, V] {
 	return node.parent
 }
 
 // findKeyPosition
 // 在节点中查找第一个大于等于key的位置
 // 没有比key大的，则返回node.keys的长度
-func (node *TreeNode) findKeyPosition(comparator utils.Comparator, key interface{}) (pos int, bFound bool) {
+func (node *TreeNode[K, V]) findKeyPosition(comparator func(a, b K) int, key K) (pos int, bFound bool) {
 	if len(node.keys) == 0 {
 		return 0, false
 	}
@@ -208,7 +180,7 @@ func (node *TreeNode) findKeyPosition(comparator utils.Comparator, key interface
 
 	for i < j {
 		h := int(uint(i+j) >> 1)
-		if comparator.Compare(node.keys[h], key) == utils.Lt {
+		if comparator(node.keys[h], key) < 0 {
 			i = h + 1
 		} else {
 			j = h
@@ -219,7 +191,7 @@ func (node *TreeNode) findKeyPosition(comparator utils.Comparator, key interface
 		return i, false
 	}
 
-	if comparator.Compare(node.keys[i], key) == utils.Et {
+	if comparator(node.keys[i], key) == 0 {
 		bFound = true
 	}
 
@@ -227,35 +199,35 @@ func (node *TreeNode) findKeyPosition(comparator utils.Comparator, key interface
 }
 
 // getPosKey 获取pos位置的key
-func (node *TreeNode) getPosKey(pos int) interface{} {
+func (node *TreeNode[K, V]) getPosKey(pos int) K {
 	return node.keys[pos]
 }
 
 // getKeys 获取key的数量
-func (node *TreeNode) getKeys() int {
+func (node *TreeNode[K, V]) getKeys() int {
 	return len(node.keys)
 }
 
 // isLeaf 是否是叶子节点
-func (node *TreeNode) isLeaf() bool {
+func (node *TreeNode[K, V]) isLeaf() bool {
 	return false
 }
 
 // isFull 是否是满节点
-func (node *TreeNode) isFull(max int) bool {
+func (node *TreeNode[K, V]) isFull(max int) bool {
 	return len(node.keys) == max
 }
 
 // free free
-func (node *TreeNode) free() {
+func (node *TreeNode[K, V]) free() {
 	node.parent = nil
 	node.keys = nil
 	node.childrens = nil
 }
 
 // verif 验证
-func (node *TreeNode) verify(t *Tree) bool {
-	if node.parent != nil {
+func (node *TreeNode[K, V]) verify(t *Tree[K, V], parent *TreeNode[K, V]) bool {
+	if parent != nil {
 		// 非根节点至少有t个关键字
 		if len(node.keys) < t.minKeys {
 			fmt.Printf("非根节点少于%v个关键字\n", t.minKeys)
@@ -281,17 +253,36 @@ func (node *TreeNode) verify(t *Tree) bool {
 		return false
 	}
 
+	// key和子节点一一对应
+	if len(node.keys) != len(node.childrens) {
+		fmt.Printf("节点的key数%v不等于子节点数%v\n", len(node.keys), len(node.childrens))
+		return false
+	}
+
 	// 非叶子节点的key是其子节点key的最小值
 	for i, v := range node.childrens {
+		// 和其他树共享的节点的父节点可能是其他树中的节点，只验证属于当前树的节点的父节点
+		if v.getGen() == t.gen {
+			if node.gen != t.gen {
+				fmt.Printf("父节点的第%v个子节点属于当前树，父节点不属于当前树\n", i)
+				return false
+			}
+
+			if v.getParent() != node {
+				fmt.Printf("父节点的第%v个子节点的父节点错误\n", i)
+				return false
+			}
+		}
+
 		if v.isLeaf() {
-			l := v.(*TreeLeaf)
-			if t.comparator.Compare(l.entries[0].GetKey(), node.keys[i]) != utils.Et {
+			l := v.(*TreeLeaf[K, V])
+			if t.comparator(l.entries[0].GetKey(), node.keys[i]) != 0 {
 				fmt.Printf("父节点的第%v个key不是其叶子节点的第一个key, 父节点第%v个key: %v, 叶子节点的第一个key: %v\n", i, i, node.keys[i], l.entries[0].GetKey())
 				return false
 			}
 		} else {
-			n := v.(*TreeNode)
-			if t.comparator.Compare(n.keys[0], node.keys[i]) != utils.Et {
+			n := v.(*TreeNode[K, V])
+			if t.comparator(n.keys[0], node.keys[i]) != 0 {
 				fmt.Printf("父节点的第%v个key不是其子节点的第一个key, 父节点第%v个key: %v, 子节点的第一个key: %v\n", i, i, node.keys[i], n.keys[0])
 				return false
 			}
@@ -302,16 +293,16 @@ func (node *TreeNode) verify(t *Tree) bool {
 }
 
 // print print
-func (node *TreeNode) print() string {
-	if node.parent != nil {
-		return fmt.Sprintf("内节点key: %v, \t此节点为父节点key[%v]的子节点, \t父节点为[%v]\n", node.printKeys(), node.keys[0], node.parent.printKeys())
+func (node *TreeNode[K, V]) print(parent *TreeNode[K, V]) string {
+	if parent != nil {
+		return fmt.Sprintf("内节点key: %v, \t此节点为父节点key[%v]的子节点, \t父节点为[%v]\n", node.printKeys(), node.keys[0], parent.printKeys())
 	}
 
 	return fmt.Sprintf("内节点key: %v, \t此节点为根节点\n", node.printKeys())
 }
 
 // String String
-func (node *TreeNode) printKeys() string {
+func (node *TreeNode[K, V]) printKeys() string {
 	keys := make([]string, 0, len(node.keys))
 	for _, key := range node.keys {
 		keys = append(keys, fmt.Sprintf("%v", key))
@@ -321,12 +312,12 @@ func (node *TreeNode) printKeys() string {
 }
 
 // dot dot
-func (node *TreeNode) dot(t *Tree, nName string, pName string) (dNode *dot.Node, dEdge *dot.Edge) {
+func (node *TreeNode[K, V]) dot(t *Tree[K, V], parent *TreeNode[K, V], nName string, pName string) (dNode *dot.Node, dEdge *dot.Edge) {
 	// 添加一个node
 	attrValues := make([]string, 0, len(node.keys))
 
 	for i, key := range node.keys {
-		attrValues = append(attrValues, fmt.Sprintf("<f%d> %d ", i, key))
+		attrValues = append(attrValues, fmt.Sprintf("<f%d> %v ", i, key))
 	}
 
 	attr := "\"" + strings.Join(attrValues, "|") + "\""
@@ -338,8 +329,8 @@ func (node *TreeNode) dot(t *Tree, nName string, pName string) (dNode *dot.Node,
 	}
 
 	// 添加一个edge
-	if node.parent != nil {
-		pos, _ := node.parent.findKeyPosition(t.comparator, node.keys[0])
+	if parent != nil {
+		pos, _ := parent.findKeyPosition(t.comparator, node.keys[0])
 		dEdge = &dot.Edge{}
 		dEdge.Src = pName
 		dEdge.SrcPort = ":f" + fmt.Sprintf("%d", pos)
@@ -352,28 +343,47 @@ func (node *TreeNode) dot(t *Tree, nName string, pName string) (dNode *dot.Node,
 // getChildrenAndUpdateFirstKeyIfNeed
 // 如果pos等于0，则更新第一个key，返回pos位置的子节点
 // 如果pos大于0，则返回pos-1位置的子节点
-func (node *TreeNode) getChildrenAndUpdateFirstKeyIfNeed(pos int, key interface{}) iNode {
+// 返回的子节点是可以修改的
+func (node *TreeNode[K, V]) getChildrenAndUpdateFirstKeyIfNeed(t *Tree[K, V], pos int, key K) iNode[K, V] {
 	if pos == 0 {
 		// 比第一个关键字还小
 		// 替换node.keys[0]
 		node.keys[0] = key
-		return node.childrens[0]
+		return t.mutableChild(node, 0)
 	}
 
-	return node.childrens[pos-1]
+	return t.mutableChild(node, pos-1)
+}
+
+// childrenPosition 获取子节点在node.childrens中的位置
+func (node *TreeNode[K, V]) childrenPosition(children iNode[K, V]) int {
+	for i, v := range node.childrens {
+		if v == children {
+			return i
+		}
+	}
+
+	return -1
 }
 
 // updateChildrensParent 更新的node的childrens的父节点
-func (node *TreeNode) updateChildrensParent(parent *TreeNode) {
+func (node *TreeNode[K, V]) updateChildrensParent(parent *TreeNode[K, V]) {
 	for i := range node.childrens {
-		node.childrens[i].setParent(parent)
+		parent.adopt(node.childrens[i])
+	}
+}
+
+// adopt 将children的父节点设置为node，children和其他树共享时不修改children
+func (node *TreeNode[K, V]) adopt(children iNode[K, V]) {
+	if children.getGen() == node.gen {
+		children.setParent(node)
 	}
 }
 
 // insertKey 在pos位置插入key
-func (node *TreeNode) insertKey(key interface{}, pos int) {
+func (node *TreeNode[K, V]) insertKey(key K, pos int) {
 	keysLen := len(node.keys)
-	newKeys := make([]interface{}, keysLen+1)
+	newKeys := make([]K, keysLen+1)
 	copy(newKeys, node.keys[:pos])
 	newKeys[pos] = key
 	copy(newKeys[pos+1:], node.keys[pos:])
@@ -381,9 +391,9 @@ func (node *TreeNode) insertKey(key interface{}, pos int) {
 }
 
 // insertChildren 在pos位置插入children
-func (node *TreeNode) insertChildren(children iNode, pos int) {
+func (node *TreeNode[K, V]) insertChildren(children iNode[K, V], pos int) {
 	csLen := len(node.childrens)
-	newCs := make([]iNode, csLen+1)
+	newCs := make([]iNode[K, V], csLen+1)
 	copy(newCs, node.childrens[:pos])
 	newCs[pos] = children
 	copy(newCs[pos+1:], node.childrens[pos:])
@@ -391,7 +401,7 @@ func (node *TreeNode) insertChildren(children iNode, pos int) {
 }
 
 // mergeFrom src的keys和childrens 合并到 node 中
-func (node *TreeNode) mergeFrom(src *TreeNode) {
+func (node *TreeNode[K, V]) mergeFrom(src *TreeNode[K, V]) {
 	node.keys = append(node.keys, src.keys...)
 	node.childrens = append(node.childrens, src.childrens...)
 
@@ -1,35 +1,30 @@
 package bptree
 
 import (
+	"cmp"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"iter"
+	"maps"
 	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
-)
-
-type bptreeComparator struct {
-}
-
-// Compare Compare
-func (tc bptreeComparator) Compare(k1, k2 interface{}) int {
-	i1 := k1.(int)
-	i2 := k2.(int)
-
-	if i1 > i2 {
-		return 1
-	}
 
-	if i1 < i2 {
-		return -1
-	}
-
-	return 0
-}
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/map/orderedmap/conformance"
+	"github.com/asinglestep/gods/utils"
+)
 
 const DEGREE = 10
 
 func Test_BpTreeInsert(t *testing.T) {
-	tree := NewTree(DEGREE, bptreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 
 	fmt.Println("插入10")
 	tree.Insert(10, 10)
@@ -114,7 +109,7 @@ func Test_BpTreeInsert(t *testing.T) {
 }
 
 func Test_BpTreeRandInsert(t *testing.T) {
-	tree := NewTree(DEGREE, bptreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -142,7 +137,7 @@ func Test_BpTreeRandInsert(t *testing.T) {
 }
 
 func Test_BpTreeDelete(t *testing.T) {
-	tree := NewTree(DEGREE, bptreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 
 	tree.Insert(10, 10)
 	tree.Insert(20, 20)
@@ -236,7 +231,7 @@ func Test_BpTreeDelete(t *testing.T) {
 }
 
 func Test_BpTreeRandDelete(t *testing.T) {
-	tree := NewTree(DEGREE, bptreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 1000000
 
 	iArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -269,7 +264,7 @@ func Test_BpTreeRandDelete(t *testing.T) {
 }
 
 func Test_BpTreeRandSearch(t *testing.T) {
-	tree := NewTree(DEGREE, bptreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 1000000
 
 	iArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -295,7 +290,7 @@ func Test_BpTreeRandSearch(t *testing.T) {
 }
 
 func Test_BpTreeRandSearchRange(t *testing.T) {
-	tree := NewTree(DEGREE, bptreeComparator{})
+	tree := NewTree(DEGREE, utils.IntComparator)
 	var num = 1000000
 
 	iArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -325,3 +320,1121 @@ func Test_BpTreeRandSearchRange(t *testing.T) {
 		}
 	}
 }
+
+func Test_BpTreeOrderedTree(t *testing.T) {
+	tree := NewOrderedTree[int, string](DEGREE)
+	var num = 100000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+	for _, v := range array {
+		tree.Insert(v, strconv.Itoa(v))
+	}
+
+	dArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num / 2)
+	for _, v := range dArray {
+		tree.Delete(v)
+	}
+
+	if !tree.Verify() {
+		t.Fatal("Test_BpTreeOrderedTree err")
+	}
+
+	idx := num / 2
+	iter := NewIterator(tree)
+	for iter.Next() {
+		if iter.GetKey() != idx || iter.GetValue() != strconv.Itoa(idx) {
+			t.Fatalf("want %v, got %v: %v\n", idx, iter.GetKey(), iter.GetValue())
+		}
+
+		idx++
+	}
+
+	if idx != num {
+		t.Fatalf("want %v, got %v\n", num, idx)
+	}
+}
+
+func Test_BpTreeConformance(t *testing.T) {
+	for _, degree := range []int{2, DEGREE} {
+		t.Run(strconv.Itoa(degree), func(t *testing.T) {
+			conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+				return NewOrderedTree[int, int](degree)
+			})
+		})
+	}
+}
+
+func Test_SyncBpTreeConformance(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewSyncTree(NewOrderedTree[int, int](DEGREE))
+	})
+}
+
+func Test_SyncBpTreeConcurrent(t *testing.T) {
+	conformance.RunConcurrent(t, NewSyncTree(NewOrderedTree[int, int](DEGREE)))
+}
+
+func Test_SyncBpTreeSearch(t *testing.T) {
+	tree := NewSyncTree(NewOrderedTree[int, int](DEGREE))
+	for i := 0; i < 100; i++ {
+		tree.Insert(i*2, i)
+	}
+
+	if found := tree.Search(20); found == nil || found.GetKey() != 20 || found.GetValue() != 10 {
+		t.Fatalf("Search(20) want 20: 10, got %v\n", found)
+	}
+
+	if found := tree.Search(21); found != nil {
+		t.Fatalf("Search(21) want nil, got %v\n", found)
+	}
+
+	keys := func(list []*utils.TypedEntry[int, int]) []int {
+		res := []int{}
+		for _, e := range list {
+			res = append(res, e.GetKey())
+		}
+
+		return res
+	}
+
+	if got := keys(tree.SearchRange(9, 20)); !slices.Equal(got, []int{10, 12, 14, 16, 18, 20}) {
+		t.Fatalf("SearchRange(9, 20) got %v\n", got)
+	}
+
+	if got := keys(tree.SearchRangeDesc(9, 20)); !slices.Equal(got, []int{20, 18, 16, 14, 12, 10}) {
+		t.Fatalf("SearchRangeDesc(9, 20) got %v\n", got)
+	}
+
+	if got := keys(tree.SearchRangeLowerBoundKeyWithLimit(195, 5)); !slices.Equal(got, []int{196, 198}) {
+		t.Fatalf("SearchRangeLowerBoundKeyWithLimit(195, 5) got %v\n", got)
+	}
+
+	if got := keys(tree.SearchRangeUpperBoundKeyWithLimit(5, 5)); !slices.Equal(got, []int{0, 2, 4}) {
+		t.Fatalf("SearchRangeUpperBoundKeyWithLimit(5, 5) got %v\n", got)
+	}
+
+	// 返回的entry是拷贝，修改拷贝不影响树
+	tree.Search(20).SetValue(-1)
+	tree.SearchRange(0, 20)[0].SetValue(-1)
+	if v, _ := tree.Get(20); v != 10 {
+		t.Fatalf("Get(20) want 10, got %v\n", v)
+	}
+
+	if v, _ := tree.Get(0); v != 0 {
+		t.Fatalf("Get(0) want 0, got %v\n", v)
+	}
+
+	// 游标在快照上移动，看不到之后的修改
+	cursor := tree.Cursor()
+	tree.Delete(0)
+	tree.Put(2, -1)
+	if !cursor.First() || cursor.GetKey() != 0 || !cursor.Next() || cursor.GetValue() != 1 {
+		t.Fatalf("Cursor want snapshot, got %v: %v\n", cursor.GetKey(), cursor.GetValue())
+	}
+
+	if cursor.Delete(); tree.Len() != 99 {
+		t.Fatalf("Cursor.Delete should not modify SyncTree, len %v\n", tree.Len())
+	}
+
+	// 读取返回的拷贝时不持有锁，同时修改树不会产生数据竞争，使用go test -race验证
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 2000; i++ {
+			tree.Put(i%200, -i)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 100; i++ {
+			cursor := tree.Cursor()
+			for ok := cursor.First(); ok; ok = cursor.Next() {
+				_ = cursor.GetValue()
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		for _, e := range tree.SearchRange(0, 200) {
+			_ = e.GetValue()
+		}
+	}
+
+	wg.Wait()
+}
+
+func Test_BpTreeMultimap(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	// 度数较小时相同的key会分布在多个叶子节点中
+	tree := NewOrderedTree[int, int](2, WithMultimap())
+	want := map[int][]int{}
+	size := 0
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(100)
+
+		switch op := r.Intn(10); {
+		case op < 6:
+			tree.Insert(key, i)
+			want[key] = append(want[key], i)
+			size++
+		case op < 9:
+			if tree.DeleteOne(key) != (len(want[key]) > 0) {
+				t.Fatalf("seed %v: DeleteOne(%v) want %v\n", seed, key, len(want[key]) > 0)
+			}
+
+			if len(want[key]) > 0 {
+				want[key] = want[key][1:]
+				size--
+			}
+		default:
+			if n := tree.DeleteAll(key); n != len(want[key]) {
+				t.Fatalf("seed %v: DeleteAll(%v) want %v, got %v\n", seed, key, len(want[key]), n)
+			}
+
+			size -= len(want[key])
+			delete(want, key)
+		}
+
+		if tree.Count(key) != len(want[key]) {
+			t.Fatalf("seed %v: Count(%v) want %v, got %v\n", seed, key, len(want[key]), tree.Count(key))
+		}
+
+		if fmt.Sprint(tree.GetAll(key)) != fmt.Sprint(want[key]) {
+			t.Fatalf("seed %v: GetAll(%v) want %v, got %v\n", seed, key, want[key], tree.GetAll(key))
+		}
+
+		if val, ok := tree.Get(key); ok != (len(want[key]) > 0) || (ok && val != want[key][0]) {
+			t.Fatalf("seed %v: Get(%v) want first value %v, got %v\n", seed, key, want[key], val)
+		}
+	}
+
+	if !tree.Verify() || tree.Len() != size {
+		t.Fatalf("seed %v: Multimap Verify Error, want %v entries, got %v\n", seed, size, tree.Len())
+	}
+
+	// 相同key的value按插入顺序排列
+	got := map[int][]int{}
+	for k, v := range tree.All() {
+		got[k] = append(got[k], v)
+	}
+
+	for k, vals := range want {
+		if len(vals) > 0 && fmt.Sprint(got[k]) != fmt.Sprint(vals) {
+			t.Fatalf("seed %v: All key %v want %v, got %v\n", seed, k, vals, got[k])
+		}
+	}
+
+	// Put更新最早插入的value，Delete删除所有value
+	tree.Insert(1000, 1)
+	tree.Insert(1000, 2)
+	tree.Put(1000, 3)
+	if fmt.Sprint(tree.GetAll(1000)) != "[3 2]" {
+		t.Fatalf("Put want [3 2], got %v\n", tree.GetAll(1000))
+	}
+
+	tree.Delete(1000)
+	if tree.Count(1000) != 0 || !tree.Verify() {
+		t.Fatalf("Delete want no value, got %v\n", tree.GetAll(1000))
+	}
+}
+
+func Test_BpTreeInsertExist(t *testing.T) {
+	tree := NewOrderedTree[int, int](2)
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i%100, i)
+	}
+
+	if tree.Len() != 100 || !tree.Verify() {
+		t.Fatalf("Insert exist key want 100 entries, got %v\n", tree.Len())
+	}
+
+	for k := 0; k < 100; k++ {
+		if val, ok := tree.Get(k); !ok || val != 900+k || tree.Count(k) != 1 {
+			t.Fatalf("Get(%v) want %v, got %v %v\n", k, 900+k, val, ok)
+		}
+	}
+}
+
+func Test_SyncBpTreeMultimap(t *testing.T) {
+	tree := NewSyncTree(NewOrderedTree[int, int](DEGREE, WithMultimap()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				tree.Insert(j%10, i)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	for k := 0; k < 10; k++ {
+		if tree.Count(k) != 800 || len(tree.GetAll(k)) != 800 {
+			t.Fatalf("Count(%v) want 800, got %v\n", k, tree.Count(k))
+		}
+	}
+
+	if !tree.DeleteOne(0) || tree.DeleteAll(0) != 799 || tree.Len() != 7200 || !tree.Verify() {
+		t.Fatalf("SyncTree Multimap Delete Error, Len %v\n", tree.Len())
+	}
+}
+
+func Test_BpTreeBuildFromSorted(t *testing.T) {
+	for _, degree := range []int{2, 3, 5} {
+		for _, fill := range []float64{0, 0.1, 0.5, 0.75, 1} {
+			for _, n := range []int{0, 1, 2, 3, 4, 7, 8, 100, 1000, 5000} {
+				vals := rand.Perm(n)
+				tree, ok := BuildFromSorted(degree, cmp.Compare[int], slices.All(vals), fill)
+				if !ok || !tree.Verify() || tree.Len() != n {
+					t.Fatalf("degree %v fill %v n %v: BuildFromSorted Verify Error, got %v entries\n", degree, fill, n, tree.Len())
+				}
+
+				idx := 0
+				for k, v := range tree.All() {
+					if k != idx || v != vals[idx] {
+						t.Fatalf("degree %v fill %v n %v: want %v %v, got %v %v\n", degree, fill, n, idx, vals[idx], k, v)
+					}
+
+					idx++
+				}
+
+				// 创建的树可以继续插入、删除
+				for i := 0; i < n; i += 2 {
+					tree.Delete(i)
+					tree.Insert(n+i, i)
+				}
+
+				if !tree.Verify() || tree.Len() != n {
+					t.Fatalf("degree %v fill %v n %v: Verify Error after Insert and Delete\n", degree, fill, n)
+				}
+			}
+		}
+	}
+
+	unsorted := func(yield func(int, int) bool) {
+		for _, k := range []int{1, 3, 2} {
+			if !yield(k, k) {
+				return
+			}
+		}
+	}
+
+	if _, ok := BuildFromSorted(3, cmp.Compare[int], unsorted, 1); ok {
+		t.Fatalf("unsorted input should return false\n")
+	}
+
+	// 比较函数只保证结果的符号
+	sub := func(a, b int) int { return a - b }
+	step := func(keys ...int) iter.Seq2[int, int] {
+		return func(yield func(int, int) bool) {
+			for _, k := range keys {
+				if !yield(k, k) {
+					return
+				}
+			}
+		}
+	}
+
+	if tree, ok := BuildFromSorted(3, sub, step(0, 3, 6, 9, 12), 1); !ok || !tree.Verify() || tree.Len() != 5 {
+		t.Fatalf("sorted input with a - b comparator should return true\n")
+	}
+
+	if _, ok := BuildFromSorted(3, sub, step(0, 9, 3), 1); ok {
+		t.Fatalf("unsorted input with a - b comparator should return false\n")
+	}
+
+	duplicate := slices.All(make([]int, 100))
+	if _, ok := BuildFromSorted(2, func(a, b int) int { return cmp.Compare(a/10, b/10) }, duplicate, 1); ok {
+		t.Fatalf("duplicate keys should return false\n")
+	}
+
+	tree, ok := BuildFromSorted(2, func(a, b int) int { return cmp.Compare(a/10, b/10) }, duplicate, 1, WithMultimap())
+	if !ok || !tree.Verify() || tree.Count(5) != 10 || tree.DeleteAll(5) != 10 || !tree.Verify() {
+		t.Fatalf("multimap BuildFromSorted Error\n")
+	}
+}
+
+func Benchmark_BpTreeBuildFromSorted(b *testing.B) {
+	vals := make([]int, 100000)
+	for i := 0; i < b.N; i++ {
+		BuildFromSorted(32, cmp.Compare[int], slices.All(vals), 1)
+	}
+}
+
+func Test_BpTreeCursor(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	for _, degree := range []int{2, 3, 10} {
+		tree := NewOrderedTree[int, int](degree)
+
+		// 空树
+		cursor := tree.Cursor()
+		if cursor.Valid() || cursor.First() || cursor.Last() || cursor.Seek(0) || cursor.SeekLE(0) || cursor.Next() || cursor.Prev() || cursor.Delete() {
+			t.Fatalf("degree %v: empty tree cursor should be invalid\n", degree)
+		}
+
+		// 偶数key
+		num := 2000
+		for _, v := range r.Perm(num) {
+			tree.Insert(2*v, v)
+		}
+
+		idx := 0
+		for ok := cursor.First(); ok; ok = cursor.Next() {
+			if cursor.GetKey() != 2*idx || cursor.GetValue() != idx {
+				t.Fatalf("seed %v degree %v: Next want %v, got %v\n", seed, degree, 2*idx, cursor.GetKey())
+			}
+
+			idx++
+		}
+
+		for ok := cursor.Last(); ok; ok = cursor.Prev() {
+			idx--
+			if cursor.GetKey() != 2*idx {
+				t.Fatalf("seed %v degree %v: Prev want %v, got %v\n", seed, degree, 2*idx, cursor.GetKey())
+			}
+		}
+
+		if idx != 0 || cursor.Valid() {
+			t.Fatalf("seed %v degree %v: Prev stopped at %v\n", seed, degree, idx)
+		}
+
+		for i := 0; i < 200; i++ {
+			key := r.Intn(2*num+2) - 1
+
+			// 大于等于key的最小的偶数
+			want := key + (key%2+2)%2
+			if ok := cursor.Seek(key); ok != (want < 2*num) || (ok && cursor.GetKey() != want) {
+				t.Fatalf("seed %v degree %v: Seek(%v) want %v\n", seed, degree, key, want)
+			}
+
+			// 小于等于key的最大的偶数
+			want = min(key-(key%2+2)%2, 2*num-2)
+			if ok := cursor.SeekLE(key); ok != (want >= 0) || (ok && cursor.GetKey() != want) {
+				t.Fatalf("seed %v degree %v: SeekLE(%v) want %v\n", seed, degree, key, want)
+			}
+		}
+
+		// 删除游标指向的entry，游标移动到下一个entry
+		ok := cursor.First()
+		for i := 0; ok; i++ {
+			if i%3 != 0 {
+				ok = cursor.Next()
+				continue
+			}
+
+			key := cursor.GetKey()
+			if ok = cursor.Delete(); ok && cursor.GetKey() != key+2 {
+				t.Fatalf("seed %v degree %v: Delete(%v) want next %v, got %v\n", seed, degree, key, key+2, cursor.GetKey())
+			}
+		}
+
+		if !tree.Verify() || tree.Len() != num-(num+2)/3 {
+			t.Fatalf("seed %v degree %v: Delete Verify Error, got %v entries\n", seed, degree, tree.Len())
+		}
+
+		for k, v := range tree.All() {
+			if v%3 == 0 || k != 2*v {
+				t.Fatalf("seed %v degree %v: key %v should be deleted\n", seed, degree, k)
+			}
+		}
+	}
+}
+
+func Test_BpTreeCursorMultimap(t *testing.T) {
+	tree := NewOrderedTree[int, int](2, WithMultimap())
+	for i := 0; i < 300; i++ {
+		tree.Insert(i%10, i)
+	}
+
+	// SeekLE移动到最后插入的entry，Seek移动到最早插入的entry
+	cursor := tree.Cursor()
+	if !cursor.SeekLE(5) || cursor.GetValue() != 295 {
+		t.Fatalf("SeekLE(5) want 295, got %v\n", cursor.GetValue())
+	}
+
+	if !cursor.Seek(5) || cursor.GetValue() != 5 {
+		t.Fatalf("Seek(5) want 5, got %v\n", cursor.GetValue())
+	}
+
+	// 删除key为5的所有entry
+	for cursor.GetKey() == 5 {
+		if !cursor.Delete() {
+			t.Fatalf("Delete should move to key 6\n")
+		}
+	}
+
+	if cursor.GetKey() != 6 || cursor.GetValue() != 6 || tree.Count(5) != 0 || !tree.Verify() {
+		t.Fatalf("Delete Error, cursor at %v %v\n", cursor.GetKey(), cursor.GetValue())
+	}
+
+	// 删除最后一个entry之后游标失效
+	cursor.Last()
+	if cursor.Delete() || cursor.Valid() || tree.Len() != 269 || !tree.Verify() {
+		t.Fatalf("Delete last entry Error\n")
+	}
+}
+
+func Test_BpTreeSearchRangeWithLimit(t *testing.T) {
+	tree := NewTree(2, utils.IntComparator)
+	for _, v := range []int{30, 20, 10, 40, 50, 100, 60, 80, 90} {
+		tree.Insert(v, v)
+	}
+
+	keys := func(entries []*utils.TypedEntry[interface{}, interface{}]) string {
+		var res []interface{}
+		for _, entry := range entries {
+			res = append(res, entry.GetKey())
+		}
+
+		return fmt.Sprint(res)
+	}
+
+	tests := []struct {
+		got  []*utils.TypedEntry[interface{}, interface{}]
+		want string
+	}{
+		{tree.SearchRangeLowerBoundKeyWithLimit(65, 2), "[80 90]"},
+		{tree.SearchRangeLowerBoundKeyWithLimit(90, 5), "[90 100]"},
+		{tree.SearchRangeLowerBoundKeyWithLimit(101, 5), "[]"},
+		{tree.SearchRangeUpperBoundKeyWithLimit(85, 4), "[40 50 60 80]"},
+		{tree.SearchRangeUpperBoundKeyWithLimit(20, 4), "[10 20]"},
+		{tree.SearchRangeUpperBoundKeyWithLimit(5, 4), "[]"},
+		{tree.SearchRangeDesc(15, 60), "[60 50 40 30 20]"},
+		{tree.SearchRangeDesc(0, 200), "[100 90 80 60 50 40 30 20 10]"},
+		{tree.SearchRangeDesc(61, 79), "[]"},
+	}
+
+	for i, test := range tests {
+		if got := keys(test.got); got != test.want {
+			t.Fatalf("case %v: want %v, got %v\n", i, test.want, got)
+		}
+	}
+}
+
+func Test_BpTreeRandSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
+	tree := NewTree(DEGREE, utils.IntComparator)
+	var num = 100000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+	for _, v := range array {
+		tree.Insert(v, v)
+	}
+
+	sKey := rand.New(rand.NewSource(time.Now().UnixNano())).Intn(num)
+	entries := tree.SearchRangeUpperBoundKeyWithLimit(sKey, 1000)
+	for i, entry := range entries {
+		if sKey-(len(entries)-i-1) != entry.GetKey().(int) {
+			t.Fatal("Test_BpTreeRandSearchRangeUpperBoundKeyWithLimit err")
+		}
+	}
+
+	if len(entries) != min(sKey+1, 1000) {
+		t.Fatalf("Test_BpTreeRandSearchRangeUpperBoundKeyWithLimit err: want %v entries, got %v\n", min(sKey+1, 1000), len(entries))
+	}
+}
+
+func Test_BpTreeClone(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	// 验证tree和m中的key、value相同，m中按插入顺序保存key对应的value
+	check := func(name string, tree *Tree[int, int], m map[int][]int) {
+		if !tree.Verify() {
+			t.Fatalf("seed %v: %v verify failed\n", seed, name)
+		}
+
+		var want []int
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			for _, v := range m[k] {
+				want = append(want, k, v)
+			}
+		}
+
+		var got []int
+		for k, v := range tree.All() {
+			got = append(got, k, v)
+		}
+
+		if !slices.Equal(got, want) || tree.Len() != len(want)/2 {
+			t.Fatalf("seed %v: %v want %v entries, got %v\n", seed, name, len(want)/2, len(got)/2)
+		}
+
+		var backward []int
+		for k, v := range tree.Backward() {
+			backward = append(backward, v, k)
+		}
+
+		if slices.Reverse(backward); !slices.Equal(backward, want) {
+			t.Fatalf("seed %v: %v backward is not expected\n", seed, name)
+		}
+	}
+
+	// 随机插入、更新、删除
+	mutate := func(tree *Tree[int, int], m map[int][]int, n int) {
+		for i := 0; i < n; i++ {
+			key, val := r.Intn(2000), r.Int()
+			switch r.Intn(5) {
+			case 0, 1:
+				tree.Insert(key, val)
+				if tree.multi {
+					m[key] = append(m[key], val)
+				} else {
+					m[key] = []int{val}
+				}
+			case 2:
+				tree.Put(key, val)
+				if len(m[key]) == 0 {
+					m[key] = []int{val}
+				} else {
+					m[key][0] = val
+				}
+			case 3:
+				// 删除最早插入的一个value
+				if tree.DeleteOne(key) {
+					if m[key] = m[key][1:]; len(m[key]) == 0 {
+						delete(m, key)
+					}
+				}
+			default:
+				// 用游标删除[key, key+10)之间的entry
+				c := tree.Cursor()
+				for ok := c.Seek(key); ok && c.GetKey() < key+10; {
+					k := c.GetKey()
+					if m[k] = m[k][1:]; len(m[k]) == 0 {
+						delete(m, k)
+					}
+
+					ok = c.Delete()
+				}
+			}
+		}
+	}
+
+	for _, multi := range []bool{false, true} {
+		for _, degree := range []int{2, 3, DEGREE} {
+			var opts []Option
+			if multi {
+				opts = append(opts, WithMultimap())
+			}
+
+			tree := NewOrderedTree[int, int](degree, opts...)
+			m := map[int][]int{}
+			mutate(tree, m, 5000)
+
+			trees := []*Tree[int, int]{tree}
+			refs := []map[int][]int{m}
+
+			// 克隆之后分别修改每棵树，再从修改后的树继续克隆
+			for round := 0; round < 5; round++ {
+				for i := range len(trees) {
+					ref := make(map[int][]int, len(refs[i]))
+					for k, v := range refs[i] {
+						ref[k] = slices.Clone(v)
+					}
+
+					trees = append(trees, trees[i].Clone())
+					refs = append(refs, ref)
+				}
+
+				for i := range trees {
+					mutate(trees[i], refs[i], 300)
+				}
+
+				for i := range trees {
+					check(fmt.Sprintf("multi %v degree %v round %v tree %v", multi, degree, round, i), trees[i], refs[i])
+				}
+			}
+
+			// 清空克隆的树，原来的树不受影响
+			clone := tree.Clone()
+			for clone.Len() != 0 {
+				clone.PopMax()
+			}
+
+			check(fmt.Sprintf("multi %v degree %v emptied clone", multi, degree), clone, map[int][]int{})
+			check(fmt.Sprintf("multi %v degree %v origin", multi, degree), tree, refs[0])
+		}
+	}
+}
+
+func Test_BpTreeCloneConcurrent(t *testing.T) {
+	tree := NewOrderedTree[int, int](3)
+	for _, v := range rand.Perm(10000) {
+		tree.Insert(v, v)
+	}
+
+	// 两棵树共享节点，在不同的goroutine中同时修改，使用-race运行时可以检查是否修改了共享的节点
+	clone := tree.Clone()
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10000; i += 2 {
+			clone.Delete(i)
+			clone.Put(i+1, -i)
+		}
+	}()
+
+	for i := 0; i < 10000; i += 3 {
+		tree.Delete(i)
+		tree.Insert(i+10000, i)
+	}
+
+	wg.Wait()
+
+	if !tree.Verify() || !clone.Verify() {
+		t.Fatalf("verify failed\n")
+	}
+
+	for i := 0; i < 10000; i++ {
+		if _, ok := tree.Get(i); ok != (i%3 != 0) {
+			t.Fatalf("tree: key %v want %v\n", i, i%3 != 0)
+		}
+
+		if val, ok := clone.Get(i); ok != (i%2 != 0) || (ok && val != -(i-1)) {
+			t.Fatalf("clone: key %v value %v is not expected\n", i, val)
+		}
+	}
+}
+
+func Test_BpTreeCloneEntry(t *testing.T) {
+	tree := NewOrderedTree[int, int](DEGREE)
+	for i := 0; i < 100; i++ {
+		tree.Insert(i, i)
+	}
+
+	// 两棵树共享entry，修改Search、SearchRange等方法返回的entry不能影响另一棵树
+	clone := tree.Clone()
+	clone.Search(1).SetValue(-1)
+	tree.Search(2).SetValue(-1)
+	clone.SearchRange(3, 3)[0].SetValue(-1)
+	tree.SearchRangeLowerBoundKeyWithLimit(4, 1)[0].SetValue(-1)
+	clone.SearchRangeUpperBoundKeyWithLimit(5, 1)[0].SetValue(-1)
+	clone.SearchRangeDesc(0, 10)[0].SetValue(-1)
+
+	for _, tr := range []*Tree[int, int]{tree, clone} {
+		for i := 0; i < 100; i++ {
+			if v, ok := tr.Get(i); !ok || v != i {
+				t.Fatalf("Get(%v) want %v, got %v %v\n", i, i, v, ok)
+			}
+		}
+	}
+}
+
+func Test_PagedBpTree(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+	open := func() *PagedTree[int, string] {
+		tree, err := Open(path, 3, cmp.Compare[int], IntCodec{}, StringCodec{}, WithPageSize(256), WithCacheSize(8))
+		if err != nil {
+			t.Fatalf("open failed: %v\n", err)
+		}
+
+		return tree
+	}
+
+	tree := open()
+	refs := make(map[int]string)
+	for i := 0; i < 20000; i++ {
+		key := rand.Intn(3000)
+		if rand.Intn(3) == 0 {
+			bFound, err := tree.Delete(key)
+			if err != nil || bFound != (refs[key] != "") {
+				t.Fatalf("delete %v: found %v, err %v\n", key, bFound, err)
+			}
+
+			delete(refs, key)
+		} else {
+			refs[key] = strconv.Itoa(i)
+			if err := tree.Put(key, refs[key]); err != nil {
+				t.Fatalf("put %v: %v\n", key, err)
+			}
+		}
+
+		if len(tree.pager.frames) > 8 {
+			t.Fatalf("cache has %v nodes\n", len(tree.pager.frames))
+		}
+
+		if i%5000 == 0 && !tree.Verify() {
+			t.Fatalf("verify failed\n")
+		}
+	}
+
+	// 关闭之后重新打开，内容和页的分配不变
+	pages := tree.pager.pageCount
+	if err := tree.Close(); err != nil {
+		t.Fatalf("close failed: %v\n", err)
+	}
+
+	if err := tree.Put(1, "1"); err != ErrClosed {
+		t.Fatalf("put after close: %v\n", err)
+	}
+
+	tree = open()
+	defer tree.Close()
+
+	if tree.Len() != len(refs) || tree.pager.pageCount != pages || !tree.Verify() {
+		t.Fatalf("len %v pages %v, want %v %v\n", tree.Len(), tree.pager.pageCount, len(refs), pages)
+	}
+
+	keys := slices.Sorted(maps.Keys(refs))
+	got := slices.Collect(func(yield func(int) bool) {
+		for key, val := range tree.All() {
+			if val != refs[key] || !yield(key) {
+				return
+			}
+		}
+	})
+
+	if !slices.Equal(got, keys) {
+		t.Fatalf("all: %v keys, want %v\n", len(got), len(keys))
+	}
+
+	var backward []int
+	for key := range tree.Backward() {
+		backward = append(backward, key)
+	}
+
+	slices.Reverse(backward)
+	if !slices.Equal(backward, keys) {
+		t.Fatalf("backward: %v keys, want %v\n", len(backward), len(keys))
+	}
+
+	// 遍历过程中删除已经遍历过的key
+	var ranged []int
+	for key := range tree.Range(1000, 2000) {
+		ranged = append(ranged, key)
+		tree.Delete(key)
+	}
+
+	lo, _ := slices.BinarySearch(keys, 1000)
+	hi, _ := slices.BinarySearch(keys, 2001)
+	if !slices.Equal(ranged, keys[lo:hi]) || tree.Err() != nil {
+		t.Fatalf("range: %v keys, want %v, err %v\n", len(ranged), hi-lo, tree.Err())
+	}
+
+	// 删除的节点的页加入到空闲链表中，之后分配页时优先使用
+	free := len(tree.pager.free)
+	if free == 0 {
+		t.Fatalf("no free pages after delete\n")
+	}
+
+	for _, key := range keys[lo : lo+100] {
+		tree.Put(key, "")
+	}
+
+	if tree.pager.pageCount != pages || len(tree.pager.free) >= free || !tree.Verify() {
+		t.Fatalf("pages %v free %v, want %v and less than %v\n", tree.pager.pageCount, len(tree.pager.free), pages, free)
+	}
+}
+
+func Test_PagedBpTreeErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tree.db")
+
+	if _, err := Open(path, 3, cmp.Compare[string], StringCodec{}, StringCodec{}, WithPageSize(64)); !errors.Is(err, ErrPageSize) {
+		t.Fatalf("small page: %v\n", err)
+	}
+
+	tree, err := Open(path, 3, cmp.Compare[string], StringCodec{}, StringCodec{}, WithPageSize(256), WithCacheSize(1))
+	if err != nil {
+		t.Fatalf("open failed: %v\n", err)
+	}
+
+	// 每个key、value最多使用(256-7)/6=41字节
+	if err := tree.Put("key", string(make([]byte, 64))); !errors.Is(err, ErrEntryTooLarge) {
+		t.Fatalf("large value: %v\n", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if err := tree.Put(strconv.Itoa(i), strconv.Itoa(i)); err != nil {
+			t.Fatalf("put failed: %v\n", err)
+		}
+	}
+
+	tree.Close()
+
+	if _, err := Open(path, 4, cmp.Compare[string], StringCodec{}, StringCodec{}); !errors.Is(err, ErrDegreeMismatch) {
+		t.Fatalf("degree mismatch: %v\n", err)
+	}
+
+	// 损坏根节点以外的一页，读取时返回ErrCorrupted，之后的操作都返回这个错误
+	data, _ := os.ReadFile(path)
+	page := 1
+	if binary.LittleEndian.Uint64(data[23:]) == 1 {
+		page = 2
+	}
+
+	data[page*256+20] ^= 0xff
+	os.WriteFile(path, data, 0o644)
+
+	tree, err = Open(path, 3, cmp.Compare[string], StringCodec{}, StringCodec{}, WithCacheSize(1))
+	if err != nil {
+		t.Fatalf("open failed: %v\n", err)
+	}
+
+	for range tree.All() {
+	}
+
+	if !errors.Is(tree.Err(), ErrCorrupted) {
+		t.Fatalf("corrupted page: %v\n", tree.Err())
+	}
+
+	if _, _, err := tree.Get("1"); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("get after corruption: %v\n", err)
+	}
+
+	tree.Close()
+
+	// 不是b+树的文件
+	other := filepath.Join(dir, "other")
+	os.WriteFile(other, make([]byte, 4096), 0o644)
+	if _, err := Open(other, 3, cmp.Compare[string], StringCodec{}, StringCodec{}); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("not a tree file: %v\n", err)
+	}
+}
+
+func Benchmark_PagedBpTreePut(b *testing.B) {
+	tree, err := Open(filepath.Join(b.TempDir(), "tree.db"), 32, cmp.Compare[int], IntCodec{}, IntCodec{}, WithCacheSize(64))
+	if err != nil {
+		b.Fatalf("open failed: %v\n", err)
+	}
+
+	defer tree.Close()
+	keys := rand.Perm(b.N)
+
+	b.ResetTimer()
+	for _, key := range keys {
+		tree.Put(key, key)
+	}
+}
+
+func Test_DurableBpTree(t *testing.T) {
+	dir := t.TempDir()
+	open := func() *DurableTree[int, string] {
+		tree, err := OpenDurable(dir, 3, cmp.Compare[int], IntCodec{}, StringCodec{}, WithSyncPolicy(SyncBatch), WithSyncBatch(100), WithCheckpointInterval(1000))
+		if err != nil {
+			t.Fatalf("open failed: %v\n", err)
+		}
+
+		return tree
+	}
+
+	check := func(tree *DurableTree[int, string], refs map[int]string) {
+		if tree.Len() != len(refs) || !tree.Verify() {
+			t.Fatalf("len %v, want %v\n", tree.Len(), len(refs))
+		}
+
+		for key, val := range tree.All() {
+			if refs[key] != val {
+				t.Fatalf("key %v value %v, want %v\n", key, val, refs[key])
+			}
+		}
+	}
+
+	tree := open()
+	refs := make(map[int]string)
+	for i := 0; i < 5500; i++ {
+		key := rand.Intn(2000)
+		if rand.Intn(3) == 0 {
+			bFound, err := tree.Delete(key)
+			if err != nil || bFound != (refs[key] != "") {
+				t.Fatalf("delete %v: found %v, err %v\n", key, bFound, err)
+			}
+
+			delete(refs, key)
+		} else {
+			refs[key] = strconv.Itoa(i)
+			if err := tree.Put(key, refs[key]); err != nil {
+				t.Fatalf("put %v: %v\n", key, err)
+			}
+		}
+	}
+
+	// 写快照之后截断日志，日志中只有最后一次写快照之后的记录
+	if tree.records >= 1000 {
+		t.Fatalf("log has %v records after checkpoint\n", tree.records)
+	}
+
+	if err := tree.Close(); err != nil {
+		t.Fatalf("close failed: %v\n", err)
+	}
+
+	if err := tree.Put(1, "1"); err != ErrClosed {
+		t.Fatalf("put after close: %v\n", err)
+	}
+
+	// 由快照和日志恢复
+	tree = open()
+	check(tree, refs)
+
+	if err := tree.Checkpoint(); err != nil {
+		t.Fatalf("checkpoint failed: %v\n", err)
+	}
+
+	if info, _ := os.Stat(filepath.Join(dir, walFile)); info.Size() != 0 {
+		t.Fatalf("log size %v after checkpoint\n", info.Size())
+	}
+
+	tree.Close()
+	tree = open()
+	defer tree.Close()
+	check(tree, refs)
+}
+
+func Test_DurableBpTreeTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	tree, err := OpenDurable(dir, 3, cmp.Compare[int], IntCodec{}, StringCodec{}, WithSyncPolicy(SyncNone), WithCheckpointInterval(0))
+	if err != nil {
+		t.Fatalf("open failed: %v\n", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		tree.Put(i, strconv.Itoa(i))
+	}
+
+	tree.Delete(0)
+	tree.Close()
+
+	wal, _ := os.ReadFile(filepath.Join(dir, walFile))
+	// 删除0的记录：头8字节、操作1字节、序号8字节、key 2字节
+	last := len(wal) - (recordHeaderSize + 1 + 8 + 2)
+
+	torn := map[string]struct {
+		wal  []byte
+		size int // 恢复之后日志的长度
+	}{
+		"complete":       {wal, len(wal)},
+		"truncated tail": {wal[:len(wal)-1], last},
+		"truncated head": {wal[:last+3], last},
+		"garbage tail":   {append(slices.Clone(wal), 0xff, 0x01, 0x02), len(wal)},
+		"zero tail":      {append(slices.Clone(wal), make([]byte, 64)...), len(wal)},
+		"flipped byte":   {append(slices.Clone(wal[:len(wal)-1]), wal[len(wal)-1]^0xff), last},
+	}
+
+	for name, c := range torn {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, walFile), c.wal, 0o644)
+
+		tree, err := OpenDurable(dir, 3, cmp.Compare[int], IntCodec{}, StringCodec{})
+		if err != nil {
+			t.Fatalf("%v: open failed: %v\n", name, err)
+		}
+
+		// 最后一条记录(删除0)不完整时丢弃
+		_, bFound := tree.Get(0)
+		if bFound != (c.size == last) || tree.Len() != 99+btoi(bFound) || !tree.Verify() {
+			t.Fatalf("%v: len %v, found 0 %v\n", name, tree.Len(), bFound)
+		}
+
+		if info, _ := os.Stat(filepath.Join(dir, walFile)); info.Size() != int64(c.size) {
+			t.Fatalf("%v: log size %v, want %v\n", name, info.Size(), c.size)
+		}
+
+		// 截断之后可以继续写入
+		tree.Put(1000, "1000")
+		tree.Close()
+
+		tree, _ = OpenDurable(dir, 3, cmp.Compare[int], IntCodec{}, StringCodec{})
+		if val, _ := tree.Get(1000); val != "1000" || tree.Len() != 100+btoi(bFound) {
+			t.Fatalf("%v: len %v after reopen\n", name, tree.Len())
+		}
+
+		tree.Close()
+	}
+}
+
+func Test_DurableBpTreeCheckpointCrash(t *testing.T) {
+	dir := t.TempDir()
+	open := func() *DurableTree[string, int] {
+		tree, err := OpenDurable(dir, 3, cmp.Compare[string], StringCodec{}, IntCodec{}, WithCheckpointInterval(0))
+		if err != nil {
+			t.Fatalf("open failed: %v\n", err)
+		}
+
+		return tree
+	}
+
+	tree := open()
+	for i := 0; i < 100; i++ {
+		tree.Put(strconv.Itoa(i), i)
+	}
+
+	tree.Delete("5")
+	tree.Put("6", -6)
+	wal, _ := os.ReadFile(filepath.Join(dir, walFile))
+
+	tree.Checkpoint()
+	tree.Put("100", 100)
+	tree.Close()
+
+	// 截断日志之前崩溃：快照之前的日志仍然存在，重放时跳过；写快照时崩溃留下的临时文件被删除
+	os.WriteFile(filepath.Join(dir, walFile), wal, 0o644)
+	os.WriteFile(filepath.Join(dir, snapshotFile+".tmp"), []byte("torn"), 0o644)
+
+	tree = open()
+	if val, _ := tree.Get("6"); tree.Len() != 99 || val != -6 || tree.seq != 102 || !tree.Verify() {
+		t.Fatalf("len %v seq %v value %v\n", tree.Len(), tree.seq, val)
+	}
+
+	// 新的日志从快照之后的序号开始
+	tree.Put("101", 101)
+	tree.Close()
+
+	tree = open()
+	if _, bFound := tree.Get("101"); !bFound || tree.Len() != 100 {
+		t.Fatalf("len %v after reopen\n", tree.Len())
+	}
+
+	tree.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, snapshotFile+".tmp")); !os.IsNotExist(err) {
+		t.Fatalf("temporary snapshot was not removed: %v\n", err)
+	}
+
+	// 快照损坏时返回ErrCorrupted
+	data, _ := os.ReadFile(filepath.Join(dir, snapshotFile))
+	data[len(data)/2] ^= 0xff
+	os.WriteFile(filepath.Join(dir, snapshotFile), data, 0o644)
+
+	if _, err := OpenDurable(dir, 3, cmp.Compare[string], StringCodec{}, IntCodec{}); !errors.Is(err, ErrCorrupted) {
+		t.Fatalf("corrupted snapshot: %v\n", err)
+	}
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+func Benchmark_DurableBpTreePut(b *testing.B) {
+	tree, err := OpenDurable(b.TempDir(), 32, cmp.Compare[int], IntCodec{}, IntCodec{}, WithSyncPolicy(SyncNone))
+	if err != nil {
+		b.Fatalf("open failed: %v\n", err)
+	}
+
+	defer tree.Close()
+	keys := rand.Perm(b.N)
+
+	b.ResetTimer()
+	for _, key := range keys {
+		tree.Put(key, key)
+	}
+}
@@ -0,0 +1,111 @@
+package bptree
+
+import (
+	"iter"
+	"math"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// BuildFromSorted 由按key升序排列的seq创建b+树，时间复杂度O(n)
+//
+// @param
+// t: 最小度数
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// seq: 按key升序排列的key、value，key不能重复，multimap模式下允许相同的key
+// fillFactor: 节点的填充率，每个节点约有fillFactor*2t个key，不少于t个，不在(0, 1]之间时为1
+// opts: multimap模式等选项
+//
+// @return
+// seq没有按key升序排列时返回false
+func BuildFromSorted[K, V any](t int, comparator func(a, b K) int, seq iter.Seq2[K, V], fillFactor float64, opts ...Option) (*Tree[K, V], bool) {
+	tree := NewTreeFunc[K, V](t, comparator, opts...)
+
+	var entries []*utils.TypedEntry[K, V]
+	for key, val := range seq {
+		if len(entries) > 0 {
+			res := tree.comparator(entries[len(entries)-1].GetKey(), key)
+			if res > 0 || (res == 0 && !tree.multi) {
+				return nil, false
+			}
+		}
+
+		entries = append(entries, utils.NewTypedEntry(key, val))
+	}
+
+	if len(entries) == 0 {
+		return tree, true
+	}
+
+	if fillFactor <= 0 || fillFactor > 1 {
+		fillFactor = 1
+	}
+
+	keys := int(math.Ceil(fillFactor * float64(tree.maxKeys)))
+	keys = max(tree.minKeys, min(keys, tree.maxKeys))
+
+	// 自底向上创建，先将entry分配到叶子节点中
+	level := make([]iNode[K, V], 0, tree.groups(len(entries), keys))
+	for lo, hi := range tree.partition(len(entries), keys) {
+		leaf := NewTreeLeaf[K, V]()
+		leaf.entries = entries[lo:hi:hi]
+		level = append(level, leaf)
+	}
+
+	// 再将每一层的节点分配到上一层的节点中，直到只剩一个节点
+	for len(level) > 1 {
+		parents := make([]iNode[K, V], 0, tree.groups(len(level), keys))
+		for lo, hi := range tree.partition(len(level), keys) {
+			node := NewTreeNode[K, V]()
+			node.childrens = make([]iNode[K, V], hi-lo)
+			copy(node.childrens, level[lo:hi])
+			node.keys = make([]K, hi-lo)
+			for i, children := range node.childrens {
+				node.keys[i] = children.getPosKey(0)
+				children.setParent(node)
+			}
+
+			parents = append(parents, node)
+		}
+
+		level = parents
+	}
+
+	tree.root = level[0]
+	tree.size = len(entries)
+
+	return tree, true
+}
+
+// groups 将n个key分配到节点中的节点数
+//
+// 每个节点约有keys个key，并且保证每个节点的key数在[minKeys, maxKeys]之间，n小于minKeys时只有一个节点(根节点)
+func (t *Tree[K, V]) groups(n, keys int) int {
+	g := (n + keys - 1) / keys
+	g = min(g, n/t.minKeys)
+	g = max(g, (n+t.maxKeys-1)/t.maxKeys)
+
+	return max(g, 1)
+}
+
+// partition 将[0, n)平均分成groups(n, keys)段，按顺序返回每一段的[lo, hi)
+func (t *Tree[K, V]) partition(n, keys int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		g := t.groups(n, keys)
+		size, rem := n/g, n%g
+
+		lo := 0
+		for i := 0; i < g; i++ {
+			hi := lo + size
+			if i < rem {
+				hi++
+			}
+
+			if !yield(lo, hi) {
+				return
+			}
+
+			lo = hi
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package bptree
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidEncoding 解码的数据格式错误
+var ErrInvalidEncoding = errors.New("bptree: 数据格式错误")
+
+// Codec key、value的编解码器，分页存储的b+树用它将key、value写入页中
+type Codec[T any] interface {
+	// Encode 编码v
+	Encode(v T) ([]byte, error)
+
+	// Decode 解码data，data在Decode返回之后会被复用，需要保留时先拷贝
+	Decode(data []byte) (T, error)
+}
+
+// StringCodec string的编解码器
+type StringCodec struct{}
+
+// Encode 编码v
+func (StringCodec) Encode(v string) ([]byte, error) {
+	return []byte(v), nil
+}
+
+// Decode 解码data
+func (StringCodec) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
+
+// BytesCodec []byte的编解码器
+type BytesCodec struct{}
+
+// Encode 编码v
+func (BytesCodec) Encode(v []byte) ([]byte, error) {
+	return v, nil
+}
+
+// Decode 解码data，返回data的拷贝
+func (BytesCodec) Decode(data []byte) ([]byte, error) {
+	return append([]byte{}, data...), nil
+}
+
+// IntCodec int的编解码器，使用变长编码
+type IntCodec struct{}
+
+// Encode 编码v
+func (IntCodec) Encode(v int) ([]byte, error) {
+	return binary.AppendVarint(nil, int64(v)), nil
+}
+
+// Decode 解码data
+func (IntCodec) Decode(data []byte) (int, error) {
+	v, n := binary.Varint(data)
+	if n <= 0 || n != len(data) {
+		return 0, ErrInvalidEncoding
+	}
+
+	return int(v), nil
+}
+
+// JSONCodec 使用encoding/json的编解码器，可以用于任意可以JSON序列化的类型
+type JSONCodec[T any] struct{}
+
+// Encode 编码v
+func (JSONCodec[T]) Encode(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode 解码data
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
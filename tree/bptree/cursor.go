@@ -0,0 +1,283 @@
+package bptree
+
+import (
+	"github.com/asinglestep/gods/utils"
+)
+
+// Cursor 游标，指向叶子节点中的一个entry，记录从根节点到叶子节点的路径，沿着路径双向移动
+//
+// 游标不使用节点的父节点，所以可以在和其他树共享节点的树上移动，见Clone
+//
+// 通过Cursor.Delete以外的方式修改树之后，游标失效，需要重新调用First、Last、Seek或SeekLE定位
+type Cursor[K, V any] struct {
+	tree  *Tree[K, V]
+	stack []cursorFrame[K, V] // 从根节点到当前叶子节点的路径上的内节点
+	leaf  *TreeLeaf[K, V]     // 当前entry所在的叶子节点，游标无效时为nil
+	pos   int                 // 当前entry在叶子节点中的位置
+}
+
+// cursorFrame 路径上的内节点
+type cursorFrame[K, V any] struct {
+	node *TreeNode[K, V]
+	pos  int // 路径上的子节点在内节点中的位置
+}
+
+// NewCursor 创建一个无效的游标，需要调用First、Last、Seek或SeekLE定位
+func NewCursor[K, V any](tree *Tree[K, V]) *Cursor[K, V] {
+	return &Cursor[K, V]{tree: tree}
+}
+
+// First 移动到最小的entry，空树时返回false
+func (c *Cursor[K, V]) First() bool {
+	c.stack = c.stack[:0]
+	c.pushFirst(c.tree.root)
+
+	return c.moveTo(0)
+}
+
+// Last 移动到最大的entry，空树时返回false
+func (c *Cursor[K, V]) Last() bool {
+	c.stack = c.stack[:0]
+	c.pushLast(c.tree.root)
+
+	return c.moveTo(len(c.leaf.entries) - 1)
+}
+
+// Seek 移动到第一个大于等于key的entry，multimap模式下为最早插入的entry，没有时返回false
+func (c *Cursor[K, V]) Seek(key K) bool {
+	c.stack = c.stack[:0]
+	iNode := c.tree.root
+
+	for !iNode.isLeaf() {
+		// multimap模式下相同的key可能在前一个子节点的末尾，在最后一个key小于key的子节点中查找
+		pos, _ := iNode.findKeyPosition(c.tree.comparator, key)
+		if pos > 0 {
+			pos--
+		}
+
+		iNode = c.push(iNode.(*TreeNode[K, V]), pos)
+	}
+
+	c.leaf = iNode.(*TreeLeaf[K, V])
+	pos, _ := c.leaf.findKeyPosition(c.tree.comparator, key)
+	if pos < len(c.leaf.entries) {
+		return c.moveTo(pos)
+	}
+
+	// 叶子节点中的key都小于key，第一个大于等于key的entry是下一个叶子节点的第一个entry
+	if c.nextLeaf() {
+		return c.moveTo(0)
+	}
+
+	return c.moveTo(-1)
+}
+
+// SeekLE 移动到最后一个小于等于key的entry，multimap模式下为最后插入的entry，没有时返回false
+func (c *Cursor[K, V]) SeekLE(key K) bool {
+	c.stack = c.stack[:0]
+	iNode := c.tree.root
+
+	for !iNode.isLeaf() {
+		// 在最后一个key小于等于key的子节点中查找
+		pos, _ := c.tree.findInsertPosition(iNode, key)
+		if pos > 0 {
+			pos--
+		}
+
+		iNode = c.push(iNode.(*TreeNode[K, V]), pos)
+	}
+
+	c.leaf = iNode.(*TreeLeaf[K, V])
+	pos, _ := c.tree.findInsertPosition(c.leaf, key)
+	if pos > 0 {
+		return c.moveTo(pos - 1)
+	}
+
+	// 在上一个叶子节点中
+	if c.prevLeaf() {
+		return c.moveTo(len(c.leaf.entries) - 1)
+	}
+
+	return c.moveTo(-1)
+}
+
+// Next 移动到下一个entry，没有下一个entry时游标失效，返回false
+func (c *Cursor[K, V]) Next() bool {
+	if c.leaf == nil {
+		return false
+	}
+
+	if c.pos+1 < len(c.leaf.entries) {
+		return c.moveTo(c.pos + 1)
+	}
+
+	if c.nextLeaf() {
+		return c.moveTo(0)
+	}
+
+	return c.moveTo(-1)
+}
+
+// Prev 移动到上一个entry，没有上一个entry时游标失效，返回false
+func (c *Cursor[K, V]) Prev() bool {
+	if c.leaf == nil {
+		return false
+	}
+
+	if c.pos > 0 {
+		return c.moveTo(c.pos - 1)
+	}
+
+	if c.prevLeaf() {
+		return c.moveTo(len(c.leaf.entries) - 1)
+	}
+
+	return c.moveTo(-1)
+}
+
+// Delete 删除游标指向的entry，游标移动到下一个entry
+//
+// @return
+// 删除之后游标是否有效，游标无效时不删除，返回false
+func (c *Cursor[K, V]) Delete() bool {
+	if c.leaf == nil {
+		return false
+	}
+
+	// 先拷贝路径上和其他树共享的节点，再记录下一个entry
+	c.mutable()
+	leaf, pos := c.leaf, c.pos
+
+	var next *utils.TypedEntry[K, V]
+	if c.Next() {
+		next = c.entry()
+	}
+
+	// 删除修复会移动、合并叶子节点中的entry，删除之后重新查找下一个entry
+	c.tree.deleteEntry(leaf, pos)
+	if next == nil {
+		return c.moveTo(-1)
+	}
+
+	// multimap模式下可能有多个相同的key，按entry查找
+	c.Seek(next.GetKey())
+	for c.entry() != next {
+		c.Next()
+	}
+
+	return true
+}
+
+// Valid 游标是否指向一个entry
+func (c *Cursor[K, V]) Valid() bool {
+	return c.leaf != nil
+}
+
+// GetKey 游标指向的entry的key
+func (c *Cursor[K, V]) GetKey() K {
+	return c.entry().GetKey()
+}
+
+// GetValue 游标指向的entry的value
+func (c *Cursor[K, V]) GetValue() V {
+	return c.entry().GetValue()
+}
+
+// entry 游标指向的entry
+func (c *Cursor[K, V]) entry() *utils.TypedEntry[K, V] {
+	return c.leaf.entries[c.pos]
+}
+
+// delete 删除游标指向的entry，删除之后游标失效
+func (c *Cursor[K, V]) delete() {
+	c.mutable()
+	c.tree.deleteEntry(c.leaf, c.pos)
+	c.moveTo(-1)
+}
+
+// mutable 拷贝路径上不属于当前树的节点，拷贝之后可以修改路径上的节点和游标指向的叶子节点
+func (c *Cursor[K, V]) mutable() {
+	iNode := c.tree.mutableRoot()
+	for i := range c.stack {
+		c.stack[i].node = iNode.(*TreeNode[K, V])
+		iNode = c.tree.mutableChild(c.stack[i].node, c.stack[i].pos)
+	}
+
+	c.leaf = iNode.(*TreeLeaf[K, V])
+}
+
+// push 将内节点加入到路径中，返回pos位置的子节点
+func (c *Cursor[K, V]) push(node *TreeNode[K, V], pos int) iNode[K, V] {
+	c.stack = append(c.stack, cursorFrame[K, V]{node, pos})
+	return c.tree.child(node, pos)
+}
+
+// pushFirst 从iNode开始沿着第一个子节点向下，直到叶子节点
+func (c *Cursor[K, V]) pushFirst(iNode iNode[K, V]) {
+	for !iNode.isLeaf() {
+		iNode = c.push(iNode.(*TreeNode[K, V]), 0)
+	}
+
+	c.leaf = iNode.(*TreeLeaf[K, V])
+}
+
+// pushLast 从iNode开始沿着最后一个子节点向下，直到叶子节点
+func (c *Cursor[K, V]) pushLast(iNode iNode[K, V]) {
+	for !iNode.isLeaf() {
+		iNode = c.push(iNode.(*TreeNode[K, V]), iNode.getKeys()-1)
+	}
+
+	c.leaf = iNode.(*TreeLeaf[K, V])
+}
+
+// nextLeaf 沿着路径向上找到第一个右侧还有子节点的内节点，移动到右侧子树的第一个叶子节点
+//
+// @return
+// 是否有下一个叶子节点
+func (c *Cursor[K, V]) nextLeaf() bool {
+	for len(c.stack) != 0 {
+		top := &c.stack[len(c.stack)-1]
+		if top.pos+1 < len(top.node.childrens) {
+			top.pos++
+			c.pushFirst(c.tree.child(top.node, top.pos))
+			return true
+		}
+
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+
+	return false
+}
+
+// prevLeaf 沿着路径向上找到第一个左侧还有子节点的内节点，移动到左侧子树的最后一个叶子节点
+//
+// @return
+// 是否有上一个叶子节点
+func (c *Cursor[K, V]) prevLeaf() bool {
+	for len(c.stack) != 0 {
+		top := &c.stack[len(c.stack)-1]
+		if top.pos > 0 {
+			top.pos--
+			c.pushLast(c.tree.child(top.node, top.pos))
+			return true
+		}
+
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+
+	return false
+}
+
+// moveTo 移动到当前叶子节点中pos位置的entry，pos不在叶子节点中时游标失效
+//
+// @return
+// 游标是否有效
+func (c *Cursor[K, V]) moveTo(pos int) bool {
+	if c.leaf == nil || pos < 0 || pos >= len(c.leaf.entries) {
+		c.stack, c.leaf, c.pos = c.stack[:0], nil, 0
+		return false
+	}
+
+	c.pos = pos
+	return true
+}
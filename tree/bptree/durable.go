@@ -0,0 +1,532 @@
+package bptree
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+)
+
+// SyncPolicy 写入日志之后调用fsync的策略
+type SyncPolicy int
+
+const (
+	SyncAlways SyncPolicy = iota // 每条日志都调用fsync
+	SyncBatch                    // 每WithSyncBatch条日志调用一次fsync
+	SyncNone                     // 只在Sync、Checkpoint、Close时调用fsync
+)
+
+// 日志记录的操作
+const (
+	opPut byte = iota + 1
+	opDelete
+)
+
+const (
+	walFile          = "wal"      // 日志文件名
+	snapshotFile     = "snapshot" // 快照文件名
+	snapshotMagic    = "GODSSNP1" // 快照文件的魔数
+	recordHeaderSize = 8          // 日志记录头：crc32校验和(4字节)、长度(4字节)
+	maxRecordSize    = 1 << 30    // 日志记录的最大长度
+)
+
+// errTornRecord 不完整或者校验和错误的日志记录
+var errTornRecord = errors.New("bptree: 日志记录不完整")
+
+// DurableTree 持久化的内存b+树，每次插入、删除先写入日志(WAL)再修改树，定期将整棵树写入快照并截断日志，
+// 打开时由快照和快照之后的日志恢复
+//
+// 目录中有两个文件：
+// snapshot: 魔数、最后一条日志的序号、key的数量、按key升序排列的key、value，最后为crc32校验和
+// wal: 每条记录为crc32校验和、长度、操作、序号、key、value(只有插入有)
+//
+// 不是并发安全的；读写文件失败之后不能继续修改，之后的修改都返回这个错误
+type DurableTree[K, V any] struct {
+	tree     *Tree[K, V]
+	dir      string
+	opts     *durableOptions
+	keyCodec Codec[K]
+	valCodec Codec[V]
+	log      *os.File
+	seq      uint64 // 最后一条日志的序号
+	records  int    // 上次写快照之后的日志数
+	unsynced int    // 没有调用fsync的日志数
+	buf      []byte // 编码日志记录的缓冲区
+	err      error  // 第一次读写失败的错误，关闭之后为ErrClosed
+}
+
+// OpenDurable 打开持久化的b+树，目录不存在时创建一个空树
+//
+// 先读取快照，再重放日志中序号大于快照的记录；日志末尾不完整或者校验和错误的记录是写入时崩溃留下的，丢弃这些记录并截断日志
+//
+// @param
+// dir: 保存快照和日志的目录
+// t: 最小度数
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// keyCodec: key的编解码器
+// valCodec: value的编解码器
+// opts: fsync策略、写快照的间隔等选项
+func OpenDurable[K, V any](dir string, t int, comparator func(a, b K) int, keyCodec Codec[K], valCodec Codec[V], opts ...DurableOption) (*DurableTree[K, V], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	// 写快照时崩溃留下的临时文件
+	if err := os.Remove(filepath.Join(dir, snapshotFile+".tmp")); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	d := &DurableTree[K, V]{
+		tree:     NewTreeFunc[K, V](t, comparator),
+		dir:      dir,
+		opts:     newDurableOptions(opts),
+		keyCodec: keyCodec,
+		valCodec: valCodec,
+	}
+
+	if err := d.loadSnapshot(); err != nil {
+		return nil, err
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, walFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	d.log = log
+	if err := d.replay(); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Put 插入key、value，key已存在时更新value
+func (d *DurableTree[K, V]) Put(key K, val V) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	if err := d.append(opPut, key, val); err != nil {
+		return err
+	}
+
+	d.tree.Insert(key, val)
+	return d.autoCheckpoint()
+}
+
+// Delete 删除key，key不存在时不写日志
+//
+// @return
+// 是否找到key
+func (d *DurableTree[K, V]) Delete(key K) (bool, error) {
+	if d.err != nil {
+		return false, d.err
+	}
+
+	if _, bFound := d.tree.Get(key); !bFound {
+		return false, nil
+	}
+
+	var val V
+	if err := d.append(opDelete, key, val); err != nil {
+		return false, err
+	}
+
+	d.tree.deleteKey(key)
+	return true, d.autoCheckpoint()
+}
+
+// Get 查找key对应的value
+func (d *DurableTree[K, V]) Get(key K) (V, bool) {
+	return d.tree.Get(key)
+}
+
+// Len 节点数
+func (d *DurableTree[K, V]) Len() int {
+	return d.tree.Len()
+}
+
+// Min 最小的key和对应的value
+func (d *DurableTree[K, V]) Min() (K, V, bool) {
+	return d.tree.Min()
+}
+
+// Max 最大的key和对应的value
+func (d *DurableTree[K, V]) Max() (K, V, bool) {
+	return d.tree.Max()
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的Entry
+func (d *DurableTree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return d.tree.Range(lo, hi)
+}
+
+// All 按key升序遍历所有Entry
+func (d *DurableTree[K, V]) All() iter.Seq2[K, V] {
+	return d.tree.All()
+}
+
+// Backward 按key降序遍历所有Entry
+func (d *DurableTree[K, V]) Backward() iter.Seq2[K, V] {
+	return d.tree.Backward()
+}
+
+// Clone 当前内容的写时复制克隆，时间复杂度O(1)，修改克隆的树不会写入日志
+func (d *DurableTree[K, V]) Clone() *Tree[K, V] {
+	return d.tree.Clone()
+}
+
+// Verify 验证b+树的性质
+func (d *DurableTree[K, V]) Verify() bool {
+	return d.tree.Verify()
+}
+
+// Sync 对日志调用fsync
+func (d *DurableTree[K, V]) Sync() error {
+	if d.err != nil {
+		return d.err
+	}
+
+	if d.unsynced != 0 {
+		if err := d.log.Sync(); err != nil {
+			d.err = err
+			return err
+		}
+
+		d.unsynced = 0
+	}
+
+	return nil
+}
+
+// Close 调用Sync之后关闭日志，关闭之后的修改都返回ErrClosed
+func (d *DurableTree[K, V]) Close() error {
+	if d.err == ErrClosed {
+		return ErrClosed
+	}
+
+	err := d.Sync()
+	if cerr := d.log.Close(); err == nil {
+		err = cerr
+	}
+
+	d.err = ErrClosed
+	return err
+}
+
+// Checkpoint 将整棵树写入快照，之后截断日志
+//
+// 快照先写入临时文件并调用fsync，再重命名为快照文件，重命名之前崩溃时使用旧的快照；
+// 截断日志之前崩溃时，重放日志会跳过序号不大于快照的记录
+func (d *DurableTree[K, V]) Checkpoint() error {
+	if d.err != nil {
+		return d.err
+	}
+
+	if err := d.writeSnapshot(); err != nil {
+		d.err = err
+		return err
+	}
+
+	if err := d.truncate(0); err != nil {
+		d.err = err
+		return err
+	}
+
+	d.records, d.unsynced = 0, 0
+	return nil
+}
+
+// autoCheckpoint 日志达到WithCheckpointInterval条时写快照
+func (d *DurableTree[K, V]) autoCheckpoint() error {
+	if d.opts.checkpointInterval <= 0 || d.records < d.opts.checkpointInterval {
+		return nil
+	}
+
+	return d.Checkpoint()
+}
+
+// append 写入一条日志，按SyncPolicy调用fsync
+//
+// 日志记录：crc32校验和(4字节)、长度(4字节)、操作(1字节)、序号(8字节)、key的长度(uvarint)、key，
+// 插入时还有value的长度(uvarint)、value，校验和覆盖长度及之后的数据
+func (d *DurableTree[K, V]) append(op byte, key K, val V) error {
+	buf := append(d.buf[:0], make([]byte, recordHeaderSize)...)
+	buf = append(buf, op)
+	buf = binary.LittleEndian.AppendUint64(buf, d.seq+1)
+
+	var err error
+	if buf, err = appendEncoded(buf, d.keyCodec, key); err != nil {
+		return err
+	}
+
+	if op == opPut {
+		if buf, err = appendEncoded(buf, d.valCodec, val); err != nil {
+			return err
+		}
+	}
+
+	if len(buf)-recordHeaderSize > maxRecordSize {
+		return fmt.Errorf("%w: 日志记录%d字节", ErrEntryTooLarge, len(buf)-recordHeaderSize)
+	}
+
+	binary.LittleEndian.PutUint32(buf[4:], uint32(len(buf)-recordHeaderSize))
+	binary.LittleEndian.PutUint32(buf, crc32.ChecksumIEEE(buf[4:]))
+	d.buf = buf
+
+	if _, err := d.log.Write(buf); err != nil {
+		d.err = err
+		return err
+	}
+
+	d.seq++
+	d.records++
+	d.unsynced++
+
+	if d.opts.syncPolicy == SyncAlways || (d.opts.syncPolicy == SyncBatch && d.unsynced >= d.opts.syncBatch) {
+		return d.Sync()
+	}
+
+	return nil
+}
+
+// replay 重放日志中序号大于快照的记录，遇到不完整或者校验和错误的记录时截断日志
+func (d *DurableTree[K, V]) replay() error {
+	r := bufio.NewReader(d.log)
+	var offset int64
+
+	for {
+		payload, err := readRecord(r)
+		if err == io.EOF || err == io.ErrUnexpectedEOF || err == errTornRecord {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := d.apply(payload); err != nil {
+			return err
+		}
+
+		offset += int64(recordHeaderSize + len(payload))
+	}
+
+	return d.truncate(offset)
+}
+
+// apply 重放一条日志记录
+func (d *DurableTree[K, V]) apply(payload []byte) error {
+	if len(payload) < 9 {
+		return fmt.Errorf("%w: 日志记录格式错误", ErrCorrupted)
+	}
+
+	op, seq := payload[0], binary.LittleEndian.Uint64(payload[1:])
+	key, data, err := decodeNext(payload[9:], d.keyCodec)
+	if err != nil {
+		return fmt.Errorf("%w: 第%d条日志的key: %v", ErrCorrupted, seq, err)
+	}
+
+	if seq > d.seq {
+		d.seq = seq
+		d.records++
+	} else {
+		// 已经在快照中
+		return nil
+	}
+
+	switch op {
+	case opPut:
+		val, _, err := decodeNext(data, d.valCodec)
+		if err != nil {
+			return fmt.Errorf("%w: 第%d条日志的value: %v", ErrCorrupted, seq, err)
+		}
+
+		d.tree.Insert(key, val)
+	case opDelete:
+		d.tree.deleteKey(key)
+	default:
+		return fmt.Errorf("%w: 第%d条日志的操作%d", ErrCorrupted, seq, op)
+	}
+
+	return nil
+}
+
+// truncate 将日志截断到offset，之后的日志从offset开始写入
+func (d *DurableTree[K, V]) truncate(offset int64) error {
+	if err := d.log.Truncate(offset); err != nil {
+		return err
+	}
+
+	if _, err := d.log.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	return d.log.Sync()
+}
+
+// loadSnapshot 读取快照，由按key升序排列的entry创建b+树，没有快照时为空树
+//
+// 快照：魔数(8字节)、最后一条日志的序号(8字节)、key的数量(8字节)、每个entry的key的长度(uvarint)、key、value的长度(uvarint)、value，
+// 最后为之前所有数据的crc32校验和(4字节)
+func (d *DurableTree[K, V]) loadSnapshot() error {
+	data, err := os.ReadFile(filepath.Join(d.dir, snapshotFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	n := len(data) - 4
+	if n < 24 || string(data[:8]) != snapshotMagic || crc32.ChecksumIEEE(data[:n]) != binary.LittleEndian.Uint32(data[n:]) {
+		return fmt.Errorf("%w: 快照的校验和错误", ErrCorrupted)
+	}
+
+	seq, count := binary.LittleEndian.Uint64(data[8:]), binary.LittleEndian.Uint64(data[16:])
+	body := data[24:n]
+
+	entries := func(yield func(K, V) bool) {
+		for i := uint64(0); i < count && err == nil; i++ {
+			var key K
+			var val V
+			if key, body, err = decodeNext(body, d.keyCodec); err != nil {
+				return
+			}
+
+			if val, body, err = decodeNext(body, d.valCodec); err != nil {
+				return
+			}
+
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+
+	tree, bSorted := BuildFromSorted(d.tree.minKeys, d.tree.comparator, entries, 1)
+	if err != nil || !bSorted || len(body) != 0 || uint64(tree.Len()) != count {
+		return fmt.Errorf("%w: 快照格式错误", ErrCorrupted)
+	}
+
+	d.tree, d.seq = tree, seq
+	return nil
+}
+
+// writeSnapshot 将整棵树写入临时文件，调用fsync之后重命名为快照文件
+func (d *DurableTree[K, V]) writeSnapshot() (err error) {
+	tmp := filepath.Join(d.dir, snapshotFile+".tmp")
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(tmp)
+		}
+	}()
+
+	h := crc32.NewIEEE()
+	w := bufio.NewWriter(io.MultiWriter(file, h))
+
+	buf := append(d.buf[:0], snapshotMagic...)
+	buf = binary.LittleEndian.AppendUint64(buf, d.seq)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(d.tree.Len()))
+	if _, err = w.Write(buf); err != nil {
+		return err
+	}
+
+	for key, val := range d.tree.All() {
+		if buf, err = appendEncoded(buf[:0], d.keyCodec, key); err != nil {
+			return err
+		}
+
+		if buf, err = appendEncoded(buf, d.valCodec, val); err != nil {
+			return err
+		}
+
+		if _, err = w.Write(buf); err != nil {
+			return err
+		}
+	}
+
+	d.buf = buf
+	if err = w.Flush(); err != nil {
+		return err
+	}
+
+	if _, err = file.Write(binary.LittleEndian.AppendUint32(nil, h.Sum32())); err != nil {
+		return err
+	}
+
+	if err = file.Sync(); err != nil {
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp, filepath.Join(d.dir, snapshotFile)); err != nil {
+		return err
+	}
+
+	return syncDir(d.dir)
+}
+
+// readRecord 读取一条日志记录，返回操作及之后的数据
+//
+// @return
+// 到达末尾时返回io.EOF，记录不完整时返回io.ErrUnexpectedEOF，长度或者校验和错误时返回errTornRecord
+func readRecord(r io.Reader) ([]byte, error) {
+	head := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, err
+	}
+
+	size := binary.LittleEndian.Uint32(head[4:])
+	if size > maxRecordSize {
+		return nil, errTornRecord
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	if crc32.Update(crc32.ChecksumIEEE(head[4:]), crc32.IEEETable, payload) != binary.LittleEndian.Uint32(head) {
+		return nil, errTornRecord
+	}
+
+	return payload, nil
+}
+
+// syncDir 对目录调用fsync，保证重命名已经写入磁盘
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	err = f.Sync()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
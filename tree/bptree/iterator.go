@@ -5,57 +5,60 @@ import (
 )
 
 // Iterator Iterator
-type Iterator struct {
-	tree     *Tree
-	leaf     *TreeLeaf
-	entry    *utils.Entry
-	entryPos int
+type Iterator[K, V any] struct {
+	cursor *Cursor[K, V]
+	entry  *utils.TypedEntry[K, V]
+	bBegin bool // 第一次调用Next时，返回起始位置的entry
 }
 
 // NewIterator NewIterator
-func NewIterator(tree *Tree) *Iterator {
-	return NewIteratorWithLeaf(tree, tree.minimum(), 0)
+func NewIterator[K, V any](tree *Tree[K, V]) *Iterator[K, V] {
+	cursor := NewCursor(tree)
+	cursor.First()
+
+	return newIterator(cursor)
 }
 
-// NewIteratorWithLeaf NewIteratorWithLeaf
-func NewIteratorWithLeaf(tree *Tree, leaf *TreeLeaf, pos int) *Iterator {
-	iter := &Iterator{}
-	iter.tree = tree
-	iter.leaf = leaf
-	iter.entry = nil
-	iter.entryPos = pos
+// NewIteratorLowerBoundKey 从大于等于key的位置开始迭代
+func NewIteratorLowerBoundKey[K, V any](tree *Tree[K, V], key K) *Iterator[K, V] {
+	cursor := NewCursor(tree)
+	cursor.Seek(key)
+
+	return newIterator(cursor)
+}
+
+// newIterator 从游标指向的entry开始迭代，游标无效时没有起始位置
+func newIterator[K, V any](cursor *Cursor[K, V]) *Iterator[K, V] {
+	iter := &Iterator[K, V]{}
+	iter.cursor = cursor
+	iter.bBegin = true
 
 	return iter
 }
 
 // Next Next
-func (iter *Iterator) Next() bool {
-	if iter.leaf == nil {
-		return false
-	}
-
-	if iter.entryPos < len(iter.leaf.entries) {
-		iter.entry = iter.leaf.entries[iter.entryPos]
-		iter.entryPos++
-		return true
+func (iter *Iterator[K, V]) Next() bool {
+	if iter.bBegin {
+		iter.bBegin = false
+	} else {
+		iter.cursor.Next()
 	}
 
-	iter.leaf = iter.leaf.next
-	if iter.leaf != nil {
-		iter.entry = iter.leaf.entries[0]
-		iter.entryPos = 1
-		return true
+	iter.entry = nil
+	if !iter.cursor.Valid() {
+		return false
 	}
 
-	return false
+	iter.entry = iter.cursor.entry()
+	return true
 }
 
 // GetKey GetKey
-func (iter *Iterator) GetKey() interface{} {
+func (iter *Iterator[K, V]) GetKey() K {
 	return iter.entry.GetKey()
 }
 
 // GetValue GetValue
-func (iter *Iterator) GetValue() interface{} {
+func (iter *Iterator[K, V]) GetValue() V {
 	return iter.entry.GetValue()
 }
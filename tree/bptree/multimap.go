@@ -0,0 +1,115 @@
+package bptree
+
+import (
+	"iter"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// GetAll 按插入顺序返回key对应的所有value，key不存在时返回nil
+func (t *Tree[K, V]) GetAll(key K) []V {
+	var vals []V
+	for entry := range t.equalEntries(key) {
+		vals = append(vals, entry.GetValue())
+	}
+
+	return vals
+}
+
+// Count key对应的value的个数
+func (t *Tree[K, V]) Count(key K) int {
+	var count int
+	for range t.equalEntries(key) {
+		count++
+	}
+
+	return count
+}
+
+// DeleteOne 删除key最早插入的一个entry
+//
+// @return
+// 是否找到key
+func (t *Tree[K, V]) DeleteOne(key K) bool {
+	return t.deleteKey(key)
+}
+
+// DeleteAll 删除key对应的所有entry，返回删除的entry数
+func (t *Tree[K, V]) DeleteAll(key K) int {
+	var count int
+	for t.DeleteOne(key) {
+		count++
+	}
+
+	return count
+}
+
+// equalEntries 按插入顺序遍历key对应的entry
+func (t *Tree[K, V]) equalEntries(key K) iter.Seq[*utils.TypedEntry[K, V]] {
+	return func(yield func(*utils.TypedEntry[K, V]) bool) {
+		iter := NewIteratorLowerBoundKey(t, key)
+		for iter.Next() && t.comparator(iter.GetKey(), key) == 0 {
+			if !yield(iter.entry) {
+				return
+			}
+		}
+	}
+}
+
+// deleteEntry 删除叶子节点中pos位置的entry，叶子节点和祖先节点都是可以修改的
+//
+// 相同的key可能分布在多个叶子节点中，父节点中也可能有多个相同的key，
+// 修复时按子节点在父节点中的位置更新父节点的key，不按key查找
+func (t *Tree[K, V]) deleteEntry(leaf *TreeLeaf[K, V], pos int) {
+	leaf.entries = append(leaf.entries[:pos], leaf.entries[pos+1:]...)
+	t.size--
+
+	var node iNode[K, V] = leaf
+	for {
+		// 删除的可能是节点的第一个key，更新父节点中的key
+		t.updateFirstKey(node)
+
+		if node.getParent() == nil {
+			// 修复根节点
+			t.dCaseRoot(node)
+			return
+		}
+
+		if node.getKeys() >= t.minKeys {
+			return
+		}
+
+		adj := node.adjacent(t)
+		if adj.getKeys() > t.minKeys {
+			// 相邻节点的key的数量大于t
+			node.moveKey(t, adj)
+			t.updateFirstKey(node)
+			return
+		}
+
+		parent := node.merge(t, adj).(*TreeNode[K, V])
+		for i, children := range parent.childrens {
+			parent.keys[i] = children.getPosKey(0)
+		}
+
+		node = parent
+	}
+}
+
+// updateFirstKey 用node的第一个key更新父节点中node对应的key，node是第一个子节点时继续向上更新
+func (t *Tree[K, V]) updateFirstKey(node iNode[K, V]) {
+	if node.getKeys() == 0 {
+		return
+	}
+
+	key := node.getPosKey(0)
+	for parent := node.getParent(); parent != nil; parent = parent.getParent() {
+		pos := parent.childrenPosition(node)
+		parent.keys[pos] = key
+		if pos != 0 {
+			return
+		}
+
+		node = parent
+	}
+}
@@ -0,0 +1,103 @@
+package bptree
+
+// Option 创建b+树的选项
+type Option func(o *options)
+
+// options b+树的选项
+type options struct {
+	multi bool // 是否允许重复的key
+}
+
+// newOptions 默认选项：key不能重复
+func newOptions(opts []Option) *options {
+	o := &options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithMultimap 使用multimap模式，Insert总是插入新的entry，相同key的entry按插入顺序排列
+func WithMultimap() Option {
+	return func(o *options) {
+		o.multi = true
+	}
+}
+
+// PageOption 打开分页存储的b+树的选项，见Open
+type PageOption func(o *pageOptions)
+
+// pageOptions 分页存储的选项
+type pageOptions struct {
+	pageSize  int // 页大小，只在创建文件时使用
+	cacheSize int // 缓冲池最多缓存的节点数
+}
+
+// newPageOptions 默认选项：页大小4096字节，缓冲池缓存1024个节点
+func newPageOptions(opts []PageOption) *pageOptions {
+	o := &pageOptions{pageSize: 4096, cacheSize: 1024}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithPageSize 页大小，只在创建文件时使用，已有的文件使用文件中的页大小
+func WithPageSize(size int) PageOption {
+	return func(o *pageOptions) {
+		o.pageSize = size
+	}
+}
+
+// WithCacheSize 缓冲池最多缓存的节点数，至少为1
+func WithCacheSize(pages int) PageOption {
+	return func(o *pageOptions) {
+		o.cacheSize = max(pages, 1)
+	}
+}
+
+// DurableOption 打开持久化的b+树的选项，见OpenDurable
+type DurableOption func(o *durableOptions)
+
+// durableOptions 持久化的选项
+type durableOptions struct {
+	syncPolicy         SyncPolicy // 写入日志之后调用fsync的策略
+	syncBatch          int        // SyncBatch策略下每多少条日志调用一次fsync
+	checkpointInterval int        // 日志达到多少条时写快照
+}
+
+// newDurableOptions 默认选项：每条日志都调用fsync，日志达到10000条时写快照
+func newDurableOptions(opts []DurableOption) *durableOptions {
+	o := &durableOptions{syncPolicy: SyncAlways, syncBatch: 64, checkpointInterval: 10000}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithSyncPolicy 写入日志之后调用fsync的策略
+func WithSyncPolicy(policy SyncPolicy) DurableOption {
+	return func(o *durableOptions) {
+		o.syncPolicy = policy
+	}
+}
+
+// WithSyncBatch SyncBatch策略下每n条日志调用一次fsync，至少为1
+func WithSyncBatch(n int) DurableOption {
+	return func(o *durableOptions) {
+		o.syncBatch = max(n, 1)
+	}
+}
+
+// WithCheckpointInterval 日志达到n条时写快照并截断日志，小于等于0时只在调用Checkpoint时写快照
+func WithCheckpointInterval(n int) DurableOption {
+	return func(o *durableOptions) {
+		o.checkpointInterval = n
+	}
+}
@@ -0,0 +1,261 @@
+package bptree
+
+import (
+	"iter"
+	"os"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// PagedTree 分页存储的b+树，每个节点保存在文件中的一个固定大小的页里，通过LRU缓冲池按需加载
+//
+// 插入、删除直接使用Tree的分裂、合并、移动key的逻辑，每次操作之后才为新节点分配页、释放删除的节点的页、淘汰多余的节点；
+// 修改过的节点在淘汰或者Sync时写回文件，两次Sync之间崩溃时文件可能不一致
+//
+// 不是并发安全的；读写文件失败或者页损坏之后不能继续使用，之后的操作都返回这个错误
+type PagedTree[K, V any] struct {
+	tree  *Tree[K, V]
+	pager *pager[K, V]
+	err   error // 第一次读写失败的错误，关闭之后为ErrClosed
+}
+
+// Open 打开分页存储的b+树，文件不存在或者为空时创建一个空树
+//
+// @param
+// path: 文件路径
+// t: 最小度数，必须和创建文件时的一致
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// keyCodec: key的编解码器
+// valCodec: value的编解码器
+// opts: 页大小、缓冲池大小等选项
+func Open[K, V any](path string, t int, comparator func(a, b K) int, keyCodec Codec[K], valCodec Codec[V], opts ...PageOption) (*PagedTree[K, V], error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := NewTreeFunc[K, V](t, comparator)
+	tree.pager = newPager(tree, file, newPageOptions(opts), keyCodec, valCodec)
+	if err := tree.pager.open(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &PagedTree[K, V]{tree: tree, pager: tree.pager}, nil
+}
+
+// Put 插入key、value，key已存在时更新value
+//
+// @return
+// key、value编码之后太大时返回ErrEntryTooLarge，不影响之后的操作
+func (t *PagedTree[K, V]) Put(key K, val V) error {
+	if t.err != nil {
+		return t.err
+	}
+
+	if err := t.pager.checkEntry(key, val); err != nil {
+		return err
+	}
+
+	return t.do(func() {
+		t.tree.Insert(key, val)
+	})
+}
+
+// Get 查找key对应的value
+func (t *PagedTree[K, V]) Get(key K) (val V, bFound bool, err error) {
+	err = t.do(func() {
+		val, bFound = t.tree.Get(key)
+	})
+
+	return val, bFound, err
+}
+
+// Delete 删除key
+//
+// @return
+// 是否找到key
+func (t *PagedTree[K, V]) Delete(key K) (bFound bool, err error) {
+	err = t.do(func() {
+		bFound = t.tree.deleteKey(key)
+	})
+
+	return bFound, err
+}
+
+// Len 节点数
+func (t *PagedTree[K, V]) Len() int {
+	return t.tree.Len()
+}
+
+// Min 最小的key和对应的value
+func (t *PagedTree[K, V]) Min() (key K, val V, bFound bool, err error) {
+	err = t.do(func() {
+		key, val, bFound = t.tree.Min()
+	})
+
+	return key, val, bFound, err
+}
+
+// Max 最大的key和对应的value
+func (t *PagedTree[K, V]) Max() (key K, val V, bFound bool, err error) {
+	err = t.do(func() {
+		key, val, bFound = t.tree.Max()
+	})
+
+	return key, val, bFound, err
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的Entry
+//
+// 每次加载一个叶子节点中的entry，遍历过程中可以修改树；读取失败时停止遍历，错误通过Err获取
+func (t *PagedTree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for key, val := range t.scan(lo, true, false) {
+			if t.tree.comparator(key, hi) > 0 || !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+// All 按key升序遍历所有Entry，见Range
+func (t *PagedTree[K, V]) All() iter.Seq2[K, V] {
+	var key K
+	return t.scan(key, false, false)
+}
+
+// Backward 按key降序遍历所有Entry，见Range
+func (t *PagedTree[K, V]) Backward() iter.Seq2[K, V] {
+	var key K
+	return t.scan(key, false, true)
+}
+
+// scan 每次在do中读取游标所在叶子节点中剩下的entry，yield在do之外调用，下一批从上一批的最后一个key之后重新定位
+//
+// @param
+// from: 起始key，bFrom为false时从第一个(或最后一个)entry开始
+// bDesc: 是否按key降序遍历
+func (t *PagedTree[K, V]) scan(from K, bFrom bool, bDesc bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var batch []*utils.TypedEntry[K, V]
+		var last K
+		bStarted := false
+
+		for {
+			batch = batch[:0]
+			err := t.do(func() {
+				c := NewCursor(t.tree)
+				var ok bool
+				switch {
+				case bStarted && bDesc:
+					if ok = c.SeekLE(last); ok && t.tree.comparator(c.GetKey(), last) == 0 {
+						ok = c.Prev()
+					}
+				case bStarted:
+					if ok = c.Seek(last); ok && t.tree.comparator(c.GetKey(), last) == 0 {
+						ok = c.Next()
+					}
+				case bFrom:
+					ok = c.Seek(from)
+				case bDesc:
+					ok = c.Last()
+				default:
+					ok = c.First()
+				}
+
+				step := 1
+				if bDesc {
+					step = -1
+				}
+
+				for leaf := c.leaf; ok && c.leaf == leaf; ok = c.moveTo(c.pos + step) {
+					batch = append(batch, c.entry())
+				}
+			})
+
+			if err != nil || len(batch) == 0 {
+				return
+			}
+
+			for _, entry := range batch {
+				if !yield(entry.GetKey(), entry.GetValue()) {
+					return
+				}
+			}
+
+			last, bStarted = batch[len(batch)-1].GetKey(), true
+		}
+	}
+}
+
+// Err 遍历或者其他操作中第一次读写失败的错误
+func (t *PagedTree[K, V]) Err() error {
+	if t.err == ErrClosed {
+		return nil
+	}
+
+	return t.err
+}
+
+// Sync 将修改过的节点和元数据写回文件，并调用fsync
+func (t *PagedTree[K, V]) Sync() error {
+	if t.err != nil {
+		return t.err
+	}
+
+	if err := t.pager.flush(); err != nil {
+		t.err = err
+	}
+
+	return t.err
+}
+
+// Close 调用Sync之后关闭文件，关闭之后的操作都返回ErrClosed
+func (t *PagedTree[K, V]) Close() error {
+	if t.err == ErrClosed {
+		return ErrClosed
+	}
+
+	err := t.Sync()
+	if cerr := t.pager.file.Close(); err == nil {
+		err = cerr
+	}
+
+	t.err = ErrClosed
+	return err
+}
+
+// Verify 验证b+树的性质和页的分配，会加载所有的节点
+func (t *PagedTree[K, V]) Verify() (bOK bool) {
+	err := t.do(func() {
+		bOK = t.tree.Verify() && t.pager.verify()
+	})
+
+	return err == nil && bOK
+}
+
+// do 执行一次操作，恢复加载页失败时的panic，操作成功之后整理缓冲池
+func (t *PagedTree[K, V]) do(fn func()) (err error) {
+	if t.err != nil {
+		return t.err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			pe, ok := r.(*pageError)
+			if !ok {
+				panic(r)
+			}
+
+			err = pe.err
+		} else {
+			err = t.pager.settle()
+		}
+
+		t.err = err
+	}()
+
+	fn()
+	return nil
+}
@@ -0,0 +1,691 @@
+package bptree
+
+import (
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// 页的类型
+const (
+	pageMeta byte = iota + 1 // 元数据页，文件的第0页
+	pageLeaf                 // 叶子节点
+	pageNode                 // 内节点
+	pageFree                 // 空闲页
+)
+
+const (
+	pageHeaderSize = 7          // 页头：类型(1字节)、crc32校验和(4字节)、key的数量(2字节)
+	metaSize       = 55         // 元数据页实际使用的字节数
+	metaMagic      = "GODSBPT1" // 元数据页的魔数
+	minEntrySize   = 16         // 每个key至少可以使用的字节数
+)
+
+var (
+	// ErrCorrupted 页已损坏
+	ErrCorrupted = errors.New("bptree: 页已损坏")
+	// ErrEntryTooLarge key、value编码之后太大，节点放不进一页
+	ErrEntryTooLarge = errors.New("bptree: key、value太大")
+	// ErrPageSize 页太小，放不下2t个key
+	ErrPageSize = errors.New("bptree: 页太小")
+	// ErrDegreeMismatch 最小度数和文件中的不一致
+	ErrDegreeMismatch = errors.New("bptree: 最小度数和文件中的不一致")
+	// ErrClosed 已经关闭
+	ErrClosed = errors.New("bptree: 已经关闭")
+)
+
+// pageError 加载页失败，在pager.child中panic，由PagedTree恢复并返回错误
+type pageError struct {
+	err error
+}
+
+// pageRef 不在缓冲池中的子节点，只记录页号，访问时通过Tree.child加载
+type pageRef[K, V any] struct {
+	iNode[K, V]        // 为nil，除了getPosKey、getGen之外的方法都不能调用
+	id          uint64 // 页号
+	key         K      // 子节点的第一个key，和父节点中对应的key相同
+}
+
+// getPosKey 子节点的第一个key，合并之后更新父节点的key时使用，只能获取第一个key
+func (ref *pageRef[K, V]) getPosKey(pos int) K {
+	return ref.key
+}
+
+// getGen 不属于任何树，移动到其他节点时不设置父节点
+func (ref *pageRef[K, V]) getGen() uint64 {
+	return math.MaxUint64
+}
+
+// frame 缓冲池中的节点
+type frame[K, V any] struct {
+	node  iNode[K, V]
+	id    uint64        // 页号
+	dirty bool          // 是否修改过，淘汰或者Sync时写回文件
+	elem  *list.Element // 在lru中的位置
+}
+
+// pager 缓冲池，按LRU缓存节点，不在缓冲池中的子节点为pageRef，访问时从文件中加载
+//
+// 只在两次操作之间淘汰节点，一次操作中用到的节点都在内存中，所以插入、删除可以直接使用Tree的分裂、合并、移动key的逻辑
+type pager[K, V any] struct {
+	tree      *Tree[K, V]
+	file      *os.File
+	pageSize  int
+	cacheSize int // 缓冲池最多缓存的节点数，一次操作中可以暂时超过
+	keyCodec  Codec[K]
+	valCodec  Codec[V]
+	frames    map[iNode[K, V]]*frame[K, V]
+	lru       *list.List    // 缓冲池中的节点，最近使用的在前面
+	touched   []iNode[K, V] // 上次settle之后修改过的节点
+	free      []uint64      // 空闲页，最后一个为空闲链表的头
+	pageCount uint64        // 文件中的页数
+	buf       []byte        // 读写页的缓冲区
+}
+
+// newPager 创建缓冲池
+func newPager[K, V any](tree *Tree[K, V], file *os.File, o *pageOptions, keyCodec Codec[K], valCodec Codec[V]) *pager[K, V] {
+	return &pager[K, V]{
+		tree:      tree,
+		file:      file,
+		pageSize:  o.pageSize,
+		cacheSize: o.cacheSize,
+		keyCodec:  keyCodec,
+		valCodec:  valCodec,
+		frames:    make(map[iNode[K, V]]*frame[K, V]),
+		lru:       list.New(),
+	}
+}
+
+// open 读取元数据和根节点，文件为空时创建一个空树
+func (p *pager[K, V]) open() error {
+	info, err := p.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() == 0 {
+		if err := p.checkPageSize(); err != nil {
+			return err
+		}
+
+		// 第0页为元数据页，第1页为空的根节点
+		p.buf = make([]byte, p.pageSize)
+		p.pageCount = 1
+		id, _ := p.alloc()
+		p.register(p.tree.root, id, true)
+		return p.flush()
+	}
+
+	root, free, err := p.readMeta()
+	if err != nil {
+		return err
+	}
+
+	// 空闲链表，从链表头开始读取，最后一个为链表头
+	for id := free; id != 0; {
+		buf, err := p.readPage(id)
+		if err != nil {
+			return err
+		}
+
+		if buf[0] != pageFree || uint64(len(p.free)) >= p.pageCount {
+			return fmt.Errorf("%w: 第%d页不是空闲页", ErrCorrupted, id)
+		}
+
+		p.free = append(p.free, id)
+		id = binary.LittleEndian.Uint64(buf[pageHeaderSize:])
+	}
+
+	for i, j := 0, len(p.free)-1; i < j; i, j = i+1, j-1 {
+		p.free[i], p.free[j] = p.free[j], p.free[i]
+	}
+
+	node, err := p.read(root)
+	if err != nil {
+		return err
+	}
+
+	p.tree.root = node
+	p.register(node, root, false)
+	return nil
+}
+
+// checkPageSize 页至少要能放下元数据和2t个key
+func (p *pager[K, V]) checkPageSize() error {
+	if p.pageSize < metaSize || p.tree.maxKeys > math.MaxUint16 || p.entryLimit() < minEntrySize {
+		return fmt.Errorf("%w: 页大小%d，最小度数%d", ErrPageSize, p.pageSize, p.tree.minKeys)
+	}
+
+	return nil
+}
+
+// entryLimit 每个key、value编码之后最多可以使用的字节数，节点中有2t个key时也能放进一页
+func (p *pager[K, V]) entryLimit() int {
+	return (p.pageSize - pageHeaderSize) / p.tree.maxKeys
+}
+
+// checkEntry 检查key、value编码之后能不能放进节点中，内节点中每个key还要保存8字节的页号
+func (p *pager[K, V]) checkEntry(key K, val V) error {
+	kb, err := p.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+
+	vb, err := p.valCodec.Encode(val)
+	if err != nil {
+		return err
+	}
+
+	size := uvarintLen(len(kb)) + len(kb) + max(uvarintLen(len(vb))+len(vb), 8)
+	if size > p.entryLimit() {
+		return fmt.Errorf("%w: 编码之后%d字节，最多%d字节", ErrEntryTooLarge, size, p.entryLimit())
+	}
+
+	return nil
+}
+
+// child 获取node中pos位置的子节点，子节点不在缓冲池中时从文件中加载，加载失败时panic(*pageError)
+func (p *pager[K, V]) child(node *TreeNode[K, V], pos int) iNode[K, V] {
+	children := node.childrens[pos]
+	ref, ok := children.(*pageRef[K, V])
+	if !ok {
+		p.use(children)
+		return children
+	}
+
+	children, err := p.read(ref.id)
+	if err != nil {
+		panic(&pageError{err})
+	}
+
+	children.setParent(node)
+	node.childrens[pos] = children
+	p.register(children, ref.id, false)
+	return children
+}
+
+// use 将节点移到lru的最前面
+func (p *pager[K, V]) use(node iNode[K, V]) {
+	if f := p.frames[node]; f != nil {
+		p.lru.MoveToFront(f.elem)
+	}
+}
+
+// dirty 节点将被修改，分裂产生的新节点还不在缓冲池中，在settle中加入
+func (p *pager[K, V]) dirty(node iNode[K, V]) {
+	p.touched = append(p.touched, node)
+	if f := p.frames[node]; f != nil {
+		f.dirty = true
+		p.lru.MoveToFront(f.elem)
+	}
+}
+
+// register 将节点加入缓冲池
+func (p *pager[K, V]) register(node iNode[K, V], id uint64, dirty bool) {
+	f := &frame[K, V]{node: node, id: id, dirty: dirty}
+	f.elem = p.lru.PushFront(f)
+	p.frames[node] = f
+}
+
+// settle 每次操作之后整理缓冲池：释放合并之后删除的节点的页，为分裂产生的节点分配页，再淘汰多余的节点
+func (p *pager[K, V]) settle() error {
+	root := p.tree.root
+	pending := []iNode[K, V]{root}
+
+	for _, node := range p.touched {
+		if node != root && node.getParent() == nil {
+			// 合并之后删除的节点，或者删除修复之后被替换的根节点
+			if err := p.release(node); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		pending = append(pending, node)
+	}
+
+	p.touched = p.touched[:0]
+
+	// 分裂产生的节点和新的根节点还不在缓冲池中，它们的父节点都修改过
+	for len(pending) != 0 {
+		node := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if p.frames[node] == nil {
+			id, err := p.alloc()
+			if err != nil {
+				return err
+			}
+
+			p.register(node, id, true)
+		}
+
+		if n, ok := node.(*TreeNode[K, V]); ok {
+			for _, children := range n.childrens {
+				if _, ok := children.(*pageRef[K, V]); !ok && p.frames[children] == nil {
+					pending = append(pending, children)
+				}
+			}
+		}
+	}
+
+	return p.trim()
+}
+
+// trim 从最久没有使用的节点开始淘汰，直到缓冲池中的节点数不超过cacheSize
+//
+// 根节点和有子节点在缓冲池中的节点不能淘汰，子节点淘汰之后父节点才能淘汰
+func (p *pager[K, V]) trim() error {
+	for len(p.frames) > p.cacheSize {
+		bEvicted := false
+		for e := p.lru.Back(); e != nil && len(p.frames) > p.cacheSize; {
+			prev := e.Prev()
+			if f := e.Value.(*frame[K, V]); p.evictable(f.node) {
+				if err := p.evict(f); err != nil {
+					return err
+				}
+
+				bEvicted = true
+			}
+
+			e = prev
+		}
+
+		if !bEvicted {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// evictable 节点是否可以淘汰
+func (p *pager[K, V]) evictable(node iNode[K, V]) bool {
+	if node == p.tree.root {
+		return false
+	}
+
+	if n, ok := node.(*TreeNode[K, V]); ok {
+		for _, children := range n.childrens {
+			if _, ok := children.(*pageRef[K, V]); !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// evict 淘汰节点，修改过的节点先写回文件，父节点中的节点替换为pageRef
+func (p *pager[K, V]) evict(f *frame[K, V]) error {
+	if f.dirty {
+		if err := p.write(f); err != nil {
+			return err
+		}
+	}
+
+	parent := f.node.getParent()
+	parent.childrens[parent.childrenPosition(f.node)] = &pageRef[K, V]{id: f.id, key: f.node.getPosKey(0)}
+	p.lru.Remove(f.elem)
+	delete(p.frames, f.node)
+
+	return nil
+}
+
+// release 释放节点的页，加入到空闲链表中
+func (p *pager[K, V]) release(node iNode[K, V]) error {
+	f := p.frames[node]
+	if f == nil {
+		return nil
+	}
+
+	p.lru.Remove(f.elem)
+	delete(p.frames, node)
+
+	buf := p.buf[:p.pageSize]
+	clear(buf)
+	buf[0] = pageFree
+	binary.LittleEndian.PutUint64(buf[pageHeaderSize:], p.freeHead())
+	if err := p.writePage(f.id, buf); err != nil {
+		return err
+	}
+
+	p.free = append(p.free, f.id)
+	return nil
+}
+
+// alloc 分配一页，优先使用空闲链表中的页
+func (p *pager[K, V]) alloc() (uint64, error) {
+	if n := len(p.free); n != 0 {
+		id := p.free[n-1]
+		p.free = p.free[:n-1]
+		return id, nil
+	}
+
+	if p.pageCount == math.MaxUint64 {
+		return 0, fmt.Errorf("%w: 页数超过上限", ErrCorrupted)
+	}
+
+	p.pageCount++
+	return p.pageCount - 1, nil
+}
+
+// freeHead 空闲链表的头，没有空闲页时为0
+func (p *pager[K, V]) freeHead() uint64 {
+	if len(p.free) == 0 {
+		return 0
+	}
+
+	return p.free[len(p.free)-1]
+}
+
+// flush 将修改过的节点和元数据写回文件
+func (p *pager[K, V]) flush() error {
+	for e := p.lru.Front(); e != nil; e = e.Next() {
+		if f := e.Value.(*frame[K, V]); f.dirty {
+			if err := p.write(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := p.writeMeta(); err != nil {
+		return err
+	}
+
+	return p.file.Sync()
+}
+
+// write 将节点写回文件
+func (p *pager[K, V]) write(f *frame[K, V]) error {
+	buf, err := p.encode(f.node)
+	if err != nil {
+		return err
+	}
+
+	if err := p.writePage(f.id, buf); err != nil {
+		return err
+	}
+
+	f.dirty = false
+	return nil
+}
+
+// encode 将节点编码为一页
+//
+// 叶子节点：每个entry为key的长度(uvarint)、key、value的长度(uvarint)、value
+// 内节点：每个子节点为key的长度(uvarint)、key、子节点的页号(8字节)
+func (p *pager[K, V]) encode(node iNode[K, V]) ([]byte, error) {
+	buf := p.buf[:pageHeaderSize]
+	clear(buf)
+
+	var err error
+	switch n := node.(type) {
+	case *TreeLeaf[K, V]:
+		buf[0] = pageLeaf
+		binary.LittleEndian.PutUint16(buf[5:], uint16(len(n.entries)))
+		for _, entry := range n.entries {
+			if buf, err = appendEncoded(buf, p.keyCodec, entry.GetKey()); err != nil {
+				return nil, err
+			}
+
+			if buf, err = appendEncoded(buf, p.valCodec, entry.GetValue()); err != nil {
+				return nil, err
+			}
+		}
+	case *TreeNode[K, V]:
+		buf[0] = pageNode
+		binary.LittleEndian.PutUint16(buf[5:], uint16(len(n.keys)))
+		for i, key := range n.keys {
+			if buf, err = appendEncoded(buf, p.keyCodec, key); err != nil {
+				return nil, err
+			}
+
+			buf = binary.LittleEndian.AppendUint64(buf, p.pageID(n.childrens[i]))
+		}
+	}
+
+	if len(buf) > p.pageSize {
+		return nil, fmt.Errorf("%w: 节点编码之后%d字节，页大小%d字节", ErrEntryTooLarge, len(buf), p.pageSize)
+	}
+
+	n := len(buf)
+	buf = buf[:p.pageSize]
+	clear(buf[n:])
+
+	return buf, nil
+}
+
+// pageID 子节点的页号
+func (p *pager[K, V]) pageID(children iNode[K, V]) uint64 {
+	if ref, ok := children.(*pageRef[K, V]); ok {
+		return ref.id
+	}
+
+	return p.frames[children].id
+}
+
+// read 从文件中读取节点，子节点都为pageRef
+func (p *pager[K, V]) read(id uint64) (iNode[K, V], error) {
+	buf, err := p.readPage(id)
+	if err != nil {
+		return nil, err
+	}
+
+	count := int(binary.LittleEndian.Uint16(buf[5:]))
+	data := buf[pageHeaderSize:]
+
+	switch buf[0] {
+	case pageLeaf:
+		leaf := NewTreeLeaf[K, V]()
+		leaf.gen = p.tree.gen
+		leaf.entries = make([]*utils.TypedEntry[K, V], 0, count)
+		for i := 0; i < count; i++ {
+			var key K
+			var val V
+			if key, data, err = decodeNext(data, p.keyCodec); err != nil {
+				return nil, fmt.Errorf("第%d页: %w", id, err)
+			}
+
+			if val, data, err = decodeNext(data, p.valCodec); err != nil {
+				return nil, fmt.Errorf("第%d页: %w", id, err)
+			}
+
+			leaf.entries = append(leaf.entries, utils.NewTypedEntry(key, val))
+		}
+
+		return leaf, nil
+	case pageNode:
+		node := NewTreeNode[K, V]()
+		node.gen = p.tree.gen
+		node.keys = make([]K, 0, count)
+		node.childrens = make([]iNode[K, V], 0, count)
+		for i := 0; i < count; i++ {
+			var key K
+			if key, data, err = decodeNext(data, p.keyCodec); err != nil || len(data) < 8 {
+				return nil, fmt.Errorf("%w: 第%d页的内节点格式错误", ErrCorrupted, id)
+			}
+
+			node.keys = append(node.keys, key)
+			node.childrens = append(node.childrens, &pageRef[K, V]{id: binary.LittleEndian.Uint64(data), key: key})
+			data = data[8:]
+		}
+
+		return node, nil
+	}
+
+	return nil, fmt.Errorf("%w: 第%d页不是节点", ErrCorrupted, id)
+}
+
+// readPage 读取一页并验证校验和，返回的buf在下一次读写之前有效
+func (p *pager[K, V]) readPage(id uint64) ([]byte, error) {
+	if id == 0 || id >= p.pageCount {
+		return nil, fmt.Errorf("%w: 页号%d超出范围", ErrCorrupted, id)
+	}
+
+	buf := p.buf[:p.pageSize]
+	if _, err := p.file.ReadAt(buf, int64(id)*int64(p.pageSize)); err != nil {
+		return nil, fmt.Errorf("第%d页: %w", id, err)
+	}
+
+	if crc32.ChecksumIEEE(buf[5:]) != binary.LittleEndian.Uint32(buf[1:]) {
+		return nil, fmt.Errorf("%w: 第%d页的校验和错误", ErrCorrupted, id)
+	}
+
+	return buf, nil
+}
+
+// writePage 计算校验和并写入一页
+func (p *pager[K, V]) writePage(id uint64, buf []byte) error {
+	binary.LittleEndian.PutUint32(buf[1:], crc32.ChecksumIEEE(buf[5:]))
+	_, err := p.file.WriteAt(buf, int64(id)*int64(p.pageSize))
+
+	return err
+}
+
+// readMeta 读取元数据页
+//
+// 元数据页在页头之后依次为：魔数(8字节)、页大小(4字节)、最小度数(4字节)、根节点的页号、空闲链表的头、页数、key的数量(各8字节)
+//
+// @return
+// root: 根节点的页号
+// free: 空闲链表的头
+func (p *pager[K, V]) readMeta() (root uint64, free uint64, err error) {
+	head := make([]byte, metaSize)
+	if _, err := p.file.ReadAt(head, 0); err != nil {
+		return 0, 0, fmt.Errorf("%w: 读取元数据页失败: %v", ErrCorrupted, err)
+	}
+
+	if head[0] != pageMeta || string(head[7:15]) != metaMagic {
+		return 0, 0, fmt.Errorf("%w: 不是b+树文件", ErrCorrupted)
+	}
+
+	p.pageSize = int(binary.LittleEndian.Uint32(head[15:]))
+	if err := p.checkPageSize(); err != nil {
+		return 0, 0, err
+	}
+
+	p.buf = make([]byte, p.pageSize)
+	if _, err := p.file.ReadAt(p.buf, 0); err != nil {
+		return 0, 0, fmt.Errorf("%w: 读取元数据页失败: %v", ErrCorrupted, err)
+	}
+
+	if crc32.ChecksumIEEE(p.buf[5:]) != binary.LittleEndian.Uint32(p.buf[1:]) {
+		return 0, 0, fmt.Errorf("%w: 元数据页的校验和错误", ErrCorrupted)
+	}
+
+	if degree := int(binary.LittleEndian.Uint32(p.buf[19:])); degree != p.tree.minKeys {
+		return 0, 0, fmt.Errorf("%w: 文件中为%d，打开时为%d", ErrDegreeMismatch, degree, p.tree.minKeys)
+	}
+
+	root = binary.LittleEndian.Uint64(p.buf[23:])
+	free = binary.LittleEndian.Uint64(p.buf[31:])
+	p.pageCount = binary.LittleEndian.Uint64(p.buf[39:])
+	p.tree.size = int(binary.LittleEndian.Uint64(p.buf[47:]))
+
+	return root, free, nil
+}
+
+// writeMeta 写入元数据页
+func (p *pager[K, V]) writeMeta() error {
+	buf := p.buf[:p.pageSize]
+	clear(buf)
+	buf[0] = pageMeta
+	copy(buf[7:], metaMagic)
+	binary.LittleEndian.PutUint32(buf[15:], uint32(p.pageSize))
+	binary.LittleEndian.PutUint32(buf[19:], uint32(p.tree.minKeys))
+	binary.LittleEndian.PutUint64(buf[23:], p.frames[p.tree.root].id)
+	binary.LittleEndian.PutUint64(buf[31:], p.freeHead())
+	binary.LittleEndian.PutUint64(buf[39:], p.pageCount)
+	binary.LittleEndian.PutUint64(buf[47:], uint64(p.tree.size))
+
+	return p.writePage(0, buf)
+}
+
+// verify 验证每一页只被一个节点使用或者在空闲链表中，缓冲池中的节点都在树中，需要先加载所有的节点
+func (p *pager[K, V]) verify() bool {
+	used := map[uint64]bool{0: true}
+	nodes := 0
+	stack := []iNode[K, V]{p.tree.root}
+
+	for len(stack) != 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		f := p.frames[node]
+		if f == nil {
+			fmt.Printf("节点不在缓冲池中\n")
+			return false
+		}
+
+		if used[f.id] {
+			fmt.Printf("第%d页被多个节点使用\n", f.id)
+			return false
+		}
+
+		used[f.id] = true
+		nodes++
+
+		if n, ok := node.(*TreeNode[K, V]); ok {
+			stack = append(stack, n.childrens...)
+		}
+	}
+
+	for _, id := range p.free {
+		if used[id] {
+			fmt.Printf("空闲链表中的第%d页被使用\n", id)
+			return false
+		}
+
+		used[id] = true
+	}
+
+	if uint64(len(used)) != p.pageCount {
+		fmt.Printf("有%d页既没有被使用，也不在空闲链表中\n", p.pageCount-uint64(len(used)))
+		return false
+	}
+
+	if nodes != len(p.frames) {
+		fmt.Printf("缓冲池中有%d个节点不在树中\n", len(p.frames)-nodes)
+		return false
+	}
+
+	return true
+}
+
+// appendEncoded 编码v，追加长度和编码之后的数据
+func appendEncoded[T any](buf []byte, codec Codec[T], v T) ([]byte, error) {
+	data, err := codec.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	return append(buf, data...), nil
+}
+
+// decodeNext 解码data中的第一个数据，返回剩下的data
+func decodeNext[T any](data []byte, codec Codec[T]) (v T, rest []byte, err error) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > uint64(len(data)-n) {
+		return v, nil, ErrCorrupted
+	}
+
+	if v, err = codec.Decode(data[n : n+int(size)]); err != nil {
+		return v, nil, err
+	}
+
+	return v, data[n+int(size):], nil
+}
+
+// uvarintLen n的uvarint编码的字节数
+func uvarintLen(n int) int {
+	return len(binary.AppendUvarint(nil, uint64(n)))
+}
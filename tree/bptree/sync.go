@@ -0,0 +1,168 @@
+package bptree
+
+import (
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/utils"
+)
+
+// SyncTree 并发安全的b+树，读操作可以并行执行，Range、All、Backward、Iterator遍历时不持有锁，见orderedmap.Sync
+//
+// Search、SearchRange等查找方法在持有读锁时拷贝entry，返回的entry不在树中，读取时不需要持有锁，修改也不会影响树。
+// Cursor返回快照上的游标：在写锁中写时复制地克隆b+树，游标在克隆的树上移动，不持有锁，也看不到之后的修改
+type SyncTree[K, V any] struct {
+	*orderedmap.Sync[K, V]
+	tree *Tree[K, V]
+}
+
+// NewSyncTree 创建一个并发安全的b+树，tree交给SyncTree之后不能再直接使用
+func NewSyncTree[K, V any](tree *Tree[K, V]) *SyncTree[K, V] {
+	return &SyncTree[K, V]{
+		Sync: orderedmap.NewSync[K, V](tree),
+		tree: tree,
+	}
+}
+
+// Insert 插入，multimap模式下总是插入新节点
+func (t *SyncTree[K, V]) Insert(key K, val V) {
+	t.Update(func(orderedmap.OrderedMap[K, V]) {
+		t.tree.Insert(key, val)
+	})
+}
+
+// GetAll 按插入顺序返回key对应的所有value
+func (t *SyncTree[K, V]) GetAll(key K) (vals []V) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		vals = t.tree.GetAll(key)
+	})
+
+	return vals
+}
+
+// Count key对应的value的个数
+func (t *SyncTree[K, V]) Count(key K) (count int) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		count = t.tree.Count(key)
+	})
+
+	return count
+}
+
+// DeleteOne 删除key最早插入的一个value
+func (t *SyncTree[K, V]) DeleteOne(key K) (bFound bool) {
+	t.Update(func(orderedmap.OrderedMap[K, V]) {
+		bFound = t.tree.DeleteOne(key)
+	})
+
+	return bFound
+}
+
+// DeleteAll 删除key对应的所有value，返回删除的个数
+func (t *SyncTree[K, V]) DeleteAll(key K) (count int) {
+	t.Update(func(orderedmap.OrderedMap[K, V]) {
+		count = t.tree.DeleteAll(key)
+	})
+
+	return count
+}
+
+// Verify 验证b+树的性质
+func (t *SyncTree[K, V]) Verify() (bOK bool) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		bOK = t.tree.Verify()
+	})
+
+	return bOK
+}
+
+// String String
+func (t *SyncTree[K, V]) String() (str string) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		str = t.tree.String()
+	})
+
+	return str
+}
+
+// Clone 写时复制地克隆b+树，返回的树不是并发安全的，修改返回的树不会影响SyncTree，反之亦然
+func (t *SyncTree[K, V]) Clone() (clone *Tree[K, V]) {
+	// Clone会修改tree的版本号
+	t.Update(func(orderedmap.OrderedMap[K, V]) {
+		clone = t.tree.Clone()
+	})
+
+	return clone
+}
+
+// Search 查找key对应的entry，返回entry的拷贝
+func (t *SyncTree[K, V]) Search(key K) (entry *utils.TypedEntry[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		if found := t.tree.Search(key); found != nil {
+			entry = utils.NewTypedEntry(found.GetKey(), found.GetValue())
+		}
+	})
+
+	return entry
+}
+
+// SearchRange 查找key在[min, max]之间的entry，返回entry的拷贝
+func (t *SyncTree[K, V]) SearchRange(min, max K) (entries []*utils.TypedEntry[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		entries = snapshot(t.tree.SearchRange(min, max))
+	})
+
+	return entries
+}
+
+// SearchRangeDesc 按key降序查找key在[min, max]之间的entry，返回entry的拷贝
+func (t *SyncTree[K, V]) SearchRangeDesc(min, max K) (entries []*utils.TypedEntry[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		entries = snapshot(t.tree.SearchRangeDesc(min, max))
+	})
+
+	return entries
+}
+
+// SearchRangeLowerBoundKeyWithLimit 查找大于等于key的limit个entry，返回entry的拷贝
+func (t *SyncTree[K, V]) SearchRangeLowerBoundKeyWithLimit(key K, limit int64) (entries []*utils.TypedEntry[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		entries = snapshot(t.tree.SearchRangeLowerBoundKeyWithLimit(key, limit))
+	})
+
+	return entries
+}
+
+// SearchRangeUpperBoundKeyWithLimit 查找小于等于key的limit个entry，按key升序返回entry的拷贝
+func (t *SyncTree[K, V]) SearchRangeUpperBoundKeyWithLimit(key K, limit int64) (entries []*utils.TypedEntry[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		entries = snapshot(t.tree.SearchRangeUpperBoundKeyWithLimit(key, limit))
+	})
+
+	return entries
+}
+
+// Dot 持有读锁生成b+树的图片
+func (t *SyncTree[K, V]) Dot() (err error) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		err = t.tree.Dot()
+	})
+
+	return err
+}
+
+// Cursor 快照上的双向游标，需要调用First、Last、Seek或SeekLE定位
+//
+// 在写锁中克隆b+树，时间复杂度O(1)，游标移动时不持有锁，看不到之后对SyncTree的修改，
+// 通过游标Delete只修改克隆的树，不影响SyncTree
+func (t *SyncTree[K, V]) Cursor() *Cursor[K, V] {
+	return t.Clone().Cursor()
+}
+
+// snapshot 拷贝entries中的每个entry，调用方读取拷贝时不需要持有锁，修改拷贝也不会影响树
+func snapshot[K, V any](entries []*utils.TypedEntry[K, V]) []*utils.TypedEntry[K, V] {
+	list := make([]*utils.TypedEntry[K, V], 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, utils.NewTypedEntry(entry.GetKey(), entry.GetValue()))
+	}
+
+	return list
+}
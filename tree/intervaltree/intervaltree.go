@@ -0,0 +1,225 @@
+package intervaltree
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/asinglestep/gods/tree/rbtree"
+	"github.com/asinglestep/gods/utils"
+)
+
+// Interval 闭区间[Low, High]
+type Interval[T any] struct {
+	Low  T
+	High T
+}
+
+// Point 只包含一个点的区间[p, p]
+func Point[T any](p T) Interval[T] {
+	return Interval[T]{Low: p, High: p}
+}
+
+// maxHigh 子树中区间右端点的最大值，子树为空时bFound为false
+type maxHigh[T any] struct {
+	high   T
+	bFound bool
+}
+
+// Tree 区间树，基于红黑树实现，按区间的左端点排序，左端点相同时按右端点排序，
+// 每个节点维护以该节点为根的子树中区间右端点的最大值
+type Tree[T, V any] struct {
+	tree       *rbtree.Tree[Interval[T], V]
+	aug        *rbtree.Augmented[Interval[T], V, maxHigh[T]]
+	comparator func(a, b T) int
+}
+
+// NewTree 创建一个端点、value为interface{}的区间树
+func NewTree(comparator utils.Comparator) *Tree[interface{}, interface{}] {
+	return NewTreeFunc[interface{}, interface{}](comparator.Compare)
+}
+
+// NewTreeFunc 创建一个区间树
+//
+// @param
+// comparator: 端点的比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+func NewTreeFunc[T, V any](comparator func(a, b T) int) *Tree[T, V] {
+	t := &Tree[T, V]{}
+	t.comparator = comparator
+	t.tree = rbtree.NewTreeFunc[Interval[T], V](t.compareInterval)
+	t.aug = rbtree.Augment(t.tree, rbtree.Augmentation[Interval[T], V, maxHigh[T]]{
+		Value: func(key Interval[T], val V) maxHigh[T] {
+			return maxHigh[T]{high: key.High, bFound: true}
+		},
+		Combine: t.combine,
+	})
+
+	return t
+}
+
+// NewOrderedTree 创建一个端点为有序类型的区间树
+func NewOrderedTree[T cmp.Ordered, V any]() *Tree[T, V] {
+	return NewTreeFunc[T, V](cmp.Compare[T])
+}
+
+// Insert 插入区间，区间已存在时更新value，Low大于High时会交换两个端点
+func (t *Tree[T, V]) Insert(interval Interval[T], val V) {
+	t.tree.Insert(t.normalize(interval), val)
+}
+
+// Delete 删除区间，区间不存在时不做任何操作
+func (t *Tree[T, V]) Delete(interval Interval[T]) {
+	t.tree.Delete(t.normalize(interval))
+}
+
+// Get 查找区间对应的value
+//
+// @return
+// 是否找到区间
+func (t *Tree[T, V]) Get(interval Interval[T]) (V, bool) {
+	return t.tree.Get(t.normalize(interval))
+}
+
+// Len 区间个数
+func (t *Tree[T, V]) Len() int {
+	return t.tree.Len()
+}
+
+// AnyOverlap 查找任意一个和interval重叠的区间，查找包含点p的区间时使用Point(p)，时间复杂度O(log n)
+//
+// @return
+// 是否找到重叠的区间
+func (t *Tree[T, V]) AnyOverlap(interval Interval[T]) (key Interval[T], val V, bFound bool) {
+	interval = t.normalize(interval)
+	node := t.tree.Root()
+
+	for node != nil {
+		if t.overlaps(node.GetKey(), interval) {
+			return node.GetKey(), node.GetValue(), true
+		}
+
+		// 左子树中区间右端点的最大值小于interval.Low时，左子树中没有重叠的区间；
+		// 否则左子树中存在区间的右端点大于等于interval.Low，
+		// 如果左子树中没有重叠的区间，这个区间的左端点大于interval.High，右子树中的区间也不会重叠
+		if left := node.Left(); left != nil && t.comparator(t.aug.Aggregate(left).high, interval.Low) >= 0 {
+			node = left
+		} else {
+			node = node.Right()
+		}
+	}
+
+	return
+}
+
+// AllOverlaps 按左端点升序遍历所有和interval重叠的区间
+func (t *Tree[T, V]) AllOverlaps(interval Interval[T]) iter.Seq2[Interval[T], V] {
+	interval = t.normalize(interval)
+
+	return func(yield func(Interval[T], V) bool) {
+		t.overlapsInSubtree(t.tree.Root(), interval, yield)
+	}
+}
+
+// All 按左端点升序遍历所有区间
+func (t *Tree[T, V]) All() iter.Seq2[Interval[T], V] {
+	return t.tree.All()
+}
+
+// Backward 按左端点降序遍历所有区间
+func (t *Tree[T, V]) Backward() iter.Seq2[Interval[T], V] {
+	return t.tree.Backward()
+}
+
+// Verify 验证红黑树的性质，以及每个节点保存的右端点的最大值
+func (t *Tree[T, V]) Verify() bool {
+	if !t.tree.Verify() {
+		return false
+	}
+
+	_, ok := t.verify(t.tree.Root())
+	return ok
+}
+
+// verify 重新计算以node为根的子树中区间右端点的最大值，并和节点保存的值比较
+func (t *Tree[T, V]) verify(node *rbtree.TreeNode[Interval[T], V]) (maxHigh[T], bool) {
+	if node == nil {
+		return maxHigh[T]{}, true
+	}
+
+	left, ok := t.verify(node.Left())
+	if !ok {
+		return left, false
+	}
+
+	right, ok := t.verify(node.Right())
+	if !ok {
+		return right, false
+	}
+
+	res := t.combine(t.combine(left, maxHigh[T]{high: node.GetKey().High, bFound: true}), right)
+	agg := t.aug.Aggregate(node)
+
+	return res, agg.bFound && t.comparator(agg.high, res.high) == 0
+}
+
+// overlapsInSubtree 按左端点升序遍历以node为根的子树中和interval重叠的区间，yield返回false时停止遍历
+func (t *Tree[T, V]) overlapsInSubtree(node *rbtree.TreeNode[Interval[T], V], interval Interval[T], yield func(Interval[T], V) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	// 子树中区间右端点的最大值小于interval.Low，子树中没有重叠的区间
+	if t.comparator(t.aug.Aggregate(node).high, interval.Low) < 0 {
+		return true
+	}
+
+	if !t.overlapsInSubtree(node.Left(), interval, yield) {
+		return false
+	}
+
+	// 当前节点的左端点大于interval.High，右子树中区间的左端点都大于interval.High
+	if t.comparator(node.GetKey().Low, interval.High) > 0 {
+		return true
+	}
+
+	if t.overlaps(node.GetKey(), interval) && !yield(node.GetKey(), node.GetValue()) {
+		return false
+	}
+
+	return t.overlapsInSubtree(node.Right(), interval, yield)
+}
+
+// overlaps 区间a和区间b是否重叠
+func (t *Tree[T, V]) overlaps(a, b Interval[T]) bool {
+	return t.comparator(a.Low, b.High) <= 0 && t.comparator(b.Low, a.High) <= 0
+}
+
+// normalize Low大于High时交换两个端点
+func (t *Tree[T, V]) normalize(interval Interval[T]) Interval[T] {
+	if t.comparator(interval.Low, interval.High) > 0 {
+		interval.Low, interval.High = interval.High, interval.Low
+	}
+
+	return interval
+}
+
+// compareInterval 先比较左端点，左端点相同时比较右端点
+func (t *Tree[T, V]) compareInterval(a, b Interval[T]) int {
+	if res := t.comparator(a.Low, b.Low); res != 0 {
+		return res
+	}
+
+	return t.comparator(a.High, b.High)
+}
+
+// combine 取两个右端点最大值中较大的一个
+func (t *Tree[T, V]) combine(a, b maxHigh[T]) maxHigh[T] {
+	if !a.bFound {
+		return b
+	}
+
+	if !b.bFound || t.comparator(a.high, b.high) >= 0 {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,141 @@
+package intervaltree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+func Test_IntervalTreeInsertDelete(t *testing.T) {
+	tree := NewOrderedTree[int, int]()
+	num := 5000
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	m := make(map[Interval[int]]int)
+	for i := 0; i < num; i++ {
+		low := r.Intn(num)
+		iv := Interval[int]{Low: low, High: low + r.Intn(100)}
+		tree.Insert(iv, i)
+		m[iv] = i
+	}
+
+	// 删除一半
+	for iv := range m {
+		if r.Intn(2) == 0 {
+			tree.Delete(iv)
+			delete(m, iv)
+		}
+	}
+
+	if !tree.Verify() {
+		t.Fatalf("IntervalTree Verify Error\n")
+	}
+
+	if tree.Len() != len(m) {
+		t.Fatalf("Len want %v, got %v\n", len(m), tree.Len())
+	}
+
+	for iv, v := range m {
+		if got, ok := tree.Get(iv); !ok || got != v {
+			t.Fatalf("Get(%v) want %v, got %v %v\n", iv, v, got, ok)
+		}
+	}
+
+	// 按左端点升序，左端点相同时按右端点升序
+	var last *Interval[int]
+	for iv := range tree.All() {
+		if last != nil && (last.Low > iv.Low || last.Low == iv.Low && last.High >= iv.High) {
+			t.Fatalf("All not sorted: %v, %v\n", *last, iv)
+		}
+
+		last = &iv
+	}
+}
+
+func Test_IntervalTreeOverlap(t *testing.T) {
+	tree := NewOrderedTree[int, int]()
+	num := 2000
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	intervals := make(map[Interval[int]]bool)
+	for i := 0; i < num; i++ {
+		low := r.Intn(num * 10)
+		iv := Interval[int]{Low: low, High: low + r.Intn(20)}
+		tree.Insert(iv, i)
+		intervals[iv] = true
+	}
+
+	for i := 0; i < 2000; i++ {
+		low := r.Intn(num*10+20) - 10
+		query := Interval[int]{Low: low, High: low + r.Intn(30)}
+		if i%2 == 0 {
+			query = Point(low)
+		}
+
+		want := make([]Interval[int], 0)
+		for iv := range intervals {
+			if iv.Low <= query.High && query.Low <= iv.High {
+				want = append(want, iv)
+			}
+		}
+
+		slices.SortFunc(want, utils.Composite(
+			utils.By(func(iv Interval[int]) int { return iv.Low }),
+			utils.By(func(iv Interval[int]) int { return iv.High }),
+		))
+
+		got := make([]Interval[int], 0)
+		for iv := range tree.AllOverlaps(query) {
+			got = append(got, iv)
+		}
+
+		if !slices.Equal(want, got) {
+			t.Fatalf("AllOverlaps(%v) want %v, got %v\n", query, want, got)
+		}
+
+		iv, _, ok := tree.AnyOverlap(query)
+		if ok != (len(want) != 0) {
+			t.Fatalf("AnyOverlap(%v) want %v, got %v\n", query, len(want) != 0, ok)
+		}
+
+		if ok && !slices.Contains(want, iv) {
+			t.Fatalf("AnyOverlap(%v) got %v not overlap\n", query, iv)
+		}
+
+		// 提前结束遍历
+		cnt := 0
+		for range tree.AllOverlaps(query) {
+			cnt++
+			break
+		}
+
+		if cnt != min(1, len(want)) {
+			t.Fatalf("AllOverlaps(%v) break got %v\n", query, cnt)
+		}
+	}
+}
+
+func Test_IntervalTreeNormalize(t *testing.T) {
+	tree := NewTree(utils.IntComparator)
+	tree.Insert(Interval[interface{}]{Low: 10, High: 1}, "a")
+
+	if v, ok := tree.Get(Interval[interface{}]{Low: 1, High: 10}); !ok || v != "a" {
+		t.Fatalf("want a, got %v %v\n", v, ok)
+	}
+
+	if _, _, ok := tree.AnyOverlap(Point[interface{}](11)); ok {
+		t.Fatalf("Point(11) want not overlap\n")
+	}
+
+	if iv, _, ok := tree.AnyOverlap(Interval[interface{}]{Low: 20, High: 10}); !ok || iv.Low != 1 {
+		t.Fatalf("want [1, 10], got %v %v\n", iv, ok)
+	}
+
+	tree.Delete(Interval[interface{}]{Low: 10, High: 1})
+	if tree.Len() != 0 || !tree.Verify() {
+		t.Fatalf("delete err, len %v\n", tree.Len())
+	}
+}
@@ -0,0 +1,46 @@
+package prbtree
+
+// Iterator 迭代器，节点没有父节点指针，使用栈保存从根节点到当前节点的路径
+type Iterator[K, V any] struct {
+	stack []*TreeNode[K, V]
+	node  *TreeNode[K, V]
+}
+
+// NewIterator 从最小key开始的迭代器，迭代的是创建迭代器时tree的版本
+func NewIterator[K, V any](tree *Tree[K, V]) *Iterator[K, V] {
+	iter := &Iterator[K, V]{}
+	iter.pushLeft(tree.root)
+
+	return iter
+}
+
+// Next 移动到下一个节点，没有下一个节点时返回false
+func (iter *Iterator[K, V]) Next() bool {
+	if len(iter.stack) == 0 {
+		iter.node = nil
+		return false
+	}
+
+	iter.node = iter.stack[len(iter.stack)-1]
+	iter.stack = iter.stack[:len(iter.stack)-1]
+	iter.pushLeft(iter.node.right)
+
+	return true
+}
+
+// GetKey 当前节点的key
+func (iter *Iterator[K, V]) GetKey() K {
+	return iter.node.key
+}
+
+// GetValue 当前节点的value
+func (iter *Iterator[K, V]) GetValue() V {
+	return iter.node.val
+}
+
+// pushLeft 将node和node的左子节点依次压入栈中
+func (iter *Iterator[K, V]) pushLeft(node *TreeNode[K, V]) {
+	for ; node != nil; node = node.left {
+		iter.stack = append(iter.stack, node)
+	}
+}
@@ -0,0 +1,124 @@
+package prbtree
+
+import (
+	"iter"
+	"sync/atomic"
+
+	"github.com/asinglestep/gods/map/orderedmap"
+)
+
+// Map 基于持久化红黑树的有序map，每次修改都会生成一个新版本的树并替换当前版本
+//
+// Snapshot以O(1)的代价返回当前版本，读者可以在任意goroutine中读取快照，写者继续修改Map不会影响已经返回的快照；
+// 多个写者之间需要调用方自己加锁
+type Map[K, V any] struct {
+	tree atomic.Pointer[Tree[K, V]]
+}
+
+// NewMap 创建一个以tree为初始版本的有序map
+func NewMap[K, V any](tree *Tree[K, V]) *Map[K, V] {
+	m := &Map[K, V]{}
+	m.tree.Store(tree)
+
+	return m
+}
+
+// Snapshot 当前版本的树
+func (m *Map[K, V]) Snapshot() *Tree[K, V] {
+	return m.tree.Load()
+}
+
+// Put 插入key、value，key已存在时更新value
+func (m *Map[K, V]) Put(key K, val V) {
+	m.tree.Store(m.Snapshot().Insert(key, val))
+}
+
+// Get 查找key对应的value
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	return m.Snapshot().Get(key)
+}
+
+// Delete 删除key，key不存在时不做任何操作
+func (m *Map[K, V]) Delete(key K) {
+	m.tree.Store(m.Snapshot().Delete(key))
+}
+
+// Len 元素个数
+func (m *Map[K, V]) Len() int {
+	return m.Snapshot().Len()
+}
+
+// Min 最小的key和对应的value
+func (m *Map[K, V]) Min() (K, V, bool) {
+	return m.Snapshot().Min()
+}
+
+// Max 最大的key和对应的value
+func (m *Map[K, V]) Max() (K, V, bool) {
+	return m.Snapshot().Max()
+}
+
+// Floor 小于等于key的最大key和对应的value
+func (m *Map[K, V]) Floor(key K) (K, V, bool) {
+	return m.Snapshot().Floor(key)
+}
+
+// Ceiling 大于等于key的最小key和对应的value
+func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
+	return m.Snapshot().Ceiling(key)
+}
+
+// Lower 小于key的最大key和对应的value
+func (m *Map[K, V]) Lower(key K) (K, V, bool) {
+	return m.Snapshot().Lower(key)
+}
+
+// Higher 大于key的最小key和对应的value
+func (m *Map[K, V]) Higher(key K) (K, V, bool) {
+	return m.Snapshot().Higher(key)
+}
+
+// PopMin 删除最小的key，并返回最小的key和对应的value
+func (m *Map[K, V]) PopMin() (key K, val V, bFound bool) {
+	tree := m.Snapshot()
+	if key, val, bFound = tree.Min(); bFound {
+		m.tree.Store(tree.Delete(key))
+	}
+
+	return key, val, bFound
+}
+
+// PopMax 删除最大的key，并返回最大的key和对应的value
+func (m *Map[K, V]) PopMax() (key K, val V, bFound bool) {
+	tree := m.Snapshot()
+	if key, val, bFound = tree.Max(); bFound {
+		m.tree.Store(tree.Delete(key))
+	}
+
+	return key, val, bFound
+}
+
+// Range 按key升序遍历当前版本中key在[lo, hi]之间的元素
+func (m *Map[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return m.Snapshot().Range(lo, hi)
+}
+
+// All 按key升序遍历当前版本的所有元素
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return m.Snapshot().All()
+}
+
+// Backward 按key降序遍历当前版本的所有元素
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return m.Snapshot().Backward()
+}
+
+// Iterator 当前版本从最小key开始的迭代器
+func (m *Map[K, V]) Iterator() orderedmap.Iterator[K, V] {
+	return m.Snapshot().Iterator()
+}
+
+// Verify 验证当前版本的树
+func (m *Map[K, V]) Verify() bool {
+	return m.Snapshot().Verify()
+}
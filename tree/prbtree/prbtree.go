@@ -0,0 +1,379 @@
+package prbtree
+
+import (
+	"cmp"
+	"iter"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// Tree 持久化（不可变）红黑树，基于左倾红黑树实现
+//
+// Insert、Delete不会修改当前的树，而是拷贝从根节点到修改位置路径上的节点，返回一个新版本的树，
+// 新版本和旧版本共享没有修改的节点，持有旧版本的读者看到的始终是修改之前的数据，
+// 每个版本都可以在多个goroutine中并发读取
+type Tree[K, V any] struct {
+	root       *TreeNode[K, V]
+	size       int // 节点数
+	comparator func(a, b K) int
+}
+
+// NewTree 创建一个key、value为interface{}的持久化红黑树
+func NewTree(comparator utils.Comparator) *Tree[interface{}, interface{}] {
+	return NewTreeFunc[interface{}, interface{}](comparator.Compare)
+}
+
+// NewTreeFunc 创建一个空的持久化红黑树
+//
+// @param
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+func NewTreeFunc[K, V any](comparator func(a, b K) int) *Tree[K, V] {
+	return &Tree[K, V]{
+		comparator: comparator,
+	}
+}
+
+// NewOrderedTree 创建一个key为有序类型的持久化红黑树
+func NewOrderedTree[K cmp.Ordered, V any]() *Tree[K, V] {
+	return NewTreeFunc[K, V](cmp.Compare[K])
+}
+
+// Insert 插入key、value，key已存在时更新value，返回新版本的树，当前的树不变
+func (t *Tree[K, V]) Insert(key K, val V) *Tree[K, V] {
+	root, bAdded := t.insert(t.root, key, val)
+	if root.red {
+		// 根节点为黑色，root是新拷贝的节点，可以直接修改
+		root.red = false
+	}
+
+	size := t.size
+	if bAdded {
+		size++
+	}
+
+	return t.version(root, size)
+}
+
+// insert 在以node为根节点的子树中插入key、value，返回新的根节点
+//
+// @return
+// bAdded: 是否插入了新的key
+func (t *Tree[K, V]) insert(node *TreeNode[K, V], key K, val V) (*TreeNode[K, V], bool) {
+	if node == nil {
+		return newTreeNode(key, val), true
+	}
+
+	var bAdded bool
+	node = node.clone()
+
+	switch res := t.comparator(key, node.key); {
+	case res < 0:
+		node.left, bAdded = t.insert(node.left, key, val)
+	case res > 0:
+		node.right, bAdded = t.insert(node.right, key, val)
+	default:
+		node.val = val
+	}
+
+	return node.balance(), bAdded
+}
+
+// Delete 删除key，返回新版本的树，当前的树不变，key不存在时返回当前的树
+func (t *Tree[K, V]) Delete(key K) *Tree[K, V] {
+	if t.lookup(key) == nil {
+		return t
+	}
+
+	root := t.root.clone()
+	if !root.left.isRed() && !root.right.isRed() {
+		root.red = true
+	}
+
+	root = t.delete(root, key)
+	if root.isRed() {
+		root = root.clone()
+		root.red = false
+	}
+
+	return t.version(root, t.size-1)
+}
+
+// delete 在以node为根节点的子树中删除key，key一定存在，返回新的根节点
+func (t *Tree[K, V]) delete(node *TreeNode[K, V], key K) *TreeNode[K, V] {
+	node = node.clone()
+
+	if t.comparator(key, node.key) < 0 {
+		if !node.left.isRed() && !node.left.left.isRed() {
+			node = node.moveRedLeft()
+		}
+
+		node.left = t.delete(node.left, key)
+		return node.balance()
+	}
+
+	if node.left.isRed() {
+		node = node.rotateRight()
+	}
+
+	if t.comparator(key, node.key) == 0 && node.right == nil {
+		return nil
+	}
+
+	if !node.right.isRed() && !node.right.left.isRed() {
+		node = node.moveRedRight()
+	}
+
+	if t.comparator(key, node.key) == 0 {
+		// 用后继节点替换当前节点，再删除后继节点
+		successor := node.right.minimum()
+		node.key = successor.key
+		node.val = successor.val
+		node.right = node.right.deleteMin()
+	} else {
+		node.right = t.delete(node.right, key)
+	}
+
+	return node.balance()
+}
+
+// version 创建一个新版本的树
+func (t *Tree[K, V]) version(root *TreeNode[K, V], size int) *Tree[K, V] {
+	return &Tree[K, V]{
+		root:       root,
+		size:       size,
+		comparator: t.comparator,
+	}
+}
+
+// Search 查找key指定的节点，没找到返回nil
+func (t *Tree[K, V]) Search(key K) *TreeNode[K, V] {
+	return t.lookup(key)
+}
+
+// Get 查找key对应的value
+//
+// @return
+// 是否找到key
+func (t *Tree[K, V]) Get(key K) (val V, bFound bool) {
+	_, val, bFound = t.lookup(key).unpack()
+	return val, bFound
+}
+
+// Len 节点数
+func (t *Tree[K, V]) Len() int {
+	return t.size
+}
+
+// Min 最小的key和对应的value
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	return t.root.minimum().unpack()
+}
+
+// Max 最大的key和对应的value
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	return t.root.maximum().unpack()
+}
+
+// Floor 小于等于key的最大key和对应的value
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	return t.lookupLess(key, true).unpack()
+}
+
+// Ceiling 大于等于key的最小key和对应的value
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	return t.lookupGreater(key, true).unpack()
+}
+
+// Lower 小于key的最大key和对应的value
+func (t *Tree[K, V]) Lower(key K) (K, V, bool) {
+	return t.lookupLess(key, false).unpack()
+}
+
+// Higher 大于key的最小key和对应的value
+func (t *Tree[K, V]) Higher(key K) (K, V, bool) {
+	return t.lookupGreater(key, false).unpack()
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的节点
+func (t *Tree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.rangeNode(t.root, lo, hi, yield)
+	}
+}
+
+// rangeNode 按key升序遍历以node为根节点的子树中key在[lo, hi]之间的节点，yield返回false时停止遍历
+func (t *Tree[K, V]) rangeNode(node *TreeNode[K, V], lo, hi K, yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	bGtLo := t.comparator(node.key, lo) >= 0
+	bLtHi := t.comparator(node.key, hi) <= 0
+
+	if bGtLo && !t.rangeNode(node.left, lo, hi, yield) {
+		return false
+	}
+
+	if bGtLo && bLtHi && !yield(node.key, node.val) {
+		return false
+	}
+
+	if bLtHi {
+		return t.rangeNode(node.right, lo, hi, yield)
+	}
+
+	return true
+}
+
+// All 按key升序遍历所有节点
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.all(yield)
+	}
+}
+
+// Backward 按key降序遍历所有节点
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.root.backward(yield)
+	}
+}
+
+// Iterator 从最小key开始的迭代器
+func (t *Tree[K, V]) Iterator() *Iterator[K, V] {
+	return NewIterator(t)
+}
+
+// Verify 验证左倾红黑树的性质
+func (t *Tree[K, V]) Verify() bool {
+	if t.root.isRed() {
+		return false
+	}
+
+	count, _, ok := t.verify(t.root)
+	if !ok || count != t.size {
+		return false
+	}
+
+	// 验证顺序
+	var last *TreeNode[K, V]
+	for node := range t.nodes() {
+		if last != nil && t.comparator(last.key, node.key) >= 0 {
+			return false
+		}
+
+		last = node
+	}
+
+	return true
+}
+
+// verify 验证以node为根节点的子树
+//
+// @return
+// count: 节点数
+// blackHeight: 黑色高度
+// ok: 右子节点不是红色，没有连续的红色节点，左右子树的黑色高度相同
+func (t *Tree[K, V]) verify(node *TreeNode[K, V]) (count int, blackHeight int, ok bool) {
+	if node == nil {
+		return 0, 1, true
+	}
+
+	if node.right.isRed() {
+		return 0, 0, false
+	}
+
+	if node.red && node.left.isRed() {
+		return 0, 0, false
+	}
+
+	lCount, lHeight, ok := t.verify(node.left)
+	if !ok {
+		return 0, 0, false
+	}
+
+	rCount, rHeight, ok := t.verify(node.right)
+	if !ok || lHeight != rHeight {
+		return 0, 0, false
+	}
+
+	if !node.red {
+		lHeight++
+	}
+
+	return lCount + rCount + 1, lHeight, true
+}
+
+// nodes 中序遍历所有节点
+func (t *Tree[K, V]) nodes() iter.Seq[*TreeNode[K, V]] {
+	return func(yield func(*TreeNode[K, V]) bool) {
+		iter := NewIterator(t)
+		for iter.Next() {
+			if !yield(iter.node) {
+				return
+			}
+		}
+	}
+}
+
+// lookup 查找key所在的节点
+func (t *Tree[K, V]) lookup(key K) *TreeNode[K, V] {
+	node := t.root
+
+	for node != nil {
+		switch res := t.comparator(key, node.key); {
+		case res < 0:
+			node = node.left
+		case res > 0:
+			node = node.right
+		default:
+			return node
+		}
+	}
+
+	return nil
+}
+
+// lookupLess 最后一个小于key的节点，orEqual为true时最后一个小于等于key的节点
+func (t *Tree[K, V]) lookupLess(key K, orEqual bool) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
+	node := t.root
+
+	for node != nil {
+		res := t.comparator(node.key, key)
+		if res == 0 && orEqual {
+			return node
+		}
+
+		if res < 0 {
+			last = node
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+
+	return last
+}
+
+// lookupGreater 第一个大于key的节点，orEqual为true时第一个大于等于key的节点
+func (t *Tree[K, V]) lookupGreater(key K, orEqual bool) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
+	node := t.root
+
+	for node != nil {
+		res := t.comparator(node.key, key)
+		if res == 0 && orEqual {
+			return node
+		}
+
+		if res > 0 {
+			last = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+
+	return last
+}
@@ -0,0 +1,67 @@
+package prbtree
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/asinglestep/gods/tree/rbtree"
+)
+
+// 每次插入之后保留一个只读版本，持久化红黑树只需要拷贝路径上的节点，rbtree需要拷贝整棵树
+
+func Benchmark_PrbTreeInsertSnapshot(b *testing.B) {
+	var num = 2000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+
+	for i := 0; i < b.N; i++ {
+		tree := NewOrderedTree[int, int]()
+		versions := make([]*Tree[int, int], 0, num)
+		for _, v := range array {
+			tree = tree.Insert(v, v)
+			versions = append(versions, tree)
+		}
+	}
+}
+
+func Benchmark_RbTreeCopyInsertSnapshot(b *testing.B) {
+	var num = 2000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+
+	for i := 0; i < b.N; i++ {
+		tree := rbtree.NewOrderedTree[int, int]()
+		versions := make([]*rbtree.Tree[int, int], 0, num)
+		for _, v := range array {
+			tree.Insert(v, v)
+
+			version := rbtree.NewOrderedTree[int, int]()
+			for k, v := range tree.All() {
+				version.Insert(k, v)
+			}
+
+			versions = append(versions, version)
+		}
+	}
+}
+
+func Benchmark_PrbTreeRandDelete(b *testing.B) {
+	var num = 100000
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	array := r.Perm(num)
+
+	tree := NewOrderedTree[int, int]()
+	for _, v := range array {
+		tree = tree.Insert(v, v)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		version := tree
+		for _, v := range array {
+			version = version.Delete(v)
+		}
+	}
+}
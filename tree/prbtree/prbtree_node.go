@@ -0,0 +1,179 @@
+package prbtree
+
+// TreeNode 持久化红黑树节点，节点被树引用之后不会再被修改
+type TreeNode[K, V any] struct {
+	key   K
+	val   V
+	left  *TreeNode[K, V]
+	right *TreeNode[K, V]
+	red   bool // 是否是红色节点，指向红色节点的链接只能是左链接
+}
+
+// newTreeNode 新建一个红色节点
+func newTreeNode[K, V any](key K, val V) *TreeNode[K, V] {
+	return &TreeNode[K, V]{
+		key: key,
+		val: val,
+		red: true,
+	}
+}
+
+// GetKey 获取key
+func (node *TreeNode[K, V]) GetKey() K {
+	return node.key
+}
+
+// GetValue 获取value
+func (node *TreeNode[K, V]) GetValue() V {
+	return node.val
+}
+
+// clone 拷贝节点，修改节点之前需要先拷贝，保证旧版本的树不变
+func (node *TreeNode[K, V]) clone() *TreeNode[K, V] {
+	n := *node
+	return &n
+}
+
+// isRed 是否是红色节点，nil为黑色
+func (node *TreeNode[K, V]) isRed() bool {
+	return node != nil && node.red
+}
+
+// rotateLeft 左旋，node必须是拷贝过的节点
+func (node *TreeNode[K, V]) rotateLeft() *TreeNode[K, V] {
+	r := node.right.clone()
+	node.right = r.left
+	r.left = node
+	r.red = node.red
+	node.red = true
+
+	return r
+}
+
+// rotateRight 右旋，node必须是拷贝过的节点
+func (node *TreeNode[K, V]) rotateRight() *TreeNode[K, V] {
+	l := node.left.clone()
+	node.left = l.right
+	l.right = node
+	l.red = node.red
+	node.red = true
+
+	return l
+}
+
+// flipColors 翻转node和左右子节点的颜色，node必须是拷贝过的节点
+func (node *TreeNode[K, V]) flipColors() {
+	node.red = !node.red
+	node.left = node.left.clone()
+	node.left.red = !node.left.red
+	node.right = node.right.clone()
+	node.right.red = !node.right.red
+}
+
+// balance 恢复左倾红黑树的性质，node必须是拷贝过的节点
+func (node *TreeNode[K, V]) balance() *TreeNode[K, V] {
+	if node.right.isRed() && !node.left.isRed() {
+		node = node.rotateLeft()
+	}
+
+	if node.left.isRed() && node.left.left.isRed() {
+		node = node.rotateRight()
+	}
+
+	if node.left.isRed() && node.right.isRed() {
+		node.flipColors()
+	}
+
+	return node
+}
+
+// moveRedLeft node的左子节点和左子节点的左子节点都是黑色时，将node或者右子节点变成左子节点，node必须是拷贝过的节点
+func (node *TreeNode[K, V]) moveRedLeft() *TreeNode[K, V] {
+	node.flipColors()
+	if node.right.left.isRed() {
+		node.right = node.right.rotateRight()
+		node = node.rotateLeft()
+		node.flipColors()
+	}
+
+	return node
+}
+
+// moveRedRight node的右子节点和右子节点的左子节点都是黑色时，将node或者左子节点变成右子节点，node必须是拷贝过的节点
+func (node *TreeNode[K, V]) moveRedRight() *TreeNode[K, V] {
+	node.flipColors()
+	if node.left.left.isRed() {
+		node = node.rotateRight()
+		node.flipColors()
+	}
+
+	return node
+}
+
+// deleteMin 删除以node为根节点的子树中最小的节点，返回新的根节点
+func (node *TreeNode[K, V]) deleteMin() *TreeNode[K, V] {
+	if node.left == nil {
+		return nil
+	}
+
+	node = node.clone()
+	if !node.left.isRed() && !node.left.left.isRed() {
+		node = node.moveRedLeft()
+	}
+
+	node.left = node.left.deleteMin()
+	return node.balance()
+}
+
+// minimum 以当前节点为根节点的子树中最小的节点
+func (node *TreeNode[K, V]) minimum() *TreeNode[K, V] {
+	if node == nil {
+		return nil
+	}
+
+	for node.left != nil {
+		node = node.left
+	}
+
+	return node
+}
+
+// maximum 以当前节点为根节点的子树中最大的节点
+func (node *TreeNode[K, V]) maximum() *TreeNode[K, V] {
+	if node == nil {
+		return nil
+	}
+
+	for node.right != nil {
+		node = node.right
+	}
+
+	return node
+}
+
+// all 中序遍历以当前节点为根节点的子树，yield返回false时停止遍历
+func (node *TreeNode[K, V]) all(yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	return node.left.all(yield) && yield(node.key, node.val) && node.right.all(yield)
+}
+
+// backward 逆序遍历以当前节点为根节点的子树，yield返回false时停止遍历
+func (node *TreeNode[K, V]) backward(yield func(K, V) bool) bool {
+	if node == nil {
+		return true
+	}
+
+	return node.right.backward(yield) && yield(node.key, node.val) && node.left.backward(yield)
+}
+
+// unpack 获取节点的key和value，node为nil时返回false
+func (node *TreeNode[K, V]) unpack() (key K, val V, bFound bool) {
+	if node == nil {
+		return key, val, false
+	}
+
+	return node.key, node.val, true
+}
@@ -0,0 +1,171 @@
+package prbtree
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/map/orderedmap/conformance"
+	"github.com/asinglestep/gods/utils"
+)
+
+func Test_PrbTreeInsert(t *testing.T) {
+	tree := NewTree(utils.IntComparator)
+	for i := 0; i < 1000; i++ {
+		tree = tree.Insert(i, i)
+		if !tree.Verify() {
+			t.Fatalf("insert %v: Verify Error\n", i)
+		}
+	}
+
+	if tree.Len() != 1000 {
+		t.Fatalf("Len want 1000, got %v\n", tree.Len())
+	}
+
+	for i := 0; i < 1000; i++ {
+		if v, ok := tree.Get(i); !ok || v != i {
+			t.Fatalf("Get(%v) want %v, got %v %v\n", i, i, v, ok)
+		}
+	}
+}
+
+func Test_PrbTreeRandDelete(t *testing.T) {
+	var num = 10000
+
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	tree := NewOrderedTree[int, int]()
+	for _, v := range r.Perm(num) {
+		tree = tree.Insert(v, v)
+	}
+
+	for i, v := range r.Perm(num) {
+		tree = tree.Delete(v)
+		if i%100 == 0 && !tree.Verify() {
+			t.Fatalf("seed %v: delete %v Verify Error\n", seed, v)
+		}
+
+		if _, ok := tree.Get(v); ok {
+			t.Fatalf("seed %v: %v should be deleted\n", seed, v)
+		}
+	}
+
+	if tree.Len() != 0 || !tree.Verify() {
+		t.Fatalf("seed %v: want empty tree, got %v\n", seed, tree.Len())
+	}
+}
+
+func Test_PrbTreeVersions(t *testing.T) {
+	// 每次修改之后保存一个版本，以及这个版本期望的数据，最后检查所有的旧版本都没有被修改
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	var versions []*Tree[int, int]
+	var wants []map[int]int
+
+	tree := NewOrderedTree[int, int]()
+	want := map[int]int{}
+
+	for i := 0; i < 2000; i++ {
+		key := r.Intn(300)
+		if r.Intn(3) == 0 {
+			tree = tree.Delete(key)
+			delete(want, key)
+		} else {
+			tree = tree.Insert(key, i)
+			want[key] = i
+		}
+
+		snapshot := make(map[int]int, len(want))
+		for k, v := range want {
+			snapshot[k] = v
+		}
+
+		versions = append(versions, tree)
+		wants = append(wants, snapshot)
+	}
+
+	for i, version := range versions {
+		if !version.Verify() {
+			t.Fatalf("seed %v: version %v Verify Error\n", seed, i)
+		}
+
+		if version.Len() != len(wants[i]) {
+			t.Fatalf("seed %v: version %v Len want %v, got %v\n", seed, i, len(wants[i]), version.Len())
+		}
+
+		for k, v := range version.All() {
+			if wants[i][k] != v {
+				t.Fatalf("seed %v: version %v key %v want %v, got %v\n", seed, i, k, wants[i][k], v)
+			}
+		}
+	}
+}
+
+func Test_PrbTreeDeleteNotExist(t *testing.T) {
+	tree := NewOrderedTree[int, int]().Insert(1, 1).Insert(2, 2)
+	if tree.Delete(3) != tree {
+		t.Fatalf("Delete a key that does not exist should return the same version\n")
+	}
+}
+
+func Test_PrbTreeConformance(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewMap(NewOrderedTree[int, int]())
+	})
+}
+
+func Test_PrbTreeConcurrentSnapshot(t *testing.T) {
+	// 一个写者不断修改，多个读者读取快照，使用go test -race验证
+	m := NewMap(NewOrderedTree[int, int]())
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				snapshot := m.Snapshot()
+				count := 0
+				for k, v := range snapshot.All() {
+					if k != v {
+						t.Errorf("key %v want value %v, got %v\n", k, k, v)
+						return
+					}
+
+					count++
+				}
+
+				if count != snapshot.Len() || !snapshot.Verify() {
+					t.Errorf("snapshot Verify Error\n")
+					return
+				}
+			}
+		}()
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < 5000; i++ {
+		key := r.Intn(500)
+		if r.Intn(2) == 0 {
+			m.Put(key, key)
+		} else {
+			m.Delete(key)
+		}
+	}
+
+	close(done)
+	wg.Wait()
+}
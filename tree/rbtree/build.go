@@ -0,0 +1,76 @@
+package rbtree
+
+import (
+	"iter"
+	"math/bits"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// BuildFromSorted 由按key升序排列的seq创建红黑树，时间复杂度O(n)
+//
+// @param
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// seq: 按key升序排列的key、value，key不能重复，multimap模式下允许相同的key
+// opts: multimap模式等选项
+//
+// @return
+// seq没有按key升序排列时返回false
+func BuildFromSorted[K, V any](comparator func(a, b K) int, seq iter.Seq2[K, V], opts ...Option) (*Tree[K, V], bool) {
+	t := NewTreeFunc[K, V](comparator, opts...)
+
+	var nodes []*TreeNode[K, V]
+	for key, val := range seq {
+		if len(nodes) > 0 && !t.sorted(nodes[len(nodes)-1].GetKey(), key) {
+			return nil, false
+		}
+
+		nodes = append(nodes, NewTreeNode(utils.NewTypedEntry(key, val), t.sentinel))
+	}
+
+	// 最后一层不满时，最后一层的节点为红色，其他节点为黑色
+	t.root = t.build(nodes, 0, bits.Len(uint(len(nodes)+1))-1)
+	t.size = len(nodes)
+
+	return t, true
+}
+
+// build 以nodes中间的节点为根节点创建子树，返回子树的根节点
+//
+// 左右子树的节点数最多相差1，除了最后一层，每一层都是满的，所以每条路径上的黑色节点数相同
+//
+// @param
+// depth: 子树的根节点的深度
+// redDepth: 深度为redDepth的节点为红色
+func (t *Tree[K, V]) build(nodes []*TreeNode[K, V], depth, redDepth int) *TreeNode[K, V] {
+	if len(nodes) == 0 {
+		return t.sentinel
+	}
+
+	mid := len(nodes) / 2
+	node := nodes[mid]
+	node.left = t.build(nodes[:mid], depth+1, redDepth)
+	node.right = t.build(nodes[mid+1:], depth+1, redDepth)
+	node.size = node.left.size + node.right.size + 1
+
+	if !node.left.isSentinel() {
+		node.left.parent = node
+	}
+
+	if !node.right.isSentinel() {
+		node.right.parent = node
+	}
+
+	node.color = BLACK
+	if depth == redDepth {
+		node.color = RED
+	}
+
+	return node
+}
+
+// sorted key是否可以排在prev之后，multimap模式下允许相同的key
+func (t *Tree[K, V]) sorted(prev, key K) bool {
+	res := t.comparator(prev, key)
+	return res < 0 || (res == 0 && t.multi)
+}
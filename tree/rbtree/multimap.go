@@ -0,0 +1,82 @@
+package rbtree
+
+import (
+	"iter"
+)
+
+// GetAll 按插入顺序返回key对应的所有value，key不存在时返回nil
+func (t *Tree[K, V]) GetAll(key K) []V {
+	var vals []V
+	for node := range t.equalNodes(key) {
+		vals = append(vals, node.GetValue())
+	}
+
+	return vals
+}
+
+// Count key对应的value的个数，时间复杂度O(log n)
+func (t *Tree[K, V]) Count(key K) int {
+	return t.countLess(key, true) - t.countLess(key, false)
+}
+
+// DeleteOne 删除key最早插入的一个节点
+//
+// @return
+// 是否找到key
+func (t *Tree[K, V]) DeleteOne(key K) bool {
+	node := t.lookupFirst(key)
+	if node == nil {
+		return false
+	}
+
+	t.deleteNode(node)
+	return true
+}
+
+// DeleteAll 删除key对应的所有节点，返回删除的节点数
+func (t *Tree[K, V]) DeleteAll(key K) int {
+	var count int
+	for t.DeleteOne(key) {
+		count++
+	}
+
+	return count
+}
+
+// lookupFirst 查找key所在的第一个节点，multimap模式下是最早插入的节点
+func (t *Tree[K, V]) lookupFirst(key K) *TreeNode[K, V] {
+	if !t.multi {
+		return t.lookup(key)
+	}
+
+	var first *TreeNode[K, V]
+	node := t.root
+
+	for !node.isSentinel() {
+		res := t.comparator(node.GetKey(), key)
+		if res < 0 {
+			node = node.right
+			continue
+		}
+
+		if res == 0 {
+			first = node
+		}
+
+		node = node.left
+	}
+
+	return first
+}
+
+// equalNodes 按插入顺序遍历key所在的节点
+func (t *Tree[K, V]) equalNodes(key K) iter.Seq[*TreeNode[K, V]] {
+	return func(yield func(*TreeNode[K, V]) bool) {
+		iter := NewIteratorWithNode(t, t.lookupFirst(key))
+		for iter.Next() && t.comparator(iter.GetKey(), key) == 0 {
+			if !yield(iter.node) {
+				return
+			}
+		}
+	}
+}
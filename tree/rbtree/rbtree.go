@@ -2,51 +2,76 @@ package rbtree
 
 import (
 	"bytes"
+	"cmp"
 	"container/list"
 	"fmt"
+	"iter"
 	"os/exec"
 	"runtime"
 
 	dot "github.com/asinglestep/godot"
+	"github.com/asinglestep/gods/map/orderedmap"
 	"github.com/asinglestep/gods/utils"
 )
 
 // Tree Tree
-type Tree struct {
-	root       *TreeNode
-	size       int // 节点数
-	comparator utils.Comparator
+type Tree[K, V any] struct {
+	root       *TreeNode[K, V]
+	sentinel   *TreeNode[K, V] // 哨兵节点
+	size       int             // 节点数
+	comparator func(a, b K) int
+	augmenter  augmenter[K, V] // 聚合值，没有注册时为nil
+	multi      bool            // 是否是multimap模式
 }
 
-// NewTree NewTree
-func NewTree(comparator utils.Comparator) *Tree {
-	t := &Tree{}
-	t.root = Sentinel
+// NewTree 创建一个key、value为interface{}的红黑树
+func NewTree(comparator utils.Comparator, opts ...Option) *Tree[interface{}, interface{}] {
+	return NewTreeFunc[interface{}, interface{}](comparator.Compare, opts...)
+}
+
+// NewTreeFunc 创建一个红黑树
+//
+// @param
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// opts: multimap模式等选项
+func NewTreeFunc[K, V any](comparator func(a, b K) int, opts ...Option) *Tree[K, V] {
+	o := newOptions(opts)
+
+	t := &Tree[K, V]{}
+	t.sentinel = newSentinel[K, V]()
+	t.root = t.sentinel
 	t.comparator = comparator
+	t.multi = o.multi
 
 	return t
 }
 
-// Insert 插入
-func (t *Tree) Insert(key, val interface{}) {
-	t.insertNode(NewTreeNode(utils.NewEntry(key, val)))
+// NewOrderedTree 创建一个key为有序类型的红黑树
+func NewOrderedTree[K cmp.Ordered, V any](opts ...Option) *Tree[K, V] {
+	return NewTreeFunc[K, V](cmp.Compare[K], opts...)
+}
+
+// Insert 插入，key已存在时更新value，multimap模式下总是插入新节点
+func (t *Tree[K, V]) Insert(key K, val V) {
+	t.insertNode(NewTreeNode(utils.NewTypedEntry(key, val), t.sentinel))
 }
 
 // insertNode 插入新节点
-func (t *Tree) insertNode(node *TreeNode) {
+func (t *Tree[K, V]) insertNode(node *TreeNode[K, V]) {
 	next := &t.root
-	var parent *TreeNode
+	var parent *TreeNode[K, V]
 
 	for cur := *next; !cur.isSentinel(); cur = *next {
-		res := t.comparator.Compare(cur.GetKey(), node.GetKey())
-		if res == utils.Et {
+		res := t.comparator(cur.GetKey(), node.GetKey())
+		if res == 0 && !t.multi {
 			cur.entry.SetValue(node.GetValue())
+			t.augmentPath(cur)
 			return
 		}
 
 		parent = cur
-		if res == utils.Lt {
-			// 在右子树查找
+		if res <= 0 {
+			// 在右子树查找，multimap模式下相同的key插入到右子树，保持插入顺序
 			next = &cur.right
 		} else {
 			// 在左子树查找
@@ -56,12 +81,20 @@ func (t *Tree) insertNode(node *TreeNode) {
 
 	*next = node
 	node.parent = parent
+
+	// 更新插入路径上的子树节点数
+	for p := parent; p != nil; p = p.parent {
+		p.size++
+	}
+
+	t.augmentPath(node)
+
 	t.insertFixUp(node)
 	t.size++
 }
 
 // insertFixUp 插入节点后进行修复
-func (t *Tree) insertFixUp(node *TreeNode) {
+func (t *Tree[K, V]) insertFixUp(node *TreeNode[K, V]) {
 	for {
 		parent := node.parent
 		if parent == nil {
@@ -100,29 +133,34 @@ func (t *Tree) insertFixUp(node *TreeNode) {
 
 		case nIsLeft && pIsRight:
 			// 当前节点为左节点，父节点为右节点
-			grandfather.right = parent.rightRotate()
+			grandfather.right = t.rightRotate(parent)
 			node = parent
 
 		case nIsRight && pIsLeft:
 			// 当前节点为右节点，父节点为左节点
-			grandfather.left = parent.leftRotate()
+			grandfather.left = t.leftRotate(parent)
 			node = parent
 		}
 	}
 }
 
-// Delete 删除
-func (t *Tree) Delete(key interface{}) {
+// Delete 删除，multimap模式下删除key对应的所有节点
+func (t *Tree[K, V]) Delete(key K) {
+	if t.multi {
+		t.DeleteAll(key)
+		return
+	}
+
 	node := t.root
 
 	for !node.isSentinel() {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		if res == 0 {
 			t.deleteNode(node)
 			return
 		}
 
-		if res == utils.Lt {
+		if res < 0 {
 			node = node.right
 		} else {
 			node = node.left
@@ -131,8 +169,8 @@ func (t *Tree) Delete(key interface{}) {
 }
 
 // deleteNode 删除节点
-func (t *Tree) deleteNode(node *TreeNode) {
-	var children *TreeNode
+func (t *Tree[K, V]) deleteNode(node *TreeNode[K, V]) {
+	var children *TreeNode[K, V]
 
 	if !node.left.isSentinel() {
 		// 有左子节点，找到前驱节点
@@ -155,6 +193,13 @@ func (t *Tree) deleteNode(node *TreeNode) {
 	children.parent = parent
 	t.updateChildren(parent, children, node.isLeft())
 
+	// 更新删除路径上的子树节点数
+	for p := parent; p != nil; p = p.parent {
+		p.size--
+	}
+
+	t.augmentPath(parent)
+
 	if !node.isRed() {
 		// 删除节点不为红色
 		t.deleteFixUp(children)
@@ -165,7 +210,7 @@ func (t *Tree) deleteNode(node *TreeNode) {
 }
 
 // deleteFixUp 删除修复
-func (t *Tree) deleteFixUp(node *TreeNode) {
+func (t *Tree[K, V]) deleteFixUp(node *TreeNode[K, V]) {
 	for {
 		parent := node.parent
 		if parent == nil {
@@ -207,18 +252,27 @@ func (t *Tree) deleteFixUp(node *TreeNode) {
 	}
 }
 
-// Search 查找key指定的节点
-func (t *Tree) Search(key interface{}) *TreeNode {
-	return t.lookup(key)
+// Root 返回根节点，树为空时返回nil
+func (t *Tree[K, V]) Root() *TreeNode[K, V] {
+	if t.root.isSentinel() {
+		return nil
+	}
+
+	return t.root
+}
+
+// Search 查找key指定的节点，multimap模式下返回最早插入的节点
+func (t *Tree[K, V]) Search(key K) *TreeNode[K, V] {
+	return t.lookupFirst(key)
 }
 
 // SearchRange 查找key在[min, max]之间的节点
-func (t *Tree) SearchRange(min, max interface{}) []*TreeNode {
-	list := []*TreeNode{}
+func (t *Tree[K, V]) SearchRange(min, max K) []*TreeNode[K, V] {
+	list := []*TreeNode[K, V]{}
 
 	iter := NewIteratorWithNode(t, t.lookupLowerBoundKey(min))
 	for iter.Next() {
-		if t.comparator.Compare(iter.GetKey(), max) == utils.Gt {
+		if t.comparator(iter.GetKey(), max) > 0 {
 			break
 		}
 
@@ -229,9 +283,9 @@ func (t *Tree) SearchRange(min, max interface{}) []*TreeNode {
 }
 
 // SearchRangeLowerBoundKeyWithLimit 查找大于等于key的limit个节点
-func (t *Tree) SearchRangeLowerBoundKeyWithLimit(key interface{}, limit int64) []*TreeNode {
+func (t *Tree[K, V]) SearchRangeLowerBoundKeyWithLimit(key K, limit int64) []*TreeNode[K, V] {
 	var count int64
-	list := make([]*TreeNode, 0, limit)
+	list := make([]*TreeNode[K, V], 0, limit)
 
 	iter := NewIteratorWithNode(t, t.lookupLowerBoundKey(key))
 	for iter.Next() {
@@ -247,9 +301,9 @@ func (t *Tree) SearchRangeLowerBoundKeyWithLimit(key interface{}, limit int64) [
 }
 
 // SearchRangeUpperBoundKeyWithLimit 找到小于等于key的limit个节点
-func (t *Tree) SearchRangeUpperBoundKeyWithLimit(key interface{}, limit int64) []*TreeNode {
+func (t *Tree[K, V]) SearchRangeUpperBoundKeyWithLimit(key K, limit int64) []*TreeNode[K, V] {
 	var count int64
-	list := make([]*TreeNode, 0, limit)
+	list := make([]*TreeNode[K, V], 0, limit)
 
 	iter := NewIteratorWithNode(t, t.lookupUpperBoundKey(key))
 	for iter.Prev() {
@@ -264,8 +318,182 @@ func (t *Tree) SearchRangeUpperBoundKeyWithLimit(key interface{}, limit int64) [
 	return reverse(list)
 }
 
+// Put 插入key、value，key已存在时更新value，multimap模式下更新最早插入的value
+func (t *Tree[K, V]) Put(key K, val V) {
+	if t.multi {
+		if node := t.lookupFirst(key); node != nil {
+			node.entry.SetValue(val)
+			t.augmentPath(node)
+			return
+		}
+	}
+
+	t.Insert(key, val)
+}
+
+// Get 查找key对应的value，multimap模式下返回最早插入的value
+func (t *Tree[K, V]) Get(key K) (val V, bFound bool) {
+	_, val, bFound = t.lookupFirst(key).unpack()
+	return val, bFound
+}
+
+// Len 节点数
+func (t *Tree[K, V]) Len() int {
+	return t.size
+}
+
+// Min 最小的key和对应的value
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	return t.minimum().unpack()
+}
+
+// Max 最大的key和对应的value
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	return t.maximum().unpack()
+}
+
+// Floor 小于等于key的最大key和对应的value
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	return t.lookupUpperBoundKey(key).unpack()
+}
+
+// Ceiling 大于等于key的最小key和对应的value
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	return t.lookupLowerBoundKey(key).unpack()
+}
+
+// Lower 小于key的最大key和对应的value
+func (t *Tree[K, V]) Lower(key K) (K, V, bool) {
+	return t.lookupLess(key).unpack()
+}
+
+// Higher 大于key的最小key和对应的value
+func (t *Tree[K, V]) Higher(key K) (K, V, bool) {
+	return t.lookupGreater(key).unpack()
+}
+
+// PopMin 删除最小的key，并返回最小的key和对应的value
+func (t *Tree[K, V]) PopMin() (key K, val V, bFound bool) {
+	node := t.minimum()
+	if node == nil {
+		return key, val, false
+	}
+
+	key, val, bFound = node.unpack()
+	t.deleteNode(node)
+	return key, val, bFound
+}
+
+// PopMax 删除最大的key，并返回最大的key和对应的value
+func (t *Tree[K, V]) PopMax() (key K, val V, bFound bool) {
+	node := t.maximum()
+	if node == nil {
+		return key, val, false
+	}
+
+	key, val, bFound = node.unpack()
+	t.deleteNode(node)
+	return key, val, bFound
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的节点
+func (t *Tree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := t.lookupLowerBoundKey(lo); node != nil; node = node.next() {
+			if t.comparator(node.GetKey(), hi) > 0 {
+				return
+			}
+
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// All 按key升序遍历所有节点
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := t.minimum(); node != nil; node = node.next() {
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 按key降序遍历所有节点
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := t.maximum(); node != nil; node = node.prev() {
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// Iterator 从最小key开始的迭代器
+func (t *Tree[K, V]) Iterator() orderedmap.Iterator[K, V] {
+	return NewIterator(t)
+}
+
+// Select 第k小(k从0开始)的key和对应的value
+func (t *Tree[K, V]) Select(k int) (key K, val V, bFound bool) {
+	if k < 0 || k >= t.size {
+		return key, val, false
+	}
+
+	node := t.root
+	for {
+		lSize := node.left.size
+		switch {
+		case k < lSize:
+			node = node.left
+		case k == lSize:
+			return node.unpack()
+		default:
+			k -= lSize + 1
+			node = node.right
+		}
+	}
+}
+
+// Rank 小于key的节点数，key存在时即为key的排名(从0开始)
+func (t *Tree[K, V]) Rank(key K) int {
+	return t.countLess(key, false)
+}
+
+// CountRange key在[lo, hi]之间的节点数
+func (t *Tree[K, V]) CountRange(lo, hi K) int {
+	if t.comparator(lo, hi) > 0 {
+		return 0
+	}
+
+	return t.countLess(hi, true) - t.countLess(lo, false)
+}
+
+// countLess 小于key的节点数，orEqual为true时为小于等于key的节点数
+func (t *Tree[K, V]) countLess(key K, orEqual bool) int {
+	count := 0
+	node := t.root
+
+	for !node.isSentinel() {
+		res := t.comparator(node.GetKey(), key)
+		if res < 0 || (res == 0 && orEqual) {
+			// 左子树和当前节点都满足条件，继续在右子树查找
+			count += node.left.size + 1
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+
+	return count
+}
+
 // String String
-func (t *Tree) String() string {
+func (t *Tree[K, V]) String() string {
 	buffer := bytes.Buffer{}
 	node := t.root
 	stack := list.New()
@@ -277,7 +505,7 @@ func (t *Tree) String() string {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		node = e.(*TreeNode)
+		node = e.(*TreeNode[K, V])
 
 		if node.parent != nil {
 			buffer.WriteString(fmt.Sprintf("节点为: %v, \t节点的颜色为: %v, \t父节点为: %v\n", node.GetKey(), node.color, node.parent.GetKey()))
@@ -296,11 +524,11 @@ func (t *Tree) String() string {
 }
 
 // Verify 验证是否是一个红黑树
-func (t *Tree) Verify() bool {
+func (t *Tree[K, V]) Verify() bool {
 	node := t.root
 	stack := list.New()
-	keys := make([]interface{}, 0)
-	nodeColorMap := make(map[interface{}]*nodeColorStat)
+	keys := make([]K, 0)
+	nodeColorMap := make(map[*TreeNode[K, V]]*nodeColorStat)
 
 	// 将树的左节点放到栈中
 	for !node.isSentinel() {
@@ -311,10 +539,10 @@ func (t *Tree) Verify() bool {
 	// 从栈中弹出左节点
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		node = e.(*TreeNode)
+		node = e.(*TreeNode[K, V])
 
-		if colorInfo, ok := nodeColorMap[node.GetKey()]; !ok {
-			// 如果key不在nodeColorMap中，将他的key放到keys数组中，如果他的右节点不是哨兵节点，将其放到栈中
+		if colorInfo, ok := nodeColorMap[node]; !ok {
+			// 如果node不在nodeColorMap中，将他的key放到keys数组中，如果他的右节点不是哨兵节点，将其放到栈中
 			// 根节点为红色，返回false
 			if node.parent == nil && node.isRed() {
 				return false
@@ -325,7 +553,7 @@ func (t *Tree) Verify() bool {
 				return false
 			}
 
-			nodeColorMap[node.GetKey()] = &nodeColorStat{}
+			nodeColorMap[node] = &nodeColorStat{}
 			keys = append(keys, node.GetKey())
 			stack.PushBack(node)
 
@@ -336,16 +564,16 @@ func (t *Tree) Verify() bool {
 				node = node.left
 			}
 		} else {
-			// 如果key存在nodeColorMap中，更新LeftBlackCount、RightBlackCount、BlackCount
+			// 如果node存在nodeColorMap中，更新LeftBlackCount、RightBlackCount、BlackCount
 			if node.left.isSentinel() {
 				// 左节点是哨兵节点，LeftBlackCount = 1
 				colorInfo.leftBlackCount = 1
 			} else if node.left.isRed() {
 				// 左节点是红色节点，LeftBlackCount = 左节点的BlackCount
-				colorInfo.leftBlackCount = nodeColorMap[node.left.GetKey()].blackCount
+				colorInfo.leftBlackCount = nodeColorMap[node.left].blackCount
 			} else {
 				// LeftBlackCount = 左节点的BlackCount + 1
-				colorInfo.leftBlackCount = nodeColorMap[node.left.GetKey()].blackCount + 1
+				colorInfo.leftBlackCount = nodeColorMap[node.left].blackCount + 1
 			}
 
 			if node.right.isSentinel() {
@@ -353,10 +581,10 @@ func (t *Tree) Verify() bool {
 				colorInfo.rightBlackCount = 1
 			} else if node.right.isRed() {
 				// 右节点是红色节点，RightBlackCount = 右节点的BlackCount
-				colorInfo.rightBlackCount = nodeColorMap[node.right.GetKey()].blackCount
+				colorInfo.rightBlackCount = nodeColorMap[node.right].blackCount
 			} else {
 				// RightBlackCount = 右节点的BlackCount + 1
-				colorInfo.rightBlackCount = nodeColorMap[node.right.GetKey()].blackCount + 1
+				colorInfo.rightBlackCount = nodeColorMap[node.right].blackCount + 1
 			}
 
 			// 左右子节点的黑色数不等，返回false
@@ -365,12 +593,21 @@ func (t *Tree) Verify() bool {
 			}
 
 			colorInfo.blackCount = colorInfo.leftBlackCount
+
+			// 子树节点数不等于左右子树节点数之和加1，返回false
+			if node.size != node.left.size+node.right.size+1 {
+				return false
+			}
 		}
 	}
 
-	// 验证顺序
+	if t.root.size != t.size {
+		return false
+	}
+
+	// 验证顺序，multimap模式下允许相同的key
 	for i := 0; i < len(keys)-1; i++ {
-		if t.comparator.Compare(keys[i], keys[i+1]) == utils.Gt {
+		if res := t.comparator(keys[i], keys[i+1]); res > 0 || (res == 0 && !t.multi) {
 			return false
 		}
 	}
@@ -379,7 +616,7 @@ func (t *Tree) Verify() bool {
 }
 
 // Dot Dot
-func (t *Tree) Dot() error {
+func (t *Tree[K, V]) Dot() error {
 	node := t.root
 	if node.isSentinel() {
 		return nil
@@ -398,7 +635,7 @@ func (t *Tree) Dot() error {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		node = e.(*TreeNode)
+		node = e.(*TreeNode[K, V])
 
 		dNode, dEdge := node.dot()
 		dGraph.AddNode(dNode)
@@ -438,12 +675,12 @@ func (t *Tree) Dot() error {
 }
 
 // caseRoot 修复节点为根节点
-func (t *Tree) caseRoot() {
+func (t *Tree[K, V]) caseRoot() {
 	t.root.color = BLACK
 }
 
 // iCaseUncleIsRed 插入修复: 修复节点的叔叔节点为红色
-func (t *Tree) iCaseUncleIsRed(parent, uncle *TreeNode) *TreeNode {
+func (t *Tree[K, V]) iCaseUncleIsRed(parent, uncle *TreeNode[K, V]) *TreeNode[K, V] {
 	parent.color = BLACK
 	uncle.color = BLACK
 	parent.parent.color = RED
@@ -451,39 +688,39 @@ func (t *Tree) iCaseUncleIsRed(parent, uncle *TreeNode) *TreeNode {
 }
 
 // iCaseNodeAndParentIsLeft 插入修复: 修复节点 和 修复节点的父节点 都是左节点
-func (t *Tree) iCaseNodeAndParentIsLeft(parent *TreeNode) {
+func (t *Tree[K, V]) iCaseNodeAndParentIsLeft(parent *TreeNode[K, V]) {
 	grandfather := parent.parent
 	greatGrandfather := grandfather.parent
 
 	parent.color = BLACK
 	isLeft := grandfather.isLeft()
-	ggfChildren := grandfather.rightRotate()
+	ggfChildren := t.rightRotate(grandfather)
 
 	t.updateChildren(greatGrandfather, ggfChildren, isLeft)
 	parent.right.color = RED
 }
 
 // iCaseNodeAndParentIsRight 插入修复: 修复节点 和 修复节点的父节点 都是右节点
-func (t *Tree) iCaseNodeAndParentIsRight(parent *TreeNode) {
+func (t *Tree[K, V]) iCaseNodeAndParentIsRight(parent *TreeNode[K, V]) {
 	grandfather := parent.parent
 	greatGrandfather := grandfather.parent
 
 	parent.color = BLACK
 	isLeft := grandfather.isLeft()
-	ggfChildren := grandfather.leftRotate()
+	ggfChildren := t.leftRotate(grandfather)
 
 	t.updateChildren(greatGrandfather, ggfChildren, isLeft)
 	parent.left.color = RED
 }
 
 // dCaseNodeIsRed 删除修复: 修复节点是红色
-func (t *Tree) dCaseNodeIsRed(node *TreeNode) {
+func (t *Tree[K, V]) dCaseNodeIsRed(node *TreeNode[K, V]) {
 	node.color = BLACK
 }
 
 // dCaseBrotherIsRed 删除修复: 修复节点的兄弟节点是红色
-func (t *Tree) dCaseBrotherIsRed(node, brother, parent *TreeNode) {
-	var children *TreeNode
+func (t *Tree[K, V]) dCaseBrotherIsRed(node, brother, parent *TreeNode[K, V]) {
+	var children *TreeNode[K, V]
 	brother.color = BLACK
 	parent.color = RED
 	grandfather := parent.parent
@@ -491,18 +728,18 @@ func (t *Tree) dCaseBrotherIsRed(node, brother, parent *TreeNode) {
 
 	if node.isLeft() {
 		// 修复节点是左节点
-		children = parent.leftRotate()
+		children = t.leftRotate(parent)
 	} else {
 		// 修复节点是右节点
-		children = parent.rightRotate()
+		children = t.rightRotate(parent)
 	}
 
 	t.updateChildren(grandfather, children, pIsLeft)
 }
 
 // dCaseBrotherLeftIsRedAndBrotherRightIsBlack 删除修复: 修复节点的兄弟节点的左节点是红色，修复节点的兄弟节点的右节点是黑色
-func (t *Tree) dCaseBrotherLeftIsRedAndBrotherRightIsBlack(node, brother, parent *TreeNode) {
-	var children *TreeNode
+func (t *Tree[K, V]) dCaseBrotherLeftIsRedAndBrotherRightIsBlack(node, brother, parent *TreeNode[K, V]) {
+	var children *TreeNode[K, V]
 	pIsLeft := parent.isLeft()
 	grandfather := parent.parent
 
@@ -510,22 +747,22 @@ func (t *Tree) dCaseBrotherLeftIsRedAndBrotherRightIsBlack(node, brother, parent
 		// 修复节点是左节点
 		brother.left.color = parent.color
 		parent.color = BLACK
-		parent.right = brother.rightRotate()
-		children = parent.leftRotate()
+		parent.right = t.rightRotate(brother)
+		children = t.leftRotate(parent)
 	} else {
 		// 修复节点是右节点
 		brother.color = parent.color
 		parent.color = BLACK
 		brother.left.color = BLACK
-		children = parent.rightRotate()
+		children = t.rightRotate(parent)
 	}
 
 	t.updateChildren(grandfather, children, pIsLeft)
 }
 
 // dCaseBrotherRightIsRed 删除修复: 修复节点的兄弟节点的右节点是红色
-func (t *Tree) dCaseBrotherRightIsRed(node, brother, parent *TreeNode) {
-	var children *TreeNode
+func (t *Tree[K, V]) dCaseBrotherRightIsRed(node, brother, parent *TreeNode[K, V]) {
+	var children *TreeNode[K, V]
 	pIsLeft := parent.isLeft()
 	grandfather := parent.parent
 
@@ -534,20 +771,20 @@ func (t *Tree) dCaseBrotherRightIsRed(node, brother, parent *TreeNode) {
 		brother.color = parent.color
 		parent.color = BLACK
 		brother.right.color = BLACK
-		children = parent.leftRotate()
+		children = t.leftRotate(parent)
 	} else {
 		// 修复节点是右节点
 		brother.right.color = parent.color
 		parent.color = BLACK
-		parent.left = brother.leftRotate()
-		children = parent.rightRotate()
+		parent.left = t.leftRotate(brother)
+		children = t.rightRotate(parent)
 	}
 
 	t.updateChildren(grandfather, children, pIsLeft)
 }
 
 // updateChild 更新父节点的子节点
-func (t *Tree) updateChildren(parent, children *TreeNode, isLeft bool) {
+func (t *Tree[K, V]) updateChildren(parent, children *TreeNode[K, V], isLeft bool) {
 	if parent == nil {
 		t.root = children
 	} else if isLeft {
@@ -557,27 +794,63 @@ func (t *Tree) updateChildren(parent, children *TreeNode, isLeft bool) {
 	}
 }
 
+// leftRotate 左旋，并更新旋转后节点的聚合值
+func (t *Tree[K, V]) leftRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	r := node.leftRotate()
+	t.augment(node)
+	t.augment(r)
+
+	return r
+}
+
+// rightRotate 右旋，并更新旋转后节点的聚合值
+func (t *Tree[K, V]) rightRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	l := node.rightRotate()
+	t.augment(node)
+	t.augment(l)
+
+	return l
+}
+
+// augment 重新计算node的聚合值
+func (t *Tree[K, V]) augment(node *TreeNode[K, V]) {
+	if t.augmenter != nil {
+		t.augmenter.update(node)
+	}
+}
+
+// augmentPath 重新计算从node到根节点路径上的聚合值
+func (t *Tree[K, V]) augmentPath(node *TreeNode[K, V]) {
+	if t.augmenter == nil {
+		return
+	}
+
+	for ; node != nil; node = node.parent {
+		t.augmenter.update(node)
+	}
+}
+
 // minimum 中序遍历后，树的最小节点
-func (t *Tree) minimum() *TreeNode {
+func (t *Tree[K, V]) minimum() *TreeNode[K, V] {
 	return t.root.minimum()
 }
 
 // maximum 中序遍历后，树的最大节点
-func (t *Tree) maximum() *TreeNode {
+func (t *Tree[K, V]) maximum() *TreeNode[K, V] {
 	return t.root.maximum()
 }
 
 // lookup 查找key所在的节点
-func (t *Tree) lookup(key interface{}) *TreeNode {
+func (t *Tree[K, V]) lookup(key K) *TreeNode[K, V] {
 	node := t.root
 
 	for !node.isSentinel() {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		if res == 0 {
 			return node
 		}
 
-		if res == utils.Lt {
+		if res < 0 {
 			node = node.right
 		} else {
 			node = node.left
@@ -587,18 +860,23 @@ func (t *Tree) lookup(key interface{}) *TreeNode {
 	return nil
 }
 
-// lookupLowerBoundKey 查找第一个大于等于key的node
-func (t *Tree) lookupLowerBoundKey(key interface{}) *TreeNode {
-	var last *TreeNode
+// lookupLowerBoundKey 查找第一个大于等于key的node，multimap模式下key相同时为最早插入的node
+func (t *Tree[K, V]) lookupLowerBoundKey(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
 	node := t.root
 
+	if node.isSentinel() {
+		return nil
+	}
+
 	for {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		// multimap模式下key相同时继续在左子树中查找最早插入的节点
+		if res == 0 && !t.multi {
 			return node
 		}
 
-		if res == utils.Lt {
+		if res < 0 {
 			if node.right.isSentinel() {
 				return last
 			}
@@ -615,18 +893,23 @@ func (t *Tree) lookupLowerBoundKey(key interface{}) *TreeNode {
 	}
 }
 
-// lookupUpperBoundKey 最后一个小于等于key的node
-func (t *Tree) lookupUpperBoundKey(key interface{}) *TreeNode {
-	var last *TreeNode
+// lookupUpperBoundKey 最后一个小于等于key的node，multimap模式下key相同时为最后插入的node
+func (t *Tree[K, V]) lookupUpperBoundKey(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
 	node := t.root
 
+	if node.isSentinel() {
+		return nil
+	}
+
 	for {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		// multimap模式下key相同时继续在右子树中查找最后插入的节点
+		if res == 0 && !t.multi {
 			return node
 		}
 
-		if res == utils.Lt {
+		if res <= 0 {
 			if node.right.isSentinel() {
 				return node
 			}
@@ -642,3 +925,37 @@ func (t *Tree) lookupUpperBoundKey(key interface{}) *TreeNode {
 		}
 	}
 }
+
+// lookupLess 最后一个小于key的node
+func (t *Tree[K, V]) lookupLess(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
+	node := t.root
+
+	for !node.isSentinel() {
+		if t.comparator(node.GetKey(), key) < 0 {
+			last = node
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+
+	return last
+}
+
+// lookupGreater 第一个大于key的node
+func (t *Tree[K, V]) lookupGreater(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
+	node := t.root
+
+	for !node.isSentinel() {
+		if t.comparator(node.GetKey(), key) > 0 {
+			last = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+
+	return last
+}
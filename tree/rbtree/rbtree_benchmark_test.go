@@ -4,10 +4,25 @@ import (
 	"math/rand"
 	"testing"
 	"time"
+
+	"github.com/asinglestep/gods/utils"
 )
 
 func Benchmark_RbTreeRandInsert(b *testing.B) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
+	var num = 10000000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+
+	for i := 0; i < b.N; i++ {
+		for _, v := range array {
+			tree.Insert(v, v)
+		}
+	}
+}
+
+func Benchmark_RbTreeOrderedRandInsert(b *testing.B) {
+	tree := NewOrderedTree[int, int]()
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -20,7 +35,7 @@ func Benchmark_RbTreeRandInsert(b *testing.B) {
 }
 
 func Benchmark_RbTreeRandDelete(b *testing.B) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -37,7 +52,7 @@ func Benchmark_RbTreeRandDelete(b *testing.B) {
 }
 
 func Benchmark_RbTreeRandSearchRangeLowerBoundKeyWithLimit(b *testing.B) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -49,3 +64,16 @@ func Benchmark_RbTreeRandSearchRangeLowerBoundKeyWithLimit(b *testing.B) {
 	sKey := rand.New(rand.NewSource(time.Now().UnixNano())).Intn(num)
 	tree.SearchRangeLowerBoundKeyWithLimit(sKey, 1000)
 }
+
+func Benchmark_RbTreeSelect(b *testing.B) {
+	tree := NewOrderedTree[int, int]()
+	num := 1000000
+	for _, v := range rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num) {
+		tree.Insert(v, v)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Select(i % num)
+	}
+}
@@ -27,43 +27,66 @@ func (c Color) String() string {
 }
 
 // TreeNode 红黑树节点
-type TreeNode struct {
+type TreeNode[K, V any] struct {
 	color  Color
-	entry  *utils.Entry
-	left   *TreeNode
-	right  *TreeNode
-	parent *TreeNode
+	entry  *utils.TypedEntry[K, V]
+	left   *TreeNode[K, V]
+	right  *TreeNode[K, V]
+	parent *TreeNode[K, V]
+	size   int // 以当前节点为根节点的子树的节点数，哨兵节点为0
+	agg    any // 以当前节点为根节点的子树的聚合值，没有注册聚合值时为nil
 }
 
-// Sentinel Sentinel
-var Sentinel = &TreeNode{
-	color: BLACK,
-	entry: nil,
+// newSentinel 新建一个哨兵节点
+func newSentinel[K, V any]() *TreeNode[K, V] {
+	return &TreeNode[K, V]{
+		color: BLACK,
+		entry: nil,
+	}
 }
 
-// NewTreeNode 新建一个节点
-func NewTreeNode(entry *utils.Entry) *TreeNode {
-	node := &TreeNode{}
+// NewTreeNode 新建一个节点，sentinel为节点所在树的哨兵节点
+func NewTreeNode[K, V any](entry *utils.TypedEntry[K, V], sentinel *TreeNode[K, V]) *TreeNode[K, V] {
+	node := &TreeNode[K, V]{}
 	node.color = RED
 	node.entry = entry
-	node.left = Sentinel
-	node.right = Sentinel
+	node.left = sentinel
+	node.right = sentinel
+	node.size = 1
 
 	return node
 }
 
 // GetKey 获取key
-func (node *TreeNode) GetKey() interface{} {
+func (node *TreeNode[K, V]) GetKey() K {
 	return node.entry.GetKey()
 }
 
 // GetValue 获取value
-func (node *TreeNode) GetValue() interface{} {
+func (node *TreeNode[K, V]) GetValue() V {
 	return node.entry.GetValue()
 }
 
+// Left 返回左子节点，没有左子节点时返回nil
+func (node *TreeNode[K, V]) Left() *TreeNode[K, V] {
+	if node.left.isSentinel() {
+		return nil
+	}
+
+	return node.left
+}
+
+// Right 返回右子节点，没有右子节点时返回nil
+func (node *TreeNode[K, V]) Right() *TreeNode[K, V] {
+	if node.right.isSentinel() {
+		return nil
+	}
+
+	return node.right
+}
+
 // leftRotate 左旋
-func (node *TreeNode) leftRotate() *TreeNode {
+func (node *TreeNode[K, V]) leftRotate() *TreeNode[K, V] {
 	r := node.right
 	rl := r.left
 
@@ -77,11 +100,14 @@ func (node *TreeNode) leftRotate() *TreeNode {
 		rl.parent = node
 	}
 
+	r.size = node.size
+	node.updateSize()
+
 	return r
 }
 
 // rightRotate 右旋
-func (node *TreeNode) rightRotate() *TreeNode {
+func (node *TreeNode[K, V]) rightRotate() *TreeNode[K, V] {
 	l := node.left
 	lr := l.right
 
@@ -95,11 +121,19 @@ func (node *TreeNode) rightRotate() *TreeNode {
 		lr.parent = node
 	}
 
+	l.size = node.size
+	node.updateSize()
+
 	return l
 }
 
+// updateSize 根据左右子节点更新子树的节点数
+func (node *TreeNode[K, V]) updateSize() {
+	node.size = node.left.size + node.right.size + 1
+}
+
 // isBlack 是否是黑色节点
-func (node *TreeNode) isBlack() bool {
+func (node *TreeNode[K, V]) isBlack() bool {
 	if node.isSentinel() {
 		return true
 	}
@@ -108,7 +142,7 @@ func (node *TreeNode) isBlack() bool {
 }
 
 // isRed 是否是红色节点
-func (node *TreeNode) isRed() bool {
+func (node *TreeNode[K, V]) isRed() bool {
 	if node.isSentinel() {
 		return false
 	}
@@ -117,7 +151,7 @@ func (node *TreeNode) isRed() bool {
 }
 
 // isLeft 是否是左节点
-func (node *TreeNode) isLeft() bool {
+func (node *TreeNode[K, V]) isLeft() bool {
 	// node为根节点
 	if node.parent == nil {
 		return false
@@ -127,7 +161,7 @@ func (node *TreeNode) isLeft() bool {
 }
 
 // isRight 是否是右节点
-func (node *TreeNode) isRight() bool {
+func (node *TreeNode[K, V]) isRight() bool {
 	// node为根节点
 	if node.parent == nil {
 		return false
@@ -137,7 +171,7 @@ func (node *TreeNode) isRight() bool {
 }
 
 // findSuccessor 找到node的后继节点
-func (node *TreeNode) findSuccessor() *TreeNode {
+func (node *TreeNode[K, V]) findSuccessor() *TreeNode[K, V] {
 	node = node.right
 
 	for {
@@ -150,7 +184,7 @@ func (node *TreeNode) findSuccessor() *TreeNode {
 }
 
 // findPrecursor 找到node的前驱节点
-func (node *TreeNode) findPrecursor() *TreeNode {
+func (node *TreeNode[K, V]) findPrecursor() *TreeNode[K, V] {
 	node = node.left
 
 	for {
@@ -163,12 +197,12 @@ func (node *TreeNode) findPrecursor() *TreeNode {
 }
 
 // isSentinel 是否是哨兵节点
-func (node *TreeNode) isSentinel() bool {
-	return node == Sentinel
+func (node *TreeNode[K, V]) isSentinel() bool {
+	return node.entry == nil
 }
 
 // getBrother 获取兄弟节点
-func (node *TreeNode) getBrother() *TreeNode {
+func (node *TreeNode[K, V]) getBrother() *TreeNode[K, V] {
 	if node.isRight() {
 		return node.parent.left
 	}
@@ -177,15 +211,24 @@ func (node *TreeNode) getBrother() *TreeNode {
 }
 
 // free free
-func (node *TreeNode) free() {
+func (node *TreeNode[K, V]) free() {
 	node.parent = nil
 	node.left = nil
 	node.right = nil
 	node.entry = nil
 }
 
+// unpack 获取节点的key和value，node为nil时返回false
+func (node *TreeNode[K, V]) unpack() (key K, val V, bFound bool) {
+	if node == nil {
+		return key, val, false
+	}
+
+	return node.GetKey(), node.GetValue(), true
+}
+
 // minimum 以当前节点为根节点，中序遍历后，树的最小节点
-func (node *TreeNode) minimum() *TreeNode {
+func (node *TreeNode[K, V]) minimum() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -198,7 +241,7 @@ func (node *TreeNode) minimum() *TreeNode {
 }
 
 // maximum 以当前节点为根节点，中序遍历后，树的最大节点
-func (node *TreeNode) maximum() *TreeNode {
+func (node *TreeNode[K, V]) maximum() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -211,7 +254,7 @@ func (node *TreeNode) maximum() *TreeNode {
 }
 
 // next 中序遍历node的下一个节点
-func (node *TreeNode) next() *TreeNode {
+func (node *TreeNode[K, V]) next() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -231,7 +274,7 @@ func (node *TreeNode) next() *TreeNode {
 }
 
 // prev 中序遍历node的上一个节点
-func (node *TreeNode) prev() *TreeNode {
+func (node *TreeNode[K, V]) prev() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -251,7 +294,7 @@ func (node *TreeNode) prev() *TreeNode {
 }
 
 // dot dot
-func (node *TreeNode) dot() (dNode *dot.Node, dEdge *dot.Edge) {
+func (node *TreeNode[K, V]) dot() (dNode *dot.Node, dEdge *dot.Edge) {
 	color := "#FF0000"
 	if node.isBlack() {
 		color = "#0F0F0F"
@@ -259,10 +302,10 @@ func (node *TreeNode) dot() (dNode *dot.Node, dEdge *dot.Edge) {
 
 	// 添加node
 	dNode = &dot.Node{}
-	dNode.Name = fmt.Sprintf("%d", node.GetKey())
+	dNode.Name = fmt.Sprintf("%v", node.GetKey())
 
 	dNode.Attr = map[string]string{
-		"label":     fmt.Sprintf("\"<f0> | %d | <f1> \"", node.GetKey()),
+		"label":     fmt.Sprintf("\"<f0> | %v | <f1> \"", node.GetKey()),
 		"fillcolor": "\"" + color + "\"",
 		"style":     "filled",
 		"fontcolor": "\"#FFFFFF\"",
@@ -272,7 +315,7 @@ func (node *TreeNode) dot() (dNode *dot.Node, dEdge *dot.Edge) {
 	// 添加edge
 	if node.parent != nil {
 		dEdge = &dot.Edge{}
-		dEdge.Src = fmt.Sprintf("%d", node.parent.GetKey())
+		dEdge.Src = fmt.Sprintf("%v", node.parent.GetKey())
 
 		if node.isLeft() {
 			dEdge.SrcPort = ":f0"
@@ -280,7 +323,7 @@ func (node *TreeNode) dot() (dNode *dot.Node, dEdge *dot.Edge) {
 			dEdge.SrcPort = ":f1"
 		}
 
-		dEdge.Dst = fmt.Sprintf("%d", node.GetKey())
+		dEdge.Dst = fmt.Sprintf("%v", node.GetKey())
 	}
 
 	return dNode, dEdge
@@ -294,7 +337,7 @@ type nodeColorStat struct {
 }
 
 // reverse 倒序
-func reverse(list []*TreeNode) []*TreeNode {
+func reverse[K, V any](list []*TreeNode[K, V]) []*TreeNode[K, V] {
 	listLen := len(list)
 
 	for i := 0; i < listLen/2; i++ {
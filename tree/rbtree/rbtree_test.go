@@ -1,33 +1,23 @@
 package rbtree
 
 import (
+	"cmp"
 	"fmt"
+	"iter"
 	"math/rand"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
-)
-
-type rbtreeComparator struct {
-}
-
-// Compare Compare
-func (tc rbtreeComparator) Compare(k1, k2 interface{}) int {
-	i1 := k1.(int)
-	i2 := k2.(int)
-
-	if i1 > i2 {
-		return 1
-	}
-
-	if i1 < i2 {
-		return -1
-	}
 
-	return 0
-}
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/map/orderedmap/conformance"
+	"github.com/asinglestep/gods/utils"
+)
 
 func Test_RbTreeInsert(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	fmt.Println("插入3")
 	tree.Insert(3, 3)
 	fmt.Println(tree)
@@ -81,7 +71,7 @@ func Test_RbTreeInsert(t *testing.T) {
 }
 
 func Test_RbTreeRandInsert(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -112,7 +102,7 @@ func Test_RbTreeRandInsert(t *testing.T) {
 }
 
 func Test_RbTreeDelete(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	tree.Insert(3, 3)
 	tree.Insert(2, 2)
 	tree.Insert(1, 1)
@@ -127,7 +117,7 @@ func Test_RbTreeDelete(t *testing.T) {
 }
 
 func Test_RbTreeRandDelete(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -175,7 +165,7 @@ func Test_RbTreeRandDelete(t *testing.T) {
 }
 
 func Test_RbTreeSearch(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(3, 3)
 	tree.Insert(2, 2)
@@ -199,7 +189,7 @@ func Test_RbTreeSearch(t *testing.T) {
 }
 
 func Test_RbTreeSearchRange(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(3, 3)
 	tree.Insert(2, 2)
@@ -230,7 +220,7 @@ func Test_RbTreeSearchRange(t *testing.T) {
 }
 
 func Test_RbTreeSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(3, 3)
 	tree.Insert(2, 2)
@@ -260,7 +250,7 @@ func Test_RbTreeSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_RbTreeRandSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -287,7 +277,7 @@ func Test_RbTreeRandSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_RbTreeSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(3, 3)
 	tree.Insert(2, 2)
@@ -317,7 +307,7 @@ func Test_RbTreeSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_RbTreeRandSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(rbtreeComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -345,3 +335,528 @@ func Test_RbTreeRandSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
 		}
 	}
 }
+
+func Test_RbTreeOrderedTree(t *testing.T) {
+	tree := NewOrderedTree[int, string]()
+	var num = 100000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+	for _, v := range array {
+		tree.Insert(v, strconv.Itoa(v))
+	}
+
+	dArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num / 2)
+	for _, v := range dArray {
+		tree.Delete(v)
+	}
+
+	if !tree.Verify() {
+		t.Fatal("Test_RbTreeOrderedTree err")
+	}
+
+	idx := num / 2
+	iter := NewIterator(tree)
+	for iter.Next() {
+		if iter.GetKey() != idx || iter.GetValue() != strconv.Itoa(idx) {
+			t.Fatalf("want %v, got %v: %v\n", idx, iter.GetKey(), iter.GetValue())
+		}
+
+		idx++
+	}
+
+	if idx != num {
+		t.Fatalf("want %v, got %v\n", num, idx)
+	}
+}
+
+func Test_RbTreeConformance(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewOrderedTree[int, int]()
+	})
+}
+
+func Test_RbTreeSelectRank(t *testing.T) {
+	tree := NewOrderedTree[int, int]()
+	num := 10000
+
+	// 插入偶数key
+	iArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+	for _, v := range iArray {
+		tree.Insert(v*2, v)
+	}
+
+	// 删除一半
+	dArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num / 2)
+	for _, v := range dArray {
+		tree.Delete(v * 2)
+	}
+
+	if !tree.Verify() {
+		t.Fatalf("RbTree Verify Error\n")
+	}
+
+	keys := make([]int, 0, num/2)
+	for i := num / 2; i < num; i++ {
+		keys = append(keys, i*2)
+	}
+
+	for i, k := range keys {
+		key, val, ok := tree.Select(i)
+		if !ok || key != k || val != k/2 {
+			t.Fatalf("Select(%v) want %v, got %v %v\n", i, k, key, ok)
+		}
+
+		if rank := tree.Rank(k); rank != i {
+			t.Fatalf("Rank(%v) want %v, got %v\n", k, i, rank)
+		}
+
+		if rank := tree.Rank(k + 1); rank != i+1 {
+			t.Fatalf("Rank(%v) want %v, got %v\n", k+1, i+1, rank)
+		}
+	}
+
+	if _, _, ok := tree.Select(-1); ok {
+		t.Fatalf("Select(-1) found\n")
+	}
+
+	if _, _, ok := tree.Select(len(keys)); ok {
+		t.Fatalf("Select(%v) found\n", len(keys))
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < 1000; i++ {
+		lo := r.Intn(num*2+2) - 1
+		hi := r.Intn(num*2+2) - 1
+
+		want := 0
+		for _, k := range keys {
+			if k >= lo && k <= hi {
+				want++
+			}
+		}
+
+		if got := tree.CountRange(lo, hi); got != want {
+			t.Fatalf("CountRange(%v, %v) want %v, got %v\n", lo, hi, want, got)
+		}
+	}
+}
+
+// sumAgg 测试用的聚合值，first为区间中最小的key，用来检查合并的顺序
+type sumAgg struct {
+	sum   int
+	cnt   int
+	first int
+}
+
+func Test_RbTreeAugment(t *testing.T) {
+	tree := NewOrderedTree[int, int]()
+	num := 2000
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	// 注册前插入的节点
+	m := make(map[int]int)
+	for _, v := range r.Perm(num)[:num/4] {
+		tree.Insert(v, v)
+		m[v] = v
+	}
+
+	aug := Augment(tree, Augmentation[int, int, sumAgg]{
+		Value: func(key, val int) sumAgg {
+			return sumAgg{sum: val, cnt: 1, first: key}
+		},
+		Combine: func(a, b sumAgg) sumAgg {
+			first := a.first
+			if a.cnt == 0 {
+				first = b.first
+			}
+
+			return sumAgg{sum: a.sum + b.sum, cnt: a.cnt + b.cnt, first: first}
+		},
+	})
+
+	for i := 0; i < num*4; i++ {
+		k := r.Intn(num)
+		if r.Intn(3) == 0 {
+			tree.Delete(k)
+			delete(m, k)
+		} else {
+			tree.Insert(k, i)
+			m[k] = i
+		}
+	}
+
+	if !tree.Verify() {
+		t.Fatalf("Verify Error\n")
+	}
+
+	for i := 0; i < 1000; i++ {
+		lo := r.Intn(num+2) - 1
+		hi := r.Intn(num+2) - 1
+
+		want := sumAgg{}
+		for k := hi; k >= lo; k-- {
+			if v, ok := m[k]; ok {
+				want.sum += v
+				want.cnt++
+				want.first = k
+			}
+		}
+
+		if got := aug.Query(lo, hi); got != want {
+			t.Fatalf("Query(%v, %v) want %+v, got %+v\n", lo, hi, want, got)
+		}
+	}
+
+	if got := aug.Total(); got.cnt != len(m) {
+		t.Fatalf("Total want %v, got %+v\n", len(m), got)
+	}
+
+	if got := aug.Aggregate(nil); got != (sumAgg{}) {
+		t.Fatalf("Aggregate(nil) want Identity, got %+v\n", got)
+	}
+}
+
+func Test_RbTreeConcurrentTrees(t *testing.T) {
+	// 每棵树有自己的哨兵节点，不同goroutine中操作不同的树不会产生数据竞争，使用go test -race验证
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+
+			tree := NewOrderedTree[int, int]()
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 2000; j++ {
+				tree.Insert(r.Intn(500), j)
+				tree.Delete(r.Intn(500))
+			}
+
+			if !tree.Verify() {
+				t.Errorf("seed %v: Verify Error\n", seed)
+			}
+		}(int64(i))
+	}
+
+	wg.Wait()
+}
+
+func Test_SyncRbTreeConformance(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewSyncTree(NewOrderedTree[int, int]())
+	})
+}
+
+func Test_SyncRbTreeConcurrent(t *testing.T) {
+	conformance.RunConcurrent(t, NewSyncTree(NewOrderedTree[int, int]()))
+}
+
+func Test_SyncRbTreeSearch(t *testing.T) {
+	tree := NewSyncTree(NewOrderedTree[int, int]())
+	for i := 0; i < 100; i++ {
+		tree.Insert(i*2, i)
+	}
+
+	if found := tree.Search(20); found == nil || found.GetKey() != 20 || found.GetValue() != 10 {
+		t.Fatalf("Search(20) want 20: 10, got %v\n", found)
+	}
+
+	if found := tree.Search(21); found != nil {
+		t.Fatalf("Search(21) want nil, got %v\n", found)
+	}
+
+	keys := func(list []*TreeNode[int, int]) []int {
+		res := []int{}
+		for _, e := range list {
+			res = append(res, e.GetKey())
+		}
+
+		return res
+	}
+
+	if got := keys(tree.SearchRange(9, 20)); !slices.Equal(got, []int{10, 12, 14, 16, 18, 20}) {
+		t.Fatalf("SearchRange(9, 20) got %v\n", got)
+	}
+
+	if got := keys(tree.SearchRangeLowerBoundKeyWithLimit(195, 5)); !slices.Equal(got, []int{196, 198}) {
+		t.Fatalf("SearchRangeLowerBoundKeyWithLimit(195, 5) got %v\n", got)
+	}
+
+	if got := keys(tree.SearchRangeUpperBoundKeyWithLimit(5, 5)); !slices.Equal(got, []int{0, 2, 4}) {
+		t.Fatalf("SearchRangeUpperBoundKeyWithLimit(5, 5) got %v\n", got)
+	}
+
+	// 返回的节点是拷贝，不在树中
+	if found := tree.Search(20); found.Left() != nil || found.Right() != nil {
+		t.Fatalf("Search(20) want a detached node\n")
+	}
+
+	// 读取返回的拷贝时不持有锁，同时修改树不会产生数据竞争，使用go test -race验证
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 2000; i++ {
+			tree.Put(i%200, -i)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		for _, e := range tree.SearchRange(0, 200) {
+			_ = e.GetValue()
+		}
+	}
+
+	wg.Wait()
+}
+
+func Test_SyncRbTreeSelectRank(t *testing.T) {
+	tree := NewSyncTree(NewOrderedTree[int, int]())
+	for i := 0; i < 100; i++ {
+		tree.Insert(i*2, i)
+	}
+
+	if k, v, ok := tree.Select(10); !ok || k != 20 || v != 10 {
+		t.Fatalf("Select(10) want 20, got %v %v\n", k, ok)
+	}
+
+	if rank := tree.Rank(21); rank != 11 {
+		t.Fatalf("Rank(21) want 11, got %v\n", rank)
+	}
+
+	if count := tree.CountRange(10, 20); count != 6 {
+		t.Fatalf("CountRange(10, 20) want 6, got %v\n", count)
+	}
+}
+
+func Test_RbTreeMultimap(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	tree := NewOrderedTree[int, int](WithMultimap())
+	want := map[int][]int{}
+	size := 0
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(100)
+
+		switch op := r.Intn(10); {
+		case op < 6:
+			tree.Insert(key, i)
+			want[key] = append(want[key], i)
+			size++
+		case op < 9:
+			if tree.DeleteOne(key) != (len(want[key]) > 0) {
+				t.Fatalf("seed %v: DeleteOne(%v) want %v\n", seed, key, len(want[key]) > 0)
+			}
+
+			if len(want[key]) > 0 {
+				want[key] = want[key][1:]
+				size--
+			}
+		default:
+			if n := tree.DeleteAll(key); n != len(want[key]) {
+				t.Fatalf("seed %v: DeleteAll(%v) want %v, got %v\n", seed, key, len(want[key]), n)
+			}
+
+			size -= len(want[key])
+			delete(want, key)
+		}
+
+		if tree.Count(key) != len(want[key]) {
+			t.Fatalf("seed %v: Count(%v) want %v, got %v\n", seed, key, len(want[key]), tree.Count(key))
+		}
+
+		if fmt.Sprint(tree.GetAll(key)) != fmt.Sprint(want[key]) {
+			t.Fatalf("seed %v: GetAll(%v) want %v, got %v\n", seed, key, want[key], tree.GetAll(key))
+		}
+
+		if val, ok := tree.Get(key); ok != (len(want[key]) > 0) || (ok && val != want[key][0]) {
+			t.Fatalf("seed %v: Get(%v) want first value %v, got %v\n", seed, key, want[key], val)
+		}
+	}
+
+	if !tree.Verify() || tree.Len() != size {
+		t.Fatalf("seed %v: Multimap Verify Error, want %v nodes, got %v\n", seed, size, tree.Len())
+	}
+
+	// 相同key的value按插入顺序排列
+	got := map[int][]int{}
+	for k, v := range tree.All() {
+		got[k] = append(got[k], v)
+	}
+
+	for k, vals := range want {
+		if len(vals) > 0 && fmt.Sprint(got[k]) != fmt.Sprint(vals) {
+			t.Fatalf("seed %v: All key %v want %v, got %v\n", seed, k, vals, got[k])
+		}
+	}
+
+	// Put更新最早插入的value，Delete删除所有value
+	tree.Insert(1000, 1)
+	tree.Insert(1000, 2)
+	tree.Put(1000, 3)
+	if fmt.Sprint(tree.GetAll(1000)) != "[3 2]" {
+		t.Fatalf("Put want [3 2], got %v\n", tree.GetAll(1000))
+	}
+
+	tree.Delete(1000)
+	if tree.Count(1000) != 0 || !tree.Verify() {
+		t.Fatalf("Delete want no value, got %v\n", tree.GetAll(1000))
+	}
+}
+
+func Test_RbTreeMultimapRange(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	// key 0~9，每个key有55个value，value按插入顺序排列
+	tree := NewOrderedTree[int, int](WithMultimap())
+	want := map[int][]int{}
+	for _, i := range r.Perm(550) {
+		tree.Insert(i%10, i)
+		want[i%10] = append(want[i%10], i)
+	}
+
+	values := func(seq iter.Seq2[int, int]) []int {
+		res := []int{}
+		for _, v := range seq {
+			res = append(res, v)
+		}
+
+		return res
+	}
+
+	nodeValues := func(list []*TreeNode[int, int]) []int {
+		res := []int{}
+		for _, node := range list {
+			res = append(res, node.GetValue())
+		}
+
+		return res
+	}
+
+	if got := values(tree.Range(5, 5)); !slices.Equal(got, want[5]) {
+		t.Fatalf("seed %v: Range(5, 5) want %v, got %v\n", seed, want[5], got)
+	}
+
+	if got, all := values(tree.Range(3, 6)), slices.Concat(want[3], want[4], want[5], want[6]); !slices.Equal(got, all) {
+		t.Fatalf("seed %v: Range(3, 6) want %v, got %v\n", seed, all, got)
+	}
+
+	if got := nodeValues(tree.SearchRange(5, 5)); !slices.Equal(got, want[5]) {
+		t.Fatalf("seed %v: SearchRange(5, 5) want %v, got %v\n", seed, want[5], got)
+	}
+
+	if got := nodeValues(tree.SearchRangeLowerBoundKeyWithLimit(5, 3)); !slices.Equal(got, want[5][:3]) {
+		t.Fatalf("seed %v: SearchRangeLowerBoundKeyWithLimit(5, 3) want %v, got %v\n", seed, want[5][:3], got)
+	}
+
+	if got := nodeValues(tree.SearchRangeUpperBoundKeyWithLimit(5, 3)); !slices.Equal(got, want[5][52:]) {
+		t.Fatalf("seed %v: SearchRangeUpperBoundKeyWithLimit(5, 3) want %v, got %v\n", seed, want[5][52:], got)
+	}
+
+	// Ceiling为相同key中最早插入的value，Floor为最后插入的value
+	if k, v, ok := tree.Ceiling(5); !ok || k != 5 || v != want[5][0] {
+		t.Fatalf("seed %v: Ceiling(5) want 5: %v, got %v: %v %v\n", seed, want[5][0], k, v, ok)
+	}
+
+	if k, v, ok := tree.Floor(5); !ok || k != 5 || v != want[5][54] {
+		t.Fatalf("seed %v: Floor(5) want 5: %v, got %v: %v %v\n", seed, want[5][54], k, v, ok)
+	}
+
+	tree.DeleteAll(5)
+	if k, v, ok := tree.Ceiling(5); !ok || k != 6 || v != want[6][0] {
+		t.Fatalf("seed %v: Ceiling(5) want 6: %v, got %v: %v %v\n", seed, want[6][0], k, v, ok)
+	}
+
+	if k, v, ok := tree.Floor(5); !ok || k != 4 || v != want[4][54] {
+		t.Fatalf("seed %v: Floor(5) want 4: %v, got %v: %v %v\n", seed, want[4][54], k, v, ok)
+	}
+}
+
+func Test_SyncRbTreeMultimap(t *testing.T) {
+	tree := NewSyncTree(NewOrderedTree[int, int](WithMultimap()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				tree.Insert(j%10, i)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	for k := 0; k < 10; k++ {
+		if tree.Count(k) != 800 || len(tree.GetAll(k)) != 800 {
+			t.Fatalf("Count(%v) want 800, got %v\n", k, tree.Count(k))
+		}
+	}
+
+	if !tree.DeleteOne(0) || tree.DeleteAll(0) != 799 || tree.Len() != 7200 || !tree.Verify() {
+		t.Fatalf("SyncTree Multimap Delete Error, Len %v\n", tree.Len())
+	}
+}
+
+func Test_RbTreeBuildFromSorted(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 4, 7, 8, 100, 1023, 1024, 5000} {
+		vals := rand.Perm(n)
+		tree, ok := BuildFromSorted(cmp.Compare[int], slices.All(vals))
+		if !ok || !tree.Verify() || tree.Len() != n {
+			t.Fatalf("n %v: BuildFromSorted Verify Error, got %v nodes\n", n, tree.Len())
+		}
+
+		idx := 0
+		for k, v := range tree.All() {
+			if k != idx || v != vals[idx] {
+				t.Fatalf("n %v: want %v %v, got %v %v\n", n, idx, vals[idx], k, v)
+			}
+
+			idx++
+		}
+
+		// 创建的树可以继续插入、删除
+		for i := 0; i < n; i += 2 {
+			tree.Delete(i)
+			tree.Insert(n+i, i)
+		}
+
+		if !tree.Verify() || tree.Len() != n {
+			t.Fatalf("n %v: Verify Error after Insert and Delete\n", n)
+		}
+	}
+
+	unsorted := func(yield func(int, int) bool) {
+		for _, k := range []int{1, 3, 2} {
+			if !yield(k, k) {
+				return
+			}
+		}
+	}
+
+	if _, ok := BuildFromSorted(cmp.Compare[int], unsorted); ok {
+		t.Fatalf("unsorted input should return false\n")
+	}
+
+	duplicate := slices.All([]int{0, 0, 1})
+	if _, ok := BuildFromSorted(func(a, b int) int { return cmp.Compare(a/2, b/2) }, duplicate); ok {
+		t.Fatalf("duplicate keys should return false\n")
+	}
+
+	tree, ok := BuildFromSorted(func(a, b int) int { return cmp.Compare(a/2, b/2) }, duplicate, WithMultimap())
+	if !ok || !tree.Verify() || tree.Count(0) != 2 {
+		t.Fatalf("multimap BuildFromSorted Error\n")
+	}
+}
+
+func Benchmark_RbTreeBuildFromSorted(b *testing.B) {
+	vals := make([]int, 100000)
+	for i := 0; i < b.N; i++ {
+		BuildFromSorted(cmp.Compare[int], slices.All(vals))
+	}
+}
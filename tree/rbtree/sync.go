@@ -0,0 +1,169 @@
+package rbtree
+
+import (
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/utils"
+)
+
+// SyncTree 并发安全的红黑树，读操作可以并行执行，Range、All、Backward、Iterator遍历时不持有锁，见orderedmap.Sync
+//
+// Search、SearchRange等查找方法在持有读锁时拷贝节点的key和value，返回的节点不在树中，没有子节点，读取时不需要持有锁
+type SyncTree[K, V any] struct {
+	*orderedmap.Sync[K, V]
+	tree *Tree[K, V]
+}
+
+// NewSyncTree 创建一个并发安全的红黑树，tree交给SyncTree之后不能再直接使用
+func NewSyncTree[K, V any](tree *Tree[K, V]) *SyncTree[K, V] {
+	return &SyncTree[K, V]{
+		Sync: orderedmap.NewSync[K, V](tree),
+		tree: tree,
+	}
+}
+
+// Insert 插入，multimap模式下总是插入新节点
+func (t *SyncTree[K, V]) Insert(key K, val V) {
+	t.Update(func(orderedmap.OrderedMap[K, V]) {
+		t.tree.Insert(key, val)
+	})
+}
+
+// GetAll 按插入顺序返回key对应的所有value
+func (t *SyncTree[K, V]) GetAll(key K) (vals []V) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		vals = t.tree.GetAll(key)
+	})
+
+	return vals
+}
+
+// Count key对应的value的个数
+func (t *SyncTree[K, V]) Count(key K) (count int) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		count = t.tree.Count(key)
+	})
+
+	return count
+}
+
+// DeleteOne 删除key最早插入的一个value
+func (t *SyncTree[K, V]) DeleteOne(key K) (bFound bool) {
+	t.Update(func(orderedmap.OrderedMap[K, V]) {
+		bFound = t.tree.DeleteOne(key)
+	})
+
+	return bFound
+}
+
+// DeleteAll 删除key对应的所有value，返回删除的个数
+func (t *SyncTree[K, V]) DeleteAll(key K) (count int) {
+	t.Update(func(orderedmap.OrderedMap[K, V]) {
+		count = t.tree.DeleteAll(key)
+	})
+
+	return count
+}
+
+// Verify 验证红黑树的性质
+func (t *SyncTree[K, V]) Verify() (bOK bool) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		bOK = t.tree.Verify()
+	})
+
+	return bOK
+}
+
+// String String
+func (t *SyncTree[K, V]) String() (str string) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		str = t.tree.String()
+	})
+
+	return str
+}
+
+// Select 第k小(k从0开始)的key和对应的value
+func (t *SyncTree[K, V]) Select(k int) (key K, val V, bFound bool) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		key, val, bFound = t.tree.Select(k)
+	})
+
+	return key, val, bFound
+}
+
+// Rank 小于key的节点数，key存在时即为key的排名(从0开始)
+func (t *SyncTree[K, V]) Rank(key K) (rank int) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		rank = t.tree.Rank(key)
+	})
+
+	return rank
+}
+
+// CountRange key在[lo, hi]之间的节点数
+func (t *SyncTree[K, V]) CountRange(lo, hi K) (count int) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		count = t.tree.CountRange(lo, hi)
+	})
+
+	return count
+}
+
+// Search 查找key指定的节点，multimap模式下返回最早插入的节点，返回节点的拷贝
+func (t *SyncTree[K, V]) Search(key K) (node *TreeNode[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		if found := t.tree.Search(key); found != nil {
+			node = snapshot([]*TreeNode[K, V]{found})[0]
+		}
+	})
+
+	return node
+}
+
+// SearchRange 查找key在[min, max]之间的节点，返回节点的拷贝
+func (t *SyncTree[K, V]) SearchRange(min, max K) (list []*TreeNode[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		list = snapshot(t.tree.SearchRange(min, max))
+	})
+
+	return list
+}
+
+// SearchRangeLowerBoundKeyWithLimit 查找大于等于key的limit个节点，返回节点的拷贝
+func (t *SyncTree[K, V]) SearchRangeLowerBoundKeyWithLimit(key K, limit int64) (list []*TreeNode[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		list = snapshot(t.tree.SearchRangeLowerBoundKeyWithLimit(key, limit))
+	})
+
+	return list
+}
+
+// SearchRangeUpperBoundKeyWithLimit 找到小于等于key的limit个节点，按key升序返回节点的拷贝
+func (t *SyncTree[K, V]) SearchRangeUpperBoundKeyWithLimit(key K, limit int64) (list []*TreeNode[K, V]) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		list = snapshot(t.tree.SearchRangeUpperBoundKeyWithLimit(key, limit))
+	})
+
+	return list
+}
+
+// Dot 持有读锁生成红黑树的图片
+func (t *SyncTree[K, V]) Dot() (err error) {
+	t.View(func(orderedmap.OrderedMap[K, V]) {
+		err = t.tree.Dot()
+	})
+
+	return err
+}
+
+// snapshot 拷贝节点的key和value，拷贝的节点使用新的哨兵节点，不和树共享任何节点
+func snapshot[K, V any](nodes []*TreeNode[K, V]) []*TreeNode[K, V] {
+	sentinel := newSentinel[K, V]()
+
+	list := make([]*TreeNode[K, V], 0, len(nodes))
+	for _, node := range nodes {
+		list = append(list, NewTreeNode(utils.NewTypedEntry(node.GetKey(), node.GetValue()), sentinel))
+	}
+
+	return list
+}
@@ -0,0 +1,314 @@
+package rope
+
+import (
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/asinglestep/gods/tree/treap"
+)
+
+// maxChunk 每个块的最大字节数
+const maxChunk = 512
+
+// metric 文本的度量
+type metric struct {
+	bytes int // 字节数
+	lines int // 换行符的个数
+}
+
+// Rope 基于隐式treap的文本，文本被切分成多个块，每个块是隐式treap中的一个元素
+//
+// 每个节点维护以该节点为根的子树中文本的字节数和换行符的个数，按字节偏移量、行号查找块的时间复杂度为O(log n)；
+// 偏移量都是字节偏移量，行号、列号从0开始
+type Rope struct {
+	chunks *treap.Implicit[string]
+	aug    *treap.ImplicitAugmented[string, metric]
+}
+
+// NewRope 创建一个内容为s的rope
+func NewRope(s string) *Rope {
+	chunks := treap.NewImplicit[string]()
+	r := &Rope{
+		chunks: chunks,
+		aug: treap.AugmentImplicit(chunks, treap.Augmentation[struct{}, string, metric]{
+			Identity: metric{},
+			Value: func(_ struct{}, chunk string) metric {
+				return metric{bytes: len(chunk), lines: strings.Count(chunk, "\n")}
+			},
+			Combine: func(a, b metric) metric {
+				return metric{bytes: a.bytes + b.bytes, lines: a.lines + b.lines}
+			},
+		}),
+	}
+
+	r.insertChunks(0, s)
+	return r
+}
+
+// Len 字节数
+func (r *Rope) Len() int {
+	return r.aug.Total().bytes
+}
+
+// Lines 行数，等于换行符的个数+1
+func (r *Rope) Lines() int {
+	return r.aug.Total().lines + 1
+}
+
+// String 完整的文本
+func (r *Rope) String() string {
+	var builder strings.Builder
+	builder.Grow(r.Len())
+
+	for piece := range r.pieces(0, r.Len()) {
+		builder.WriteString(piece)
+	}
+
+	return builder.String()
+}
+
+// Insert 在偏移量offset处插入s，0 <= offset <= Len()，偏移量不合法时返回false
+func (r *Rope) Insert(offset int, s string) bool {
+	if offset < 0 || offset > r.Len() {
+		return false
+	}
+
+	if s == "" {
+		return true
+	}
+
+	// s较短时直接插入到offset所在的块中，offset在两个块之间时插入到前一个块的末尾
+	if i, before, bFound := r.locate(max(offset-1, 0)); bFound {
+		chunk, _ := r.chunks.Get(i)
+		if len(chunk)+len(s) <= maxChunk {
+			pos := offset - before.bytes
+			r.chunks.Set(i, chunk[:pos]+s+chunk[pos:])
+			return true
+		}
+	}
+
+	r.insertChunks(r.splitAt(offset), s)
+	return true
+}
+
+// Delete 删除偏移量在[offset, offset+length)之间的文本，范围不合法时返回false
+func (r *Rope) Delete(offset, length int) bool {
+	if offset < 0 || length < 0 || offset+length > r.Len() {
+		return false
+	}
+
+	if length == 0 {
+		return true
+	}
+
+	i := r.splitAt(offset)
+	j := r.splitAt(offset + length)
+	r.chunks.Slice(i, j)
+	r.coalesce(i)
+
+	return true
+}
+
+// Substring 偏移量在[offset, offset+length)之间的文本，范围不合法时返回false
+func (r *Rope) Substring(offset, length int) (string, bool) {
+	if offset < 0 || length < 0 || offset+length > r.Len() {
+		return "", false
+	}
+
+	var builder strings.Builder
+	builder.Grow(length)
+
+	for piece := range r.pieces(offset, offset+length) {
+		builder.WriteString(piece)
+	}
+
+	return builder.String(), true
+}
+
+// Index substr第一次出现的偏移量，没有找到时返回-1
+func (r *Rope) Index(substr string) int {
+	if substr == "" {
+		return 0
+	}
+
+	// carry保存上一个片段末尾的len(substr)-1个字节，用于查找跨越两个块的substr
+	var carry string
+	base := 0 // carry的偏移量
+
+	for piece := range r.pieces(0, r.Len()) {
+		buf := carry + piece
+		if idx := strings.Index(buf, substr); idx >= 0 {
+			return base + idx
+		}
+
+		keep := min(len(substr)-1, len(buf))
+		base += len(buf) - keep
+		carry = buf[len(buf)-keep:]
+	}
+
+	return -1
+}
+
+// LineToOffset 第line行的第一个字节的偏移量，行号不合法时返回false
+func (r *Rope) LineToOffset(line int) (int, bool) {
+	if line < 0 || line >= r.Lines() {
+		return 0, false
+	}
+
+	if line == 0 {
+		return 0, true
+	}
+
+	// 查找第line个换行符所在的块
+	i, before, _ := r.aug.Search(func(m metric) bool {
+		return m.lines >= line
+	})
+
+	chunk, _ := r.chunks.Get(i)
+	offset := before.bytes
+	for n := line - before.lines; n > 0; n-- {
+		idx := strings.IndexByte(chunk, '\n')
+		offset += idx + 1
+		chunk = chunk[idx+1:]
+	}
+
+	return offset, true
+}
+
+// OffsetToLine 偏移量offset所在的行号和列号，列号为offset相对于行首的字节数，0 <= offset <= Len()，偏移量不合法时返回false
+func (r *Rope) OffsetToLine(offset int) (line, col int, bOk bool) {
+	if offset < 0 || offset > r.Len() {
+		return 0, 0, false
+	}
+
+	line = r.aug.Total().lines
+	if i, before, bFound := r.locate(offset); bFound {
+		chunk, _ := r.chunks.Get(i)
+		line = before.lines + strings.Count(chunk[:offset-before.bytes], "\n")
+	}
+
+	start, _ := r.LineToOffset(line)
+	return line, offset - start, true
+}
+
+// NewReader 读取偏移量在[offset, offset+length)之间的文本的io.Reader，范围不合法时返回false
+//
+// 读取的过程中不能修改rope
+func (r *Rope) NewReader(offset, length int) (*Reader, bool) {
+	if offset < 0 || length < 0 || offset+length > r.Len() {
+		return nil, false
+	}
+
+	return &Reader{
+		rope:   r,
+		offset: offset,
+		end:    offset + length,
+	}, true
+}
+
+// Reader 读取rope中一段文本的io.Reader
+type Reader struct {
+	rope   *Rope
+	offset int // 下一次读取的偏移量
+	end    int // 结束的偏移量
+}
+
+// Read 实现io.Reader
+func (rd *Reader) Read(p []byte) (n int, err error) {
+	if rd.offset >= rd.end {
+		return 0, io.EOF
+	}
+
+	for piece := range rd.rope.pieces(rd.offset, rd.end) {
+		c := copy(p[n:], piece)
+		n += c
+		rd.offset += c
+
+		if n == len(p) {
+			break
+		}
+	}
+
+	return n, nil
+}
+
+// locate 偏移量offset所在的块，没有找到时返回false
+//
+// @return
+// i: 块的位置
+// before: 前i个块的度量
+func (r *Rope) locate(offset int) (i int, before metric, bFound bool) {
+	return r.aug.Search(func(m metric) bool {
+		return m.bytes > offset
+	})
+}
+
+// pieces 按顺序返回偏移量在[offset, end)之间的文本片段
+func (r *Rope) pieces(offset, end int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if offset >= end {
+			return
+		}
+
+		i, before, _ := r.locate(offset)
+		pos := offset - before.bytes
+
+		for _, chunk := range r.chunks.Range(i, r.chunks.Len()) {
+			chunk = chunk[pos:]
+			pos = 0
+
+			if len(chunk) >= end-offset {
+				yield(chunk[:end-offset])
+				return
+			}
+
+			if !yield(chunk) {
+				return
+			}
+
+			offset += len(chunk)
+		}
+	}
+}
+
+// splitAt 在偏移量offset处拆分块，返回从offset开始的块的位置，offset为Len()时返回块的个数
+func (r *Rope) splitAt(offset int) int {
+	i, before, bFound := r.locate(offset)
+	if !bFound {
+		return r.chunks.Len()
+	}
+
+	pos := offset - before.bytes
+	if pos == 0 {
+		return i
+	}
+
+	chunk, _ := r.chunks.Get(i)
+	r.chunks.Set(i, chunk[:pos])
+	r.chunks.InsertAt(i+1, chunk[pos:])
+
+	return i + 1
+}
+
+// insertChunks 将s切分成多个块，插入到位置i
+func (r *Rope) insertChunks(i int, s string) {
+	for len(s) > 0 {
+		n := min(len(s), maxChunk)
+		r.chunks.InsertAt(i, s[:n])
+		s = s[n:]
+		i++
+	}
+}
+
+// coalesce 位置i的块和前一个块的字节数之和不超过maxChunk时，合并成一个块
+func (r *Rope) coalesce(i int) {
+	prev, bPrev := r.chunks.Get(i - 1)
+	chunk, bFound := r.chunks.Get(i)
+	if !bPrev || !bFound || len(prev)+len(chunk) > maxChunk {
+		return
+	}
+
+	r.chunks.Set(i-1, prev+chunk)
+	r.chunks.DeleteAt(i)
+}
@@ -0,0 +1,35 @@
+package rope
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 在1MB的文本中随机插入、删除
+
+func Benchmark_RopeEdit(b *testing.B) {
+	text := strings.Repeat("0123456789abcde\n", 1<<16)
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rope := NewRope(text)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		offset := r.Intn(rope.Len())
+		rope.Insert(offset, "edit")
+		rope.Delete(offset, 4)
+	}
+}
+
+func Benchmark_StringEdit(b *testing.B) {
+	text := strings.Repeat("0123456789abcde\n", 1<<16)
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		offset := r.Intn(len(text))
+		text = text[:offset] + "edit" + text[offset:]
+		text = text[:offset] + text[offset+4:]
+	}
+}
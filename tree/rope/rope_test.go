@@ -0,0 +1,141 @@
+package rope
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func Test_RopeEdit(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	// randText 随机生成长度为n的文本
+	randText := func(n int) string {
+		const letters = "abc\n"
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = letters[r.Intn(len(letters))]
+		}
+
+		return string(b)
+	}
+
+	want := randText(3000)
+	rope := NewRope(want)
+
+	for op := 0; op < 2000; op++ {
+		offset := r.Intn(len(want) + 1)
+
+		switch r.Intn(4) {
+		case 0, 1:
+			// 短文本和跨越多个块的长文本
+			n := r.Intn(10)
+			if r.Intn(20) == 0 {
+				n = r.Intn(3 * maxChunk)
+			}
+
+			s := randText(n)
+			if !rope.Insert(offset, s) {
+				t.Fatalf("seed %v op %v: Insert(%v) Error\n", seed, op, offset)
+			}
+
+			want = want[:offset] + s + want[offset:]
+		case 2:
+			// 短文本和跨越多个块的长文本
+			n := 10
+			if r.Intn(20) == 0 {
+				n = 3 * maxChunk
+			}
+
+			length := r.Intn(min(len(want)-offset, n) + 1)
+			if !rope.Delete(offset, length) {
+				t.Fatalf("seed %v op %v: Delete(%v, %v) Error\n", seed, op, offset, length)
+			}
+
+			want = want[:offset] + want[offset+length:]
+		case 3:
+			length := r.Intn(len(want) - offset + 1)
+			if got, ok := rope.Substring(offset, length); !ok || got != want[offset:offset+length] {
+				t.Fatalf("seed %v op %v: Substring(%v, %v) want %q, got %q\n", seed, op, offset, length, want[offset:offset+length], got)
+			}
+		}
+
+		if rope.Len() != len(want) || rope.Lines() != strings.Count(want, "\n")+1 {
+			t.Fatalf("seed %v op %v: want Len %v, got %v\n", seed, op, len(want), rope.Len())
+		}
+	}
+
+	if rope.String() != want {
+		t.Fatalf("seed %v: String Error\n", seed)
+	}
+
+	for _, substr := range []string{"", "a", "abca", "\n\n", want[len(want)/2 : len(want)/2+20], want[len(want)-5:], "x"} {
+		if got := rope.Index(substr); got != strings.Index(want, substr) {
+			t.Fatalf("seed %v: Index(%q) want %v, got %v\n", seed, substr, strings.Index(want, substr), got)
+		}
+	}
+
+	if rope.Insert(-1, "a") || rope.Delete(0, len(want)+1) {
+		t.Fatalf("invalid range want false\n")
+	}
+
+	if _, ok := rope.Substring(len(want), 1); ok {
+		t.Fatalf("invalid range want false\n")
+	}
+}
+
+func Test_RopeLines(t *testing.T) {
+	text := "first line\n\nthird\n" + strings.Repeat("x", 2*maxChunk) + "\nlast"
+	rope := NewRope(text)
+	lines := strings.Split(text, "\n")
+
+	if rope.Lines() != len(lines) {
+		t.Fatalf("Lines want %v, got %v\n", len(lines), rope.Lines())
+	}
+
+	offset := 0
+	for line, s := range lines {
+		if got, ok := rope.LineToOffset(line); !ok || got != offset {
+			t.Fatalf("LineToOffset(%v) want %v, got %v %v\n", line, offset, got, ok)
+		}
+
+		for col := 0; col <= len(s); col++ {
+			l, c, ok := rope.OffsetToLine(offset + col)
+			if !ok || l != line || c != col {
+				t.Fatalf("OffsetToLine(%v) want %v:%v, got %v:%v %v\n", offset+col, line, col, l, c, ok)
+			}
+		}
+
+		offset += len(s) + 1
+	}
+
+	if _, ok := rope.LineToOffset(len(lines)); ok {
+		t.Fatalf("LineToOffset(%v) want false\n", len(lines))
+	}
+}
+
+func Test_RopeReader(t *testing.T) {
+	text := strings.Repeat("0123456789", 300)
+	rope := NewRope(text)
+	rope.Insert(1500, "inserted")
+	text = text[:1500] + "inserted" + text[1500:]
+
+	rd, ok := rope.NewReader(100, 2000)
+	if !ok {
+		t.Fatalf("NewReader Error\n")
+	}
+
+	if err := iotest.TestReader(rd, []byte(text[100:2100])); err != nil {
+		t.Fatal(err)
+	}
+
+	rd, _ = rope.NewReader(0, rope.Len())
+	got, err := io.ReadAll(iotest.OneByteReader(rd))
+	if err != nil || string(got) != text {
+		t.Fatalf("ReadAll Error %v\n", err)
+	}
+}
@@ -0,0 +1,113 @@
+package treap
+
+// Augmentation 节点上维护的聚合值，Combine需要满足结合律，例如: 求和、最大值、最小值
+//
+// 以node为根的子树的聚合值为 Combine(Combine(left, Value(node)), right)
+type Augmentation[K, V, A any] struct {
+	Identity A                    // 空子树的聚合值，Combine(Identity, a) == Combine(a, Identity) == a
+	Value    func(key K, val V) A // 单个节点的聚合值
+	Combine  func(a, b A) A       // 合并两个相邻区间的聚合值，a中的key都小于b中的key
+}
+
+// augmenter 树的结构发生变化后，重新计算节点的聚合值
+type augmenter[K, V any] interface {
+	// update 根据左右子节点的聚合值重新计算node的聚合值
+	update(node *TreeNode[K, V])
+
+	// rebuild 重新计算以node为根的子树中所有节点的聚合值
+	rebuild(node *TreeNode[K, V])
+}
+
+// Augmented 注册了聚合值的treap
+type Augmented[K, V, A any] struct {
+	tree *Tree[K, V]
+	aug  Augmentation[K, V, A]
+}
+
+// Augment 为treap注册聚合值，插入、删除、旋转后会自动维护节点的聚合值
+//
+// 注册时会重新计算树中所有节点的聚合值，一棵树只能注册一个聚合值，重复注册会使之前返回的Augmented失效
+func Augment[K, V, A any](t *Tree[K, V], aug Augmentation[K, V, A]) *Augmented[K, V, A] {
+	a := &Augmented[K, V, A]{
+		tree: t,
+		aug:  aug,
+	}
+
+	t.augmenter = a
+	a.rebuild(t.root)
+
+	return a
+}
+
+// Tree 返回注册了聚合值的treap
+func (a *Augmented[K, V, A]) Tree() *Tree[K, V] {
+	return a.tree
+}
+
+// Aggregate 以node为根的子树的聚合值，node为nil或者哨兵节点时返回Identity
+func (a *Augmented[K, V, A]) Aggregate(node *TreeNode[K, V]) A {
+	if node == nil || node.isSentinel() {
+		return a.aug.Identity
+	}
+
+	return node.agg.(A)
+}
+
+// Total 所有节点的聚合值
+func (a *Augmented[K, V, A]) Total() A {
+	return a.Aggregate(a.tree.root)
+}
+
+// Query 按key升序合并key在[lo, hi]之间的节点的聚合值，时间复杂度O(log n)
+func (a *Augmented[K, V, A]) Query(lo, hi K) A {
+	return a.query(a.tree.root, lo, hi, true, true)
+}
+
+// query 合并以node为根的子树中key在[lo, hi]之间的节点的聚合值
+//
+// @param
+// bLo: 是否需要检查下界lo
+// bHi: 是否需要检查上界hi
+func (a *Augmented[K, V, A]) query(node *TreeNode[K, V], lo, hi K, bLo, bHi bool) A {
+	for !node.isSentinel() {
+		if !bLo && !bHi {
+			// 整棵子树都在范围内
+			return node.agg.(A)
+		}
+
+		if bLo && a.tree.comparator(node.GetKey(), lo) < 0 {
+			// 当前节点小于lo，在右子树查找
+			node = node.right
+			continue
+		}
+
+		if bHi && a.tree.comparator(node.GetKey(), hi) > 0 {
+			// 当前节点大于hi，在左子树查找
+			node = node.left
+			continue
+		}
+
+		// 当前节点在范围内，左子树的key都小于hi，右子树的key都大于lo
+		res := a.aug.Combine(a.query(node.left, lo, hi, bLo, false), a.aug.Value(node.GetKey(), node.GetValue()))
+		return a.aug.Combine(res, a.query(node.right, lo, hi, false, bHi))
+	}
+
+	return a.aug.Identity
+}
+
+// update 根据左右子节点的聚合值重新计算node的聚合值
+func (a *Augmented[K, V, A]) update(node *TreeNode[K, V]) {
+	res := a.aug.Combine(a.Aggregate(node.left), a.aug.Value(node.GetKey(), node.GetValue()))
+	node.agg = a.aug.Combine(res, a.Aggregate(node.right))
+}
+
+// rebuild 后序遍历重新计算以node为根的子树中所有节点的聚合值
+func (a *Augmented[K, V, A]) rebuild(node *TreeNode[K, V]) {
+	if node.isSentinel() {
+		return
+	}
+
+	a.rebuild(node.left)
+	a.rebuild(node.right)
+	a.update(node)
+}
@@ -0,0 +1,403 @@
+package treap
+
+import (
+	"iter"
+
+	"github.com/asinglestep/gods/utils"
+)
+
+// Implicit 隐式treap，按位置访问的序列
+//
+// 节点没有key，以节点在中序遍历中的位置作为隐式的key，位置由左子树的节点数计算得到；
+// 插入时复用treap的随机优先级和旋转，拆分、合并、翻转通过下推翻转标记实现；
+// 可以通过AugmentImplicit注册聚合值，按聚合值查找位置
+type Implicit[V any] struct {
+	tree *Tree[struct{}, V]
+}
+
+// NewImplicit 创建一个空的隐式treap
+//
+// @param
+// opts: 随机数种子、随机数源等选项，默认使用种子为1的xorshift
+func NewImplicit[V any](opts ...Option) *Implicit[V] {
+	return &Implicit[V]{
+		tree: NewTreeFunc[struct{}, V](nil, opts...),
+	}
+}
+
+// Len 元素个数
+func (s *Implicit[V]) Len() int {
+	return s.tree.size
+}
+
+// InsertAt 在位置i插入val，插入之后val的位置为i，0 <= i <= Len()，位置不合法时返回false
+func (s *Implicit[V]) InsertAt(i int, val V) bool {
+	t := s.tree
+	if i < 0 || i > t.size {
+		return false
+	}
+
+	node := NewTreeNode(utils.NewTypedEntry(struct{}{}, val), t.rand(), t.sentinel)
+	next := &t.root
+	var parent *TreeNode[struct{}, V]
+
+	for cur := *next; !cur.isSentinel(); cur = *next {
+		// 旋转之前需要下推路径上的翻转标记
+		s.push(cur)
+		parent = cur
+
+		if i <= cur.left.count() {
+			next = &cur.left
+		} else {
+			i -= cur.left.count() + 1
+			next = &cur.right
+		}
+	}
+
+	*next = node
+	node.parent = parent
+	t.augmentPath(node)
+	t.insertFixUp(node)
+	t.size++
+
+	return true
+}
+
+// Append 在末尾插入val
+func (s *Implicit[V]) Append(val V) {
+	s.InsertAt(s.Len(), val)
+}
+
+// DeleteAt 删除位置i的元素，并返回删除的元素，位置不合法时返回false
+func (s *Implicit[V]) DeleteAt(i int) (val V, bFound bool) {
+	t := s.tree
+	if i < 0 || i >= t.size {
+		return val, false
+	}
+
+	node := s.lookup(i)
+	parent, isLeft := node.parent, node.isLeft()
+
+	// 用左右子树合并之后的树替换node
+	children := s.merge(node.left, node.right)
+	if !children.isSentinel() {
+		children.parent = parent
+	}
+
+	t.updateChildren(parent, children, isLeft)
+	t.augmentPath(parent)
+	t.size--
+
+	val = node.GetValue()
+	node.free()
+
+	return val, true
+}
+
+// Get 位置i的元素，位置不合法时返回false
+func (s *Implicit[V]) Get(i int) (val V, bFound bool) {
+	if i < 0 || i >= s.tree.size {
+		return val, false
+	}
+
+	return s.find(i).GetValue(), true
+}
+
+// Set 修改位置i的元素，位置不合法时返回false
+func (s *Implicit[V]) Set(i int, val V) bool {
+	if i < 0 || i >= s.tree.size {
+		return false
+	}
+
+	node := s.find(i)
+	node.entry.SetValue(val)
+	s.tree.augmentPath(node)
+
+	return true
+}
+
+// Slice 将位置在[i, j)之间的元素从s中拆分出来，作为一个新的序列返回，0 <= i <= j <= Len()，位置不合法时返回false
+func (s *Implicit[V]) Slice(i, j int) (*Implicit[V], bool) {
+	if i < 0 || i > j || j > s.tree.size {
+		return nil, false
+	}
+
+	l, m, r := s.split3(i, j)
+	s.setRoot(s.merge(l, r))
+
+	slice := NewImplicit[V](WithSeed(s.tree.rand()))
+	slice.tree.augmenter = s.tree.augmenter
+	slice.setRoot(m)
+
+	return slice, true
+}
+
+// Concat 将other中的元素追加到s的末尾，追加之后other为空
+//
+// s注册了聚合值，且other的聚合值和s不同时（不是由s通过Slice拆分出来的），需要重新计算other中所有节点的聚合值
+func (s *Implicit[V]) Concat(other *Implicit[V]) {
+	if s.tree.augmenter != nil && s.tree.augmenter != other.tree.augmenter {
+		s.tree.augmenter.rebuild(other.tree.root)
+	}
+
+	s.setRoot(s.merge(s.tree.root, other.tree.root))
+	other.tree.reset()
+}
+
+// RangeReverse 翻转位置在[i, j)之间的元素，0 <= i <= j <= Len()，位置不合法时返回false
+//
+// 翻转不会重新计算聚合值，注册了聚合值时，Combine需要满足交换律
+func (s *Implicit[V]) RangeReverse(i, j int) bool {
+	if i < 0 || i > j || j > s.tree.size {
+		return false
+	}
+
+	l, m, r := s.split3(i, j)
+	if !m.isSentinel() {
+		m.reversed = !m.reversed
+	}
+
+	s.setRoot(s.merge(s.merge(l, m), r))
+	return true
+}
+
+// All 按位置升序遍历所有元素
+func (s *Implicit[V]) All() iter.Seq2[int, V] {
+	return func(yield func(int, V) bool) {
+		i := 0
+		s.walk(s.tree.root, false, func(val V) bool {
+			i++
+			return yield(i-1, val)
+		})
+	}
+}
+
+// Range 按位置升序遍历位置在[i, j)之间的元素，时间复杂度O(log n + j - i)
+func (s *Implicit[V]) Range(i, j int) iter.Seq2[int, V] {
+	return func(yield func(int, V) bool) {
+		pos := max(i, 0)
+		s.walkRange(s.tree.root, false, i, j, func(val V) bool {
+			pos++
+			return yield(pos-1, val)
+		})
+	}
+}
+
+// Backward 按位置降序遍历所有元素
+func (s *Implicit[V]) Backward() iter.Seq2[int, V] {
+	return func(yield func(int, V) bool) {
+		i := s.tree.size
+		s.walk(s.tree.root, true, func(val V) bool {
+			i--
+			return yield(i, val)
+		})
+	}
+}
+
+// Values 按位置升序返回所有元素
+func (s *Implicit[V]) Values() []V {
+	values := make([]V, 0, s.tree.size)
+	for _, val := range s.All() {
+		values = append(values, val)
+	}
+
+	return values
+}
+
+// Verify 验证是否是隐式treap
+func (s *Implicit[V]) Verify() bool {
+	root := s.tree.root
+	if !root.isSentinel() && root.parent != nil {
+		return false
+	}
+
+	return s.verify(root) && root.count() == s.tree.size
+}
+
+// verify 验证以node为根节点的子树的优先级、节点数和父节点
+func (s *Implicit[V]) verify(node *TreeNode[struct{}, V]) bool {
+	if node.isSentinel() {
+		return true
+	}
+
+	for _, child := range []*TreeNode[struct{}, V]{node.left, node.right} {
+		if child.isSentinel() {
+			continue
+		}
+
+		if child.priority < node.priority || child.parent != node || !s.verify(child) {
+			return false
+		}
+	}
+
+	return node.size == node.left.count()+node.right.count()+1
+}
+
+// push 下推node的翻转标记：交换左右子树，并翻转子节点的翻转标记
+func (s *Implicit[V]) push(node *TreeNode[struct{}, V]) {
+	if node.isSentinel() || !node.reversed {
+		return
+	}
+
+	node.left, node.right = node.right, node.left
+	for _, child := range []*TreeNode[struct{}, V]{node.left, node.right} {
+		if !child.isSentinel() {
+			child.reversed = !child.reversed
+		}
+	}
+
+	node.reversed = false
+}
+
+// lookup 查找位置i的节点，并下推路径上的翻转标记，0 <= i < Len()
+func (s *Implicit[V]) lookup(i int) *TreeNode[struct{}, V] {
+	node := s.tree.root
+
+	for {
+		s.push(node)
+
+		size := node.left.count()
+		switch {
+		case i < size:
+			node = node.left
+		case i > size:
+			i -= size + 1
+			node = node.right
+		default:
+			return node
+		}
+	}
+}
+
+// find 查找位置i的节点，不修改树，0 <= i < Len()
+func (s *Implicit[V]) find(i int) *TreeNode[struct{}, V] {
+	node := s.tree.root
+	var rev bool
+
+	for {
+		// rev为true时，以node为根节点的子树需要翻转，左右子树交换
+		rev = rev != node.reversed
+		left, right := node.left, node.right
+		if rev {
+			left, right = right, left
+		}
+
+		size := left.count()
+		switch {
+		case i < size:
+			node = left
+		case i > size:
+			i -= size + 1
+			node = right
+		default:
+			return node
+		}
+	}
+}
+
+// walk 中序遍历以node为根节点的子树，rev为true时逆序遍历，yield返回false时停止遍历
+func (s *Implicit[V]) walk(node *TreeNode[struct{}, V], rev bool, yield func(V) bool) bool {
+	if node.isSentinel() {
+		return true
+	}
+
+	rev = rev != node.reversed
+	first, second := node.left, node.right
+	if rev {
+		first, second = second, first
+	}
+
+	return s.walk(first, rev, yield) && yield(node.GetValue()) && s.walk(second, rev, yield)
+}
+
+// walkRange 中序遍历以node为根节点的子树中位置在[i, j)之间的元素，位置相对于子树，yield返回false时停止遍历
+func (s *Implicit[V]) walkRange(node *TreeNode[struct{}, V], rev bool, i, j int, yield func(V) bool) bool {
+	if node.isSentinel() || i >= j || j <= 0 || i >= node.size {
+		return true
+	}
+
+	rev = rev != node.reversed
+	first, second := node.left, node.right
+	if rev {
+		first, second = second, first
+	}
+
+	size := first.count()
+	if i < size && !s.walkRange(first, rev, i, j, yield) {
+		return false
+	}
+
+	if i <= size && size < j && !yield(node.GetValue()) {
+		return false
+	}
+
+	return s.walkRange(second, rev, i-size-1, j-size-1, yield)
+}
+
+// split 将以node为根节点的子树拆分成两棵子树，l中为前k个元素，r中为剩下的元素
+func (s *Implicit[V]) split(node *TreeNode[struct{}, V], k int) (l, r *TreeNode[struct{}, V]) {
+	if node.isSentinel() {
+		return node, node
+	}
+
+	s.push(node)
+
+	size := node.left.count()
+	if k <= size {
+		l, r = s.split(node.left, k)
+		s.link(node, r, node.right)
+		return l, node
+	}
+
+	l, r = s.split(node.right, k-size-1)
+	s.link(node, node.left, l)
+	return node, r
+}
+
+// split3 将树拆分成位置在[0, i)、[i, j)、[j, Len())之间的三棵子树
+func (s *Implicit[V]) split3(i, j int) (l, m, r *TreeNode[struct{}, V]) {
+	l, r = s.split(s.tree.root, i)
+	m, r = s.split(r, j-i)
+
+	return l, m, r
+}
+
+// merge 合并l、r两棵子树，l中的元素在r之前，优先级小的节点作为根节点，返回新的根节点
+func (s *Implicit[V]) merge(l, r *TreeNode[struct{}, V]) *TreeNode[struct{}, V] {
+	if l.isSentinel() {
+		return r
+	}
+
+	if r.isSentinel() {
+		return l
+	}
+
+	if l.priority <= r.priority {
+		s.push(l)
+		s.link(l, l.left, s.merge(l.right, r))
+		return l
+	}
+
+	s.push(r)
+	s.link(r, s.merge(l, r.left), r.right)
+	return r
+}
+
+// link 设置node的左右子节点，并重新计算node的节点数和聚合值
+func (s *Implicit[V]) link(node, left, right *TreeNode[struct{}, V]) {
+	s.tree.link(node, left, right)
+	s.tree.augment(node)
+}
+
+// setRoot 设置根节点，并更新元素个数
+func (s *Implicit[V]) setRoot(root *TreeNode[struct{}, V]) {
+	t := s.tree
+	if root.isSentinel() {
+		t.reset()
+		return
+	}
+
+	root.parent = nil
+	t.root = root
+	t.size = root.size
+}
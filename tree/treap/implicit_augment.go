@@ -0,0 +1,69 @@
+package treap
+
+// ImplicitAugmented 注册了聚合值的隐式treap
+type ImplicitAugmented[V, A any] struct {
+	seq       *Implicit[V]
+	augmented *Augmented[struct{}, V, A]
+}
+
+// AugmentImplicit 为隐式treap注册聚合值，插入、删除、拆分、合并后会自动维护节点的聚合值
+//
+// 注册时会重新计算所有节点的聚合值，一个隐式treap只能注册一个聚合值，重复注册会使之前返回的ImplicitAugmented失效
+func AugmentImplicit[V, A any](s *Implicit[V], aug Augmentation[struct{}, V, A]) *ImplicitAugmented[V, A] {
+	return &ImplicitAugmented[V, A]{
+		seq:       s,
+		augmented: Augment(s.tree, aug),
+	}
+}
+
+// Implicit 返回注册了聚合值的隐式treap
+func (a *ImplicitAugmented[V, A]) Implicit() *Implicit[V] {
+	return a.seq
+}
+
+// Total 所有元素的聚合值
+func (a *ImplicitAugmented[V, A]) Total() A {
+	return a.augmented.Total()
+}
+
+// Search 查找第一个满足pred(前i+1个元素的聚合值)的位置i，时间复杂度O(log n)
+//
+// pred需要满足单调性：对某个前缀的聚合值返回true，对更长的前缀的聚合值也返回true，例如: 前缀和大于某个值
+//
+// @return
+// i: 满足条件的位置，没有找到时为Len()
+// before: 前i个元素的聚合值
+// bFound: 是否找到
+func (a *ImplicitAugmented[V, A]) Search(pred func(A) bool) (i int, before A, bFound bool) {
+	aug := a.augmented.aug
+	node := a.seq.tree.root
+	before = aug.Identity
+
+	var rev bool
+	for !node.isSentinel() {
+		rev = rev != node.reversed
+		left, right := node.left, node.right
+		if rev {
+			left, right = right, left
+		}
+
+		withLeft := aug.Combine(before, a.augmented.Aggregate(left))
+		if !left.isSentinel() && pred(withLeft) {
+			// 在左子树中
+			node = left
+			continue
+		}
+
+		i += left.count()
+		withNode := aug.Combine(withLeft, aug.Value(node.GetKey(), node.GetValue()))
+		if pred(withNode) {
+			return i, withLeft, true
+		}
+
+		before = withNode
+		i++
+		node = right
+	}
+
+	return i, before, false
+}
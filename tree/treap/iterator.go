@@ -1,15 +1,15 @@
 package treap
 
 // Iterator Iterator
-type Iterator struct {
-	node   *TreeNode
-	tree   *Tree
+type Iterator[K, V any] struct {
+	node   *TreeNode[K, V]
+	tree   *Tree[K, V]
 	bBegin bool
 }
 
 // NewIterator NewIterator
-func NewIterator(tree *Tree) *Iterator {
-	iter := &Iterator{}
+func NewIterator[K, V any](tree *Tree[K, V]) *Iterator[K, V] {
+	iter := &Iterator[K, V]{}
 	iter.tree = tree
 	iter.node = iter.tree.minimum()
 	iter.bBegin = true
@@ -18,8 +18,8 @@ func NewIterator(tree *Tree) *Iterator {
 }
 
 // NewIteratorWithNode 从指定的node开始迭代
-func NewIteratorWithNode(tree *Tree, node *TreeNode) *Iterator {
-	iter := &Iterator{}
+func NewIteratorWithNode[K, V any](tree *Tree[K, V], node *TreeNode[K, V]) *Iterator[K, V] {
+	iter := &Iterator[K, V]{}
 	iter.tree = tree
 	iter.node = node
 	iter.bBegin = true
@@ -28,7 +28,7 @@ func NewIteratorWithNode(tree *Tree, node *TreeNode) *Iterator {
 }
 
 // Next Next
-func (iter *Iterator) Next() bool {
+func (iter *Iterator[K, V]) Next() bool {
 	if iter.bBegin {
 		iter.bBegin = false
 	} else {
@@ -43,7 +43,7 @@ func (iter *Iterator) Next() bool {
 }
 
 // Prev Prev
-func (iter *Iterator) Prev() bool {
+func (iter *Iterator[K, V]) Prev() bool {
 	if iter.bBegin {
 		iter.bBegin = false
 	} else {
@@ -58,11 +58,11 @@ func (iter *Iterator) Prev() bool {
 }
 
 // GetKey GetKey
-func (iter *Iterator) GetKey() interface{} {
+func (iter *Iterator[K, V]) GetKey() K {
 	return iter.node.entry.GetKey()
 }
 
 // GetValue GetValue
-func (iter *Iterator) GetValue() interface{} {
+func (iter *Iterator[K, V]) GetValue() V {
 	return iter.node.entry.GetValue()
 }
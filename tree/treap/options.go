@@ -0,0 +1,53 @@
+package treap
+
+import (
+	"math/rand"
+)
+
+// Option 创建treap的选项
+type Option func(o *options)
+
+// options treap的选项
+type options struct {
+	seed   uint32      // xorshift的种子
+	source rand.Source // 随机数源
+	multi  bool        // 是否允许重复的key
+}
+
+// newOptions 默认选项：种子为1的xorshift
+func newOptions(opts []Option) *options {
+	o := &options{
+		seed: 1,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithSeed 使用seed作为xorshift的种子，相同的种子和插入顺序会生成相同结构的treap，用于复现问题
+//
+// xorshift的种子不能为0，seed为0时忽略
+func WithSeed(seed uint32) Option {
+	return func(o *options) {
+		if seed != 0 {
+			o.seed = seed
+		}
+	}
+}
+
+// WithSource 使用src生成节点的优先级，src只能被一个treap使用
+func WithSource(src rand.Source) Option {
+	return func(o *options) {
+		o.source = src
+	}
+}
+
+// WithMultimap 使用multimap模式，Insert总是插入新节点，相同key的节点按插入顺序排列
+func WithMultimap() Option {
+	return func(o *options) {
+		o.multi = true
+	}
+}
@@ -0,0 +1,103 @@
+package treap
+
+import (
+	"github.com/asinglestep/gods/utils"
+)
+
+// 用户指定的优先级越大越靠近根节点，节点中保存的是优先级按位取反之后的值，树仍然按节点中保存的值满足小根堆的性质；
+// Insert生成的随机优先级同样按位取反之后对用户可见
+
+// InsertWithPriority 插入key、value，并指定优先级，key已存在时更新value和优先级，multimap模式下总是插入新节点
+func (t *Tree[K, V]) InsertWithPriority(key K, val V, priority uint32) {
+	if node := t.lookup(key); node != nil && !t.multi {
+		node.entry.SetValue(val)
+		t.augmentPath(node)
+		t.setPriority(node, ^priority)
+		return
+	}
+
+	t.insertNode(NewTreeNode(utils.NewTypedEntry(key, val), ^priority, t.sentinel))
+}
+
+// UpdatePriority 修改key的优先级，multimap模式下修改最早插入的节点，key不存在时返回false
+func (t *Tree[K, V]) UpdatePriority(key K, priority uint32) bool {
+	node := t.lookupFirst(key)
+	if node == nil {
+		return false
+	}
+
+	t.setPriority(node, ^priority)
+	return true
+}
+
+// GetPriority key的优先级，multimap模式下返回最早插入的节点的优先级，key不存在时返回false
+func (t *Tree[K, V]) GetPriority(key K) (uint32, bool) {
+	node := t.lookupFirst(key)
+	if node == nil {
+		return 0, false
+	}
+
+	return ^node.priority, true
+}
+
+// PeekMaxPriority 优先级最大的key、value和优先级，即根节点，时间复杂度O(1)
+func (t *Tree[K, V]) PeekMaxPriority() (key K, val V, priority uint32, bFound bool) {
+	return t.unpackPriority(t.root)
+}
+
+// MaxPriorityInRange key在[lo, hi]之间的节点中优先级最大的key、value和优先级，期望时间复杂度O(log n)
+//
+// 从根节点向下查找，第一个key在[lo, hi]之间的节点是所有key在[lo, hi]之间的节点的祖先，它的优先级最大
+func (t *Tree[K, V]) MaxPriorityInRange(lo, hi K) (key K, val V, priority uint32, bFound bool) {
+	node := t.root
+
+	for !node.isSentinel() {
+		if t.comparator(node.GetKey(), lo) < 0 {
+			node = node.right
+		} else if t.comparator(node.GetKey(), hi) > 0 {
+			node = node.left
+		} else {
+			break
+		}
+	}
+
+	return t.unpackPriority(node)
+}
+
+// setPriority 修改node中保存的优先级，并通过旋转恢复堆的性质
+func (t *Tree[K, V]) setPriority(node *TreeNode[K, V], priority uint32) {
+	node.priority = priority
+
+	// 优先级变小时向上旋转
+	t.insertFixUp(node)
+
+	// 优先级变大时向下旋转，和优先级较小的子节点交换位置
+	for {
+		child := node.left
+		if child.isSentinel() || (!node.right.isSentinel() && node.right.priority < child.priority) {
+			child = node.right
+		}
+
+		if child.isSentinel() || child.priority >= node.priority {
+			return
+		}
+
+		parent, isLeft := node.parent, node.isLeft()
+		if child == node.left {
+			child = t.rightRotate(node)
+		} else {
+			child = t.leftRotate(node)
+		}
+
+		t.updateChildren(parent, child, isLeft)
+	}
+}
+
+// unpackPriority 获取节点的key、value和用户可见的优先级，node为哨兵节点时返回false
+func (t *Tree[K, V]) unpackPriority(node *TreeNode[K, V]) (key K, val V, priority uint32, bFound bool) {
+	if node.isSentinel() {
+		return key, val, 0, false
+	}
+
+	return node.GetKey(), node.GetValue(), ^node.priority, true
+}
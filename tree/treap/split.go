@@ -0,0 +1,149 @@
+package treap
+
+// Split 按key将树拆分成两棵树，left中的key都小于key，right中的key都大于等于key，期望时间复杂度O(log n)
+//
+// 拆分之后t为空树，left、right使用t的比较函数、模式和由t生成的随机数种子，没有注册聚合值
+func (t *Tree[K, V]) Split(key K) (left, right *Tree[K, V]) {
+	l, r := t.split(t.root, key)
+	left, right = t.subtree(l), t.subtree(r)
+	t.reset()
+
+	return left, right
+}
+
+// Join 合并两棵树，left中的key都必须小于right中的key，left为multimap模式时允许等于，期望时间复杂度O(log n)
+//
+// 合并之后left、right为空树，返回的树使用left的比较函数、模式和由left生成的随机数种子，没有注册聚合值；
+// left、right的key有重叠时返回false，left、right不变
+func Join[K, V any](left, right *Tree[K, V]) (*Tree[K, V], bool) {
+	maxNode, minNode := left.maximum(), right.minimum()
+	if maxNode != nil && minNode != nil {
+		if res := left.comparator(maxNode.GetKey(), minNode.GetKey()); res > 0 || (res == 0 && !left.multi) {
+			return nil, false
+		}
+	}
+
+	tree := left.subtree(left.join(left.root, right.root))
+	left.reset()
+	right.reset()
+
+	return tree, true
+}
+
+// split 按key拆分以node为根节点的子树，l中的key都小于key，r中的key都大于等于key
+func (t *Tree[K, V]) split(node *TreeNode[K, V], key K) (l, r *TreeNode[K, V]) {
+	if node.isSentinel() {
+		return node, node
+	}
+
+	if t.comparator(node.GetKey(), key) < 0 {
+		// node和左子树都小于key，node的优先级小于右子树中所有节点，仍然是l的根节点
+		l, r = t.split(node.right, key)
+		t.link(node, node.left, l)
+		return node, r
+	}
+
+	// node和右子树都大于等于key
+	l, r = t.split(node.left, key)
+	t.link(node, r, node.right)
+	return l, node
+}
+
+// splitNode 按key拆分以node为根节点的子树，l中的key都小于key，r中的key都大于key，m为key所在的节点，没有找到key时m为哨兵节点
+func (t *Tree[K, V]) splitNode(node *TreeNode[K, V], key K) (l, m, r *TreeNode[K, V]) {
+	if node.isSentinel() {
+		return node, node, node
+	}
+
+	switch res := t.comparator(node.GetKey(), key); {
+	case res < 0:
+		l, m, r = t.splitNode(node.right, key)
+		t.link(node, node.left, l)
+		return node, m, r
+	case res > 0:
+		l, m, r = t.splitNode(node.left, key)
+		t.link(node, r, node.right)
+		return l, m, node
+	}
+
+	return node.left, node, node.right
+}
+
+// joinWith 以mid为中间节点合并l、r两棵子树，l中的key都小于mid，r中的key都大于mid，mid原来的子节点会被丢弃，返回新的根节点
+func (t *Tree[K, V]) joinWith(l, mid, r *TreeNode[K, V]) *TreeNode[K, V] {
+	bLeft := !l.isSentinel() && l.priority < mid.priority
+	bRight := !r.isSentinel() && r.priority < mid.priority
+
+	switch {
+	case bLeft && (!bRight || l.priority <= r.priority):
+		// l的根节点的优先级最小
+		t.link(l, l.left, t.joinWith(l.right, mid, r))
+		return l
+	case bRight:
+		// r的根节点的优先级最小
+		t.link(r, t.joinWith(l, mid, r.left), r.right)
+		return r
+	}
+
+	t.link(mid, l, r)
+	return mid
+}
+
+// join 合并l、r两棵子树，l中的key都小于r中的key，优先级小的节点作为根节点，返回新的根节点
+func (t *Tree[K, V]) join(l, r *TreeNode[K, V]) *TreeNode[K, V] {
+	if l.isSentinel() {
+		return r
+	}
+
+	if r.isSentinel() {
+		return l
+	}
+
+	if l.priority <= r.priority {
+		t.link(l, l.left, t.join(l.right, r))
+		return l
+	}
+
+	t.link(r, t.join(l, r.left), r.right)
+	return r
+}
+
+// link 设置node的左右子节点，并重新计算node的节点数
+//
+// 拆分、合并时不维护聚合值，参与拆分、合并的树可能注册了不同的聚合值
+func (t *Tree[K, V]) link(node, left, right *TreeNode[K, V]) {
+	node.left = left
+	node.right = right
+
+	if !left.isSentinel() {
+		left.parent = node
+	}
+
+	if !right.isSentinel() {
+		right.parent = node
+	}
+
+	node.size = left.count() + right.count() + 1
+}
+
+// subtree 创建一棵以root为根节点的树，比较函数和模式和t相同，随机数种子由t生成
+//
+// 新的树不共享t的随机数源，可以和t在不同的goroutine中使用
+func (t *Tree[K, V]) subtree(root *TreeNode[K, V]) *Tree[K, V] {
+	tree := NewTreeFunc[K, V](t.comparator, WithSeed(t.rand()))
+	tree.multi = t.multi
+
+	if !root.isSentinel() {
+		root.parent = nil
+		tree.root = root
+		tree.size = root.size
+	}
+
+	return tree
+}
+
+// reset 清空树，树中的节点已经转移到其他树中
+func (t *Tree[K, V]) reset() {
+	t.root = t.sentinel
+	t.size = 0
+}
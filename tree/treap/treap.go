@@ -2,54 +2,82 @@ package treap
 
 import (
 	"bytes"
+	"cmp"
 	"container/list"
 	"fmt"
+	"iter"
+	"math/rand"
 	"os/exec"
 	"runtime"
 
 	dot "github.com/asinglestep/godot"
+	"github.com/asinglestep/gods/map/orderedmap"
 	"github.com/asinglestep/gods/utils"
 )
 
 // Tree Tree
-type Tree struct {
-	root       *TreeNode // 根节点
-	seed       uint32
-	size       int // 节点数
-	comparator utils.Comparator
-}
-
-// NewTree NewTree
-func NewTree(comparator utils.Comparator) *Tree {
-	t := &Tree{}
-	t.root = Sentinel
-	t.seed = 1
+type Tree[K, V any] struct {
+	root       *TreeNode[K, V] // 根节点
+	sentinel   *TreeNode[K, V] // 哨兵节点
+	seed       uint32          // xorshift的种子
+	source     rand.Source     // 随机数源，为nil时使用xorshift生成优先级
+	size       int             // 节点数
+	comparator func(a, b K) int
+	augmenter  augmenter[K, V] // 聚合值，没有注册时为nil
+	multi      bool            // 是否是multimap模式
+}
+
+// NewTree 创建一个key、value为interface{}的treap
+func NewTree(comparator utils.Comparator, opts ...Option) *Tree[interface{}, interface{}] {
+	return NewTreeFunc[interface{}, interface{}](comparator.Compare, opts...)
+}
+
+// NewTreeFunc 创建一个treap
+//
+// @param
+// comparator: 比较函数，a > b 返回正数，a = b 返回 0，a < b 返回负数
+// opts: 随机数种子、随机数源、multimap模式等选项，默认使用种子为1的xorshift
+func NewTreeFunc[K, V any](comparator func(a, b K) int, opts ...Option) *Tree[K, V] {
+	o := newOptions(opts)
+
+	t := &Tree[K, V]{}
+	t.sentinel = newSentinel[K, V]()
+	t.root = t.sentinel
+	t.seed = o.seed
+	t.source = o.source
 	t.comparator = comparator
+	t.multi = o.multi
 
 	return t
 }
 
-// Insert 插入
-func (t *Tree) Insert(key, val interface{}) {
-	node := NewTreeNode(utils.NewEntry(key, val), t.rand())
+// NewOrderedTree 创建一个key为有序类型的treap
+func NewOrderedTree[K cmp.Ordered, V any](opts ...Option) *Tree[K, V] {
+	return NewTreeFunc[K, V](cmp.Compare[K], opts...)
+}
+
+// Insert 插入，key已存在时更新value，multimap模式下总是插入新节点
+func (t *Tree[K, V]) Insert(key K, val V) {
+	node := NewTreeNode(utils.NewTypedEntry(key, val), t.rand(), t.sentinel)
 	t.insertNode(node)
 }
 
 // insertNode 插入节点
-func (t *Tree) insertNode(node *TreeNode) {
+func (t *Tree[K, V]) insertNode(node *TreeNode[K, V]) {
 	next := &t.root
-	var parent *TreeNode
+	var parent *TreeNode[K, V]
 
 	for cur := *next; !cur.isSentinel(); cur = *next {
-		res := t.comparator.Compare(cur.GetKey(), node.GetKey())
-		if res == utils.Et {
+		res := t.comparator(cur.GetKey(), node.GetKey())
+		if res == 0 && !t.multi {
 			cur.entry.SetValue(node.GetValue())
+			t.augmentPath(cur)
 			return
 		}
 
 		parent = cur
-		if res == utils.Lt {
-			// 在右子树查找
+		if res <= 0 {
+			// 在右子树查找，multimap模式下相同的key插入到右子树，保持插入顺序
 			next = &cur.right
 		} else {
 			// 在左子树查找
@@ -59,40 +87,46 @@ func (t *Tree) insertNode(node *TreeNode) {
 
 	*next = node
 	node.parent = parent
+	t.augmentPath(node)
 	t.insertFixUp(node)
 	t.size++
 }
 
 // insertFixUp 插入修复
-func (t *Tree) insertFixUp(node *TreeNode) {
+func (t *Tree[K, V]) insertFixUp(node *TreeNode[K, V]) {
 	for node.parent != nil && node.parent.priority > node.priority {
 		grandfather := node.parent.parent
 		isLeft := node.parent.isLeft()
 
 		if node.isLeft() {
 			// 父节点的左节点
-			node.parent.rightRotate()
+			t.rightRotate(node.parent)
 		} else {
 			// 父节点的右节点
-			node.parent.leftRotate()
+			t.leftRotate(node.parent)
 		}
 
 		t.updateChildren(grandfather, node, isLeft)
 	}
 }
 
-// Delete 删除
-func (t *Tree) Delete(key interface{}) {
+// Delete 删除，multimap模式下删除key对应的所有节点
+func (t *Tree[K, V]) Delete(key K) {
+	if t.multi {
+		t.DeleteAll(key)
+		return
+	}
+
 	node := t.root
 
 	for !node.isSentinel() {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		if res == 0 {
 			t.deleteNode(node)
 			return
 		}
 
-		if res == utils.Lt {
+		if res < 0 {
 			node = node.right
 		} else {
 			node = node.left
@@ -101,8 +135,8 @@ func (t *Tree) Delete(key interface{}) {
 }
 
 // deleteNode 删除节点
-func (t *Tree) deleteNode(node *TreeNode) {
-	var children *TreeNode
+func (t *Tree[K, V]) deleteNode(node *TreeNode[K, V]) {
+	var children *TreeNode[K, V]
 
 	for !node.left.isSentinel() || !node.right.isSentinel() {
 		parent := node.parent
@@ -110,32 +144,34 @@ func (t *Tree) deleteNode(node *TreeNode) {
 
 		if node.left.isSentinel() || (!node.right.isSentinel() && node.left.priority > node.right.priority) {
 			// 左节点为空 或者 左右节点都不为空，且左节点的优先级大于右节点的优先级
-			children = node.leftRotate()
+			children = t.leftRotate(node)
 		} else {
 			// 右节点为空 或者 左右节点都不为空，且右节点的优先级大于左节点的优先级
-			children = node.rightRotate()
+			children = t.rightRotate(node)
 		}
 
 		t.updateChildren(parent, children, isLeft)
 	}
 
-	t.updateChildren(node.parent, Sentinel, node.isLeft())
+	parent := node.parent
+	t.updateChildren(parent, t.sentinel, node.isLeft())
+	t.augmentPath(parent)
 	node.free()
 	t.size--
 }
 
-// Search 查找
-func (t *Tree) Search(key interface{}) *TreeNode {
-	return t.lookup(key)
+// Search 查找，multimap模式下返回最早插入的节点
+func (t *Tree[K, V]) Search(key K) *TreeNode[K, V] {
+	return t.lookupFirst(key)
 }
 
 // SearchRange 查找key在[min, max]之间的节点
-func (t *Tree) SearchRange(min, max interface{}) []*TreeNode {
-	list := []*TreeNode{}
+func (t *Tree[K, V]) SearchRange(min, max K) []*TreeNode[K, V] {
+	list := []*TreeNode[K, V]{}
 
 	iter := NewIteratorWithNode(t, t.lookupLowerBoundKey(min))
 	for iter.Next() {
-		if t.comparator.Compare(iter.GetKey(), max) == utils.Gt {
+		if t.comparator(iter.GetKey(), max) > 0 {
 			break
 		}
 
@@ -146,9 +182,9 @@ func (t *Tree) SearchRange(min, max interface{}) []*TreeNode {
 }
 
 // SearchRangeLowerBoundKeyWithLimit 查找大于等于key的limit个节点
-func (t *Tree) SearchRangeLowerBoundKeyWithLimit(key interface{}, limit int64) []*TreeNode {
+func (t *Tree[K, V]) SearchRangeLowerBoundKeyWithLimit(key K, limit int64) []*TreeNode[K, V] {
 	var count int64
-	list := make([]*TreeNode, 0, limit)
+	list := make([]*TreeNode[K, V], 0, limit)
 
 	iter := NewIteratorWithNode(t, t.lookupLowerBoundKey(key))
 	for iter.Next() {
@@ -164,9 +200,9 @@ func (t *Tree) SearchRangeLowerBoundKeyWithLimit(key interface{}, limit int64) [
 }
 
 // SearchRangeUpperBoundKeyWithLimit 找到小于等于key的limit个节点
-func (t *Tree) SearchRangeUpperBoundKeyWithLimit(key interface{}, limit int64) []*TreeNode {
+func (t *Tree[K, V]) SearchRangeUpperBoundKeyWithLimit(key K, limit int64) []*TreeNode[K, V] {
 	var count int64
-	list := make([]*TreeNode, 0, limit)
+	list := make([]*TreeNode[K, V], 0, limit)
 
 	iter := NewIteratorWithNode(t, t.lookupUpperBoundKey(key))
 	for iter.Prev() {
@@ -181,8 +217,128 @@ func (t *Tree) SearchRangeUpperBoundKeyWithLimit(key interface{}, limit int64) [
 	return reverse(list)
 }
 
+// Put 插入key、value，key已存在时更新value
+func (t *Tree[K, V]) Put(key K, val V) {
+	if t.multi {
+		if node := t.lookupFirst(key); node != nil {
+			node.entry.SetValue(val)
+			t.augmentPath(node)
+			return
+		}
+	}
+
+	t.Insert(key, val)
+}
+
+// Get 查找key对应的value，multimap模式下返回最早插入的value
+func (t *Tree[K, V]) Get(key K) (val V, bFound bool) {
+	_, val, bFound = t.lookupFirst(key).unpack()
+	return val, bFound
+}
+
+// Len 节点数
+func (t *Tree[K, V]) Len() int {
+	return t.size
+}
+
+// Min 最小的key和对应的value
+func (t *Tree[K, V]) Min() (K, V, bool) {
+	return t.minimum().unpack()
+}
+
+// Max 最大的key和对应的value
+func (t *Tree[K, V]) Max() (K, V, bool) {
+	return t.maximum().unpack()
+}
+
+// Floor 小于等于key的最大key和对应的value
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	return t.lookupUpperBoundKey(key).unpack()
+}
+
+// Ceiling 大于等于key的最小key和对应的value
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	return t.lookupLowerBoundKey(key).unpack()
+}
+
+// Lower 小于key的最大key和对应的value
+func (t *Tree[K, V]) Lower(key K) (K, V, bool) {
+	return t.lookupLess(key).unpack()
+}
+
+// Higher 大于key的最小key和对应的value
+func (t *Tree[K, V]) Higher(key K) (K, V, bool) {
+	return t.lookupGreater(key).unpack()
+}
+
+// PopMin 删除最小的key，并返回最小的key和对应的value
+func (t *Tree[K, V]) PopMin() (key K, val V, bFound bool) {
+	node := t.minimum()
+	if node == nil {
+		return key, val, false
+	}
+
+	key, val, bFound = node.unpack()
+	t.deleteNode(node)
+	return key, val, bFound
+}
+
+// PopMax 删除最大的key，并返回最大的key和对应的value
+func (t *Tree[K, V]) PopMax() (key K, val V, bFound bool) {
+	node := t.maximum()
+	if node == nil {
+		return key, val, false
+	}
+
+	key, val, bFound = node.unpack()
+	t.deleteNode(node)
+	return key, val, bFound
+}
+
+// Range 按key升序遍历key在[lo, hi]之间的节点
+func (t *Tree[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := t.lookupLowerBoundKey(lo); node != nil; node = node.next() {
+			if t.comparator(node.GetKey(), hi) > 0 {
+				return
+			}
+
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// All 按key升序遍历所有节点
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := t.minimum(); node != nil; node = node.next() {
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 按key降序遍历所有节点
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := t.maximum(); node != nil; node = node.prev() {
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// Iterator 从最小key开始的迭代器
+func (t *Tree[K, V]) Iterator() orderedmap.Iterator[K, V] {
+	return NewIterator(t)
+}
+
 // String String
-func (t *Tree) String() string {
+func (t *Tree[K, V]) String() string {
 	buffer := bytes.Buffer{}
 	node := t.root
 	stack := list.New()
@@ -194,7 +350,7 @@ func (t *Tree) String() string {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		node = e.(*TreeNode)
+		node = e.(*TreeNode[K, V])
 
 		if node.parent != nil {
 			buffer.WriteString(fmt.Sprintf("节点为: %v, \t节点的优先级为: %v, \t父节点为: %v\n", node.GetKey(), node.priority, node.parent.GetKey()))
@@ -213,9 +369,9 @@ func (t *Tree) String() string {
 }
 
 // Verify 验证是否是treap
-func (t *Tree) Verify() bool {
+func (t *Tree[K, V]) Verify() bool {
 	node := t.root
-	entries := make([]*utils.Entry, 0)
+	entries := make([]*utils.TypedEntry[K, V], 0)
 	stack := list.New()
 
 	for !node.isSentinel() {
@@ -225,7 +381,7 @@ func (t *Tree) Verify() bool {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		node = e.(*TreeNode)
+		node = e.(*TreeNode[K, V])
 
 		// 验证左节点的优先级
 		if !node.left.isSentinel() {
@@ -243,6 +399,16 @@ func (t *Tree) Verify() bool {
 			}
 		}
 
+		if node.size != node.left.count()+node.right.count()+1 {
+			fmt.Printf("节点[%v]的节点数错误\n", node.GetKey())
+			return false
+		}
+
+		if (!node.left.isSentinel() && node.left.parent != node) || (!node.right.isSentinel() && node.right.parent != node) {
+			fmt.Printf("节点[%v]的子节点的父节点错误\n", node.GetKey())
+			return false
+		}
+
 		entries = append(entries, node.entry)
 
 		node = node.right
@@ -252,9 +418,9 @@ func (t *Tree) Verify() bool {
 		}
 	}
 
-	// 验证顺序
+	// 验证顺序，multimap模式下允许相同的key
 	for i := 0; i < len(entries)-1; i++ {
-		if t.comparator.Compare(entries[i].GetKey(), entries[i+1].GetKey()) == utils.Gt {
+		if res := t.comparator(entries[i].GetKey(), entries[i+1].GetKey()); res > 0 || (res == 0 && !t.multi) {
 			fmt.Printf("Key顺序错误\n")
 			return false
 		}
@@ -269,7 +435,7 @@ func (t *Tree) Verify() bool {
 }
 
 // Dot Dot
-func (t *Tree) Dot() error {
+func (t *Tree[K, V]) Dot() error {
 	node := t.root
 	if node.isSentinel() {
 		return nil
@@ -288,7 +454,7 @@ func (t *Tree) Dot() error {
 
 	for stack.Len() != 0 {
 		e := stack.Remove(stack.Back())
-		node = e.(*TreeNode)
+		node = e.(*TreeNode[K, V])
 
 		dNode, dEdge := node.dot()
 		dGraph.AddNode(dNode)
@@ -328,7 +494,7 @@ func (t *Tree) Dot() error {
 }
 
 // updateChild 更新父节点的子节点
-func (t *Tree) updateChildren(parent, children *TreeNode, isLeft bool) {
+func (t *Tree[K, V]) updateChildren(parent, children *TreeNode[K, V], isLeft bool) {
 	if parent == nil {
 		t.root = children
 	} else if isLeft {
@@ -338,18 +504,56 @@ func (t *Tree) updateChildren(parent, children *TreeNode, isLeft bool) {
 	}
 }
 
+// leftRotate 左旋，并更新旋转后节点的聚合值
+func (t *Tree[K, V]) leftRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	r := node.leftRotate()
+	t.augment(node)
+	t.augment(r)
+
+	return r
+}
+
+// rightRotate 右旋，并更新旋转后节点的聚合值
+func (t *Tree[K, V]) rightRotate(node *TreeNode[K, V]) *TreeNode[K, V] {
+	l := node.rightRotate()
+	t.augment(node)
+	t.augment(l)
+
+	return l
+}
+
+// augment 重新计算node的节点数和聚合值
+func (t *Tree[K, V]) augment(node *TreeNode[K, V]) {
+	node.size = node.left.count() + node.right.count() + 1
+
+	if t.augmenter != nil {
+		t.augmenter.update(node)
+	}
+}
+
+// augmentPath 重新计算从node到根节点路径上的节点数和聚合值
+func (t *Tree[K, V]) augmentPath(node *TreeNode[K, V]) {
+	for ; node != nil; node = node.parent {
+		t.augment(node)
+	}
+}
+
 // minimum 中序遍历后，树的最小节点
-func (t *Tree) minimum() *TreeNode {
+func (t *Tree[K, V]) minimum() *TreeNode[K, V] {
 	return t.root.minimum()
 }
 
 // maximum 中序遍历后，树的最大节点
-func (t *Tree) maximum() *TreeNode {
+func (t *Tree[K, V]) maximum() *TreeNode[K, V] {
 	return t.root.maximum()
 }
 
-// rand 生成随机数 xorshift
-func (t *Tree) rand() uint32 {
+// rand 生成随机数，指定了随机数源时使用随机数源，否则使用xorshift
+func (t *Tree[K, V]) rand() uint32 {
+	if t.source != nil {
+		return uint32(t.source.Int63())
+	}
+
 	x := t.seed
 	x ^= x << 13
 	x ^= x >> 17
@@ -359,16 +563,16 @@ func (t *Tree) rand() uint32 {
 }
 
 // lookup 查找key所在的节点
-func (t *Tree) lookup(key interface{}) *TreeNode {
+func (t *Tree[K, V]) lookup(key K) *TreeNode[K, V] {
 	node := t.root
 
 	for !node.isSentinel() {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		if res == 0 {
 			return node
 		}
 
-		if res == utils.Lt {
+		if res < 0 {
 			node = node.right
 		} else {
 			node = node.left
@@ -378,18 +582,23 @@ func (t *Tree) lookup(key interface{}) *TreeNode {
 	return nil
 }
 
-// lookupLowerBoundKey 查找第一个大于等于key的node
-func (t *Tree) lookupLowerBoundKey(key interface{}) *TreeNode {
-	var last *TreeNode
+// lookupLowerBoundKey 查找第一个大于等于key的node，multimap模式下key相同时为最早插入的node
+func (t *Tree[K, V]) lookupLowerBoundKey(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
 	node := t.root
 
+	if node.isSentinel() {
+		return nil
+	}
+
 	for {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		// multimap模式下key相同时继续在左子树中查找最早插入的节点
+		if res == 0 && !t.multi {
 			return node
 		}
 
-		if res == utils.Lt {
+		if res < 0 {
 			if node.right.isSentinel() {
 				return last
 			}
@@ -406,18 +615,23 @@ func (t *Tree) lookupLowerBoundKey(key interface{}) *TreeNode {
 	}
 }
 
-// lookupUpperBoundKey 最后一个小于等于key的node
-func (t *Tree) lookupUpperBoundKey(key interface{}) *TreeNode {
-	var last *TreeNode
+// lookupUpperBoundKey 最后一个小于等于key的node，multimap模式下key相同时为最后插入的node
+func (t *Tree[K, V]) lookupUpperBoundKey(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
 	node := t.root
 
+	if node.isSentinel() {
+		return nil
+	}
+
 	for {
-		res := t.comparator.Compare(node.GetKey(), key)
-		if res == utils.Et {
+		res := t.comparator(node.GetKey(), key)
+		// multimap模式下key相同时继续在右子树中查找最后插入的节点
+		if res == 0 && !t.multi {
 			return node
 		}
 
-		if res == utils.Lt {
+		if res <= 0 {
 			if node.right.isSentinel() {
 				return node
 			}
@@ -433,3 +647,37 @@ func (t *Tree) lookupUpperBoundKey(key interface{}) *TreeNode {
 		}
 	}
 }
+
+// lookupLess 最后一个小于key的node
+func (t *Tree[K, V]) lookupLess(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
+	node := t.root
+
+	for !node.isSentinel() {
+		if t.comparator(node.GetKey(), key) < 0 {
+			last = node
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+
+	return last
+}
+
+// lookupGreater 第一个大于key的node
+func (t *Tree[K, V]) lookupGreater(key K) *TreeNode[K, V] {
+	var last *TreeNode[K, V]
+	node := t.root
+
+	for !node.isSentinel() {
+		if t.comparator(node.GetKey(), key) > 0 {
+			last = node
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+
+	return last
+}
@@ -4,10 +4,23 @@ import (
 	"math/rand"
 	"testing"
 	"time"
+
+	"github.com/asinglestep/gods/utils"
 )
 
 func Benchmark_TreapRandInsert(b *testing.B) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
+	var num = 10000000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+
+	for _, v := range array {
+		tree.Insert(v, v)
+	}
+}
+
+func Benchmark_TreapOrderedRandInsert(b *testing.B) {
+	tree := NewOrderedTree[int, int]()
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -18,7 +31,7 @@ func Benchmark_TreapRandInsert(b *testing.B) {
 }
 
 func Benchmark_TreapRandDelete(b *testing.B) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -35,7 +48,7 @@ func Benchmark_TreapRandDelete(b *testing.B) {
 }
 
 func Benchmark_TreapRandSearch(b *testing.B) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 10000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -49,3 +62,57 @@ func Benchmark_TreapRandSearch(b *testing.B) {
 		tree.Search(v)
 	}
 }
+
+func Benchmark_TreapUnion(b *testing.B) {
+	var num = 100000
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	array1, array2 := r.Perm(num * 2)[:num], r.Perm(num * 2)[:num]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		t1, t2 := NewOrderedTree[int, int](), NewOrderedTree[int, int]()
+		for j := range array1 {
+			t1.Insert(array1[j], j)
+			t2.Insert(array2[j], j)
+		}
+		b.StartTimer()
+
+		Union(t1, t2, nil)
+	}
+}
+
+func Benchmark_TreapUnionInsert(b *testing.B) {
+	var num = 100000
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	array1, array2 := r.Perm(num * 2)[:num], r.Perm(num * 2)[:num]
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		t1, t2 := NewOrderedTree[int, int](), NewOrderedTree[int, int]()
+		for j := range array1 {
+			t1.Insert(array1[j], j)
+			t2.Insert(array2[j], j)
+		}
+		b.StartTimer()
+
+		// 遍历t2逐个插入t1
+		for k, v := range t2.All() {
+			t1.Insert(k, v)
+		}
+	}
+}
+
+func Benchmark_ImplicitTreapInsertMiddle(b *testing.B) {
+	var num = 100000
+
+	for i := 0; i < b.N; i++ {
+		s := NewImplicit[int]()
+		for j := 0; j < num; j++ {
+			s.InsertAt(s.Len()/2, j)
+		}
+	}
+}
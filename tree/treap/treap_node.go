@@ -8,42 +8,48 @@ import (
 )
 
 // TreeNode treap节点
-type TreeNode struct {
-	left     *TreeNode
-	right    *TreeNode
-	parent   *TreeNode
-	priority uint32       // 优先级
-	entry    *utils.Entry // 数据
+type TreeNode[K, V any] struct {
+	left     *TreeNode[K, V]
+	right    *TreeNode[K, V]
+	parent   *TreeNode[K, V]
+	priority uint32                  // 优先级
+	reversed bool                    // 隐式treap中以当前节点为根节点的子树是否需要翻转，翻转标记还没有下推到子节点
+	size     int                     // 以当前节点为根节点的子树的节点数
+	entry    *utils.TypedEntry[K, V] // 数据
+	agg      any                     // 以当前节点为根节点的子树的聚合值，没有注册聚合值时为nil
 }
 
-// Sentinel Sentinel
-var Sentinel = &TreeNode{
-	entry: nil,
+// newSentinel 新建一个哨兵节点
+func newSentinel[K, V any]() *TreeNode[K, V] {
+	return &TreeNode[K, V]{
+		entry: nil,
+	}
 }
 
-// NewTreeNode NewTreeNode
-func NewTreeNode(entry *utils.Entry, priority uint32) *TreeNode {
-	node := &TreeNode{}
+// NewTreeNode 新建一个节点，sentinel为节点所在树的哨兵节点
+func NewTreeNode[K, V any](entry *utils.TypedEntry[K, V], priority uint32, sentinel *TreeNode[K, V]) *TreeNode[K, V] {
+	node := &TreeNode[K, V]{}
 	node.entry = entry
 	node.priority = priority
-	node.left = Sentinel
-	node.right = Sentinel
+	node.size = 1
+	node.left = sentinel
+	node.right = sentinel
 
 	return node
 }
 
 // GetKey 获取key
-func (node *TreeNode) GetKey() interface{} {
+func (node *TreeNode[K, V]) GetKey() K {
 	return node.entry.GetKey()
 }
 
 // GetValue 获取value
-func (node *TreeNode) GetValue() interface{} {
+func (node *TreeNode[K, V]) GetValue() V {
 	return node.entry.GetValue()
 }
 
 // leftRotate 左旋
-func (node *TreeNode) leftRotate() *TreeNode {
+func (node *TreeNode[K, V]) leftRotate() *TreeNode[K, V] {
 	r := node.right
 	rl := r.left
 
@@ -61,7 +67,7 @@ func (node *TreeNode) leftRotate() *TreeNode {
 }
 
 // rightRotate 右旋
-func (node *TreeNode) rightRotate() *TreeNode {
+func (node *TreeNode[K, V]) rightRotate() *TreeNode[K, V] {
 	l := node.left
 	lr := l.right
 
@@ -79,7 +85,7 @@ func (node *TreeNode) rightRotate() *TreeNode {
 }
 
 // isLeft 是否是左节点
-func (node *TreeNode) isLeft() bool {
+func (node *TreeNode[K, V]) isLeft() bool {
 	// node为根节点
 	if node.parent == nil {
 		return false
@@ -89,7 +95,7 @@ func (node *TreeNode) isLeft() bool {
 }
 
 // isRight 是否是右节点
-func (node *TreeNode) isRight() bool {
+func (node *TreeNode[K, V]) isRight() bool {
 	// node为根节点
 	if node.parent == nil {
 		return false
@@ -98,13 +104,31 @@ func (node *TreeNode) isRight() bool {
 	return node == node.parent.right
 }
 
+// count 以当前节点为根节点的子树的节点数，哨兵节点返回0
+func (node *TreeNode[K, V]) count() int {
+	if node.isSentinel() {
+		return 0
+	}
+
+	return node.size
+}
+
 // isSentinel 是否是哨兵节点
-func (node *TreeNode) isSentinel() bool {
+func (node *TreeNode[K, V]) isSentinel() bool {
 	return node.entry == nil
 }
 
+// unpack 获取节点的key和value，node为nil时返回false
+func (node *TreeNode[K, V]) unpack() (key K, val V, bFound bool) {
+	if node == nil {
+		return key, val, false
+	}
+
+	return node.GetKey(), node.GetValue(), true
+}
+
 // minimum 以当前节点为根节点，中序遍历后，树的最小节点
-func (node *TreeNode) minimum() *TreeNode {
+func (node *TreeNode[K, V]) minimum() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -117,7 +141,7 @@ func (node *TreeNode) minimum() *TreeNode {
 }
 
 // maximum 以当前节点为根节点，中序遍历后，树的最大节点
-func (node *TreeNode) maximum() *TreeNode {
+func (node *TreeNode[K, V]) maximum() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -130,7 +154,7 @@ func (node *TreeNode) maximum() *TreeNode {
 }
 
 // next 中序遍历node的下一个节点
-func (node *TreeNode) next() *TreeNode {
+func (node *TreeNode[K, V]) next() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -150,7 +174,7 @@ func (node *TreeNode) next() *TreeNode {
 }
 
 // prev 中序遍历node的上一个节点
-func (node *TreeNode) prev() *TreeNode {
+func (node *TreeNode[K, V]) prev() *TreeNode[K, V] {
 	if node.isSentinel() {
 		return nil
 	}
@@ -170,7 +194,7 @@ func (node *TreeNode) prev() *TreeNode {
 }
 
 // free free
-func (node *TreeNode) free() {
+func (node *TreeNode[K, V]) free() {
 	node.parent = nil
 	node.left = nil
 	node.right = nil
@@ -178,18 +202,18 @@ func (node *TreeNode) free() {
 }
 
 // dot dot
-func (node *TreeNode) dot() (dNode *dot.Node, dEdge *dot.Edge) {
+func (node *TreeNode[K, V]) dot() (dNode *dot.Node, dEdge *dot.Edge) {
 	// 添加node
 	dNode = &dot.Node{}
-	dNode.Name = fmt.Sprintf("%d", node.entry.GetKey())
+	dNode.Name = fmt.Sprintf("%v", node.entry.GetKey())
 	dNode.Attr = map[string]string{
-		"label": fmt.Sprintf("\"<f0> | k: %d、 p: %d | <f1> \"", node.entry.GetKey(), node.priority),
+		"label": fmt.Sprintf("\"<f0> | k: %v、 p: %d | <f1> \"", node.entry.GetKey(), node.priority),
 	}
 
 	// 添加edge
 	if node.parent != nil {
 		dEdge = &dot.Edge{}
-		dEdge.Src = fmt.Sprintf("%d", node.parent.entry.GetKey())
+		dEdge.Src = fmt.Sprintf("%v", node.parent.entry.GetKey())
 
 		if node.isLeft() {
 			dEdge.SrcPort = ":f0"
@@ -197,14 +221,14 @@ func (node *TreeNode) dot() (dNode *dot.Node, dEdge *dot.Edge) {
 			dEdge.SrcPort = ":f1"
 		}
 
-		dEdge.Dst = fmt.Sprintf("%d", node.entry.GetKey())
+		dEdge.Dst = fmt.Sprintf("%v", node.entry.GetKey())
 	}
 
 	return dNode, dEdge
 }
 
 // reverse 倒序
-func reverse(list []*TreeNode) []*TreeNode {
+func reverse[K, V any](list []*TreeNode[K, V]) []*TreeNode[K, V] {
 	listLen := len(list)
 
 	for i := 0; i < listLen/2; i++ {
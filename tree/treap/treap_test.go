@@ -1,32 +1,21 @@
 package treap
 
 import (
+	"iter"
 	"math/rand"
+	"slices"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
-)
-
-type treapComparator struct {
-}
 
-// Compare Compare
-func (tc treapComparator) Compare(k1, k2 interface{}) int {
-	i1 := k1.(int)
-	i2 := k2.(int)
-
-	if i1 > i2 {
-		return 1
-	}
-
-	if i1 < i2 {
-		return -1
-	}
-
-	return 0
-}
+	"github.com/asinglestep/gods/map/orderedmap"
+	"github.com/asinglestep/gods/map/orderedmap/conformance"
+	"github.com/asinglestep/gods/utils"
+)
 
 func Test_TreapRandInsert(t *testing.T) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -58,7 +47,7 @@ func Test_TreapRandInsert(t *testing.T) {
 }
 
 func Test_TreapRandDelete(t *testing.T) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -95,7 +84,7 @@ func Test_TreapRandDelete(t *testing.T) {
 }
 
 func Test_TreapRandSearch(t *testing.T) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -116,7 +105,7 @@ func Test_TreapRandSearch(t *testing.T) {
 }
 
 func Test_TreapSearchRange(t *testing.T) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(30, 30)
 	tree.Insert(20, 20)
@@ -147,7 +136,7 @@ func Test_TreapSearchRange(t *testing.T) {
 }
 
 func Test_TreapSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(30, 30)
 	tree.Insert(20, 20)
@@ -177,7 +166,7 @@ func Test_TreapSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_TreapRandSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -204,7 +193,7 @@ func Test_TreapRandSearchRangeLowerBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_TreapSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 
 	tree.Insert(30, 30)
 	tree.Insert(20, 20)
@@ -234,7 +223,7 @@ func Test_TreapSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
 }
 
 func Test_TreapRandSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
-	tree := NewTree(treapComparator{})
+	tree := NewTree(utils.IntComparator)
 	var num = 1000000
 
 	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
@@ -262,3 +251,778 @@ func Test_TreapRandSearchRangeUpperBoundKeyWithLimit(t *testing.T) {
 		}
 	}
 }
+
+func Test_TreapOrderedTree(t *testing.T) {
+	tree := NewOrderedTree[int, string]()
+	var num = 100000
+
+	array := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num)
+	for _, v := range array {
+		tree.Insert(v, strconv.Itoa(v))
+	}
+
+	dArray := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(num / 2)
+	for _, v := range dArray {
+		tree.Delete(v)
+	}
+
+	if !tree.Verify() {
+		t.Fatal("Test_TreapOrderedTree err")
+	}
+
+	idx := num / 2
+	iter := NewIterator(tree)
+	for iter.Next() {
+		if iter.GetKey() != idx || iter.GetValue() != strconv.Itoa(idx) {
+			t.Fatalf("want %v, got %v: %v\n", idx, iter.GetKey(), iter.GetValue())
+		}
+
+		idx++
+	}
+
+	if idx != num {
+		t.Fatalf("want %v, got %v\n", num, idx)
+	}
+}
+
+func Test_TreapConformance(t *testing.T) {
+	conformance.Run(t, func() orderedmap.OrderedMap[int, int] {
+		return NewOrderedTree[int, int]()
+	})
+}
+
+// sumAgg 测试用的聚合值，first为区间中最小的key，用来检查合并的顺序
+type sumAgg struct {
+	sum   int
+	cnt   int
+	first int
+}
+
+func Test_TreapAugment(t *testing.T) {
+	tree := NewOrderedTree[int, int]()
+	num := 2000
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	// 注册前插入的节点
+	m := make(map[int]int)
+	for _, v := range r.Perm(num)[:num/4] {
+		tree.Insert(v, v)
+		m[v] = v
+	}
+
+	aug := Augment(tree, Augmentation[int, int, sumAgg]{
+		Value: func(key, val int) sumAgg {
+			return sumAgg{sum: val, cnt: 1, first: key}
+		},
+		Combine: func(a, b sumAgg) sumAgg {
+			first := a.first
+			if a.cnt == 0 {
+				first = b.first
+			}
+
+			return sumAgg{sum: a.sum + b.sum, cnt: a.cnt + b.cnt, first: first}
+		},
+	})
+
+	for i := 0; i < num*4; i++ {
+		k := r.Intn(num)
+		if r.Intn(3) == 0 {
+			tree.Delete(k)
+			delete(m, k)
+		} else {
+			tree.Insert(k, i)
+			m[k] = i
+		}
+	}
+
+	if !tree.Verify() {
+		t.Fatalf("Verify Error\n")
+	}
+
+	for i := 0; i < 1000; i++ {
+		lo := r.Intn(num+2) - 1
+		hi := r.Intn(num+2) - 1
+
+		want := sumAgg{}
+		for k := hi; k >= lo; k-- {
+			if v, ok := m[k]; ok {
+				want.sum += v
+				want.cnt++
+				want.first = k
+			}
+		}
+
+		if got := aug.Query(lo, hi); got != want {
+			t.Fatalf("Query(%v, %v) want %+v, got %+v\n", lo, hi, want, got)
+		}
+	}
+
+	if got := aug.Total(); got.cnt != len(m) {
+		t.Fatalf("Total want %v, got %+v\n", len(m), got)
+	}
+
+	if got := aug.Aggregate(nil); got != (sumAgg{}) {
+		t.Fatalf("Aggregate(nil) want Identity, got %+v\n", got)
+	}
+}
+
+func Test_TreapConcurrentTrees(t *testing.T) {
+	// 每棵树有自己的哨兵节点，不同goroutine中操作不同的树不会产生数据竞争，使用go test -race验证
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+
+			tree := NewOrderedTree[int, int]()
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 2000; j++ {
+				tree.Insert(r.Intn(500), j)
+				tree.Delete(r.Intn(500))
+			}
+
+			if !tree.Verify() {
+				t.Errorf("seed %v: Verify Error\n", seed)
+			}
+		}(int64(i))
+	}
+
+	wg.Wait()
+}
+
+func Test_TreapSplitJoin(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	for round := 0; round < 50; round++ {
+		num := r.Intn(2000)
+		tree := NewOrderedTree[int, int]()
+		for _, v := range r.Perm(num) {
+			tree.Insert(v*2, v)
+		}
+
+		// 拆分点可能在所有key之前、之后或者不存在于树中
+		key := r.Intn(num*2+3) - 1
+		left, right := tree.Split(key)
+		if tree.Len() != 0 || !tree.Verify() {
+			t.Fatalf("seed %v: tree should be empty after Split, got %v\n", seed, tree.Len())
+		}
+
+		if !left.Verify() || !right.Verify() {
+			t.Fatalf("seed %v: Split(%v) Verify Error\n", seed, key)
+		}
+
+		if left.Len()+right.Len() != num {
+			t.Fatalf("seed %v: Split(%v) want %v keys, got %v + %v\n", seed, key, num, left.Len(), right.Len())
+		}
+
+		for k := range left.All() {
+			if k >= key {
+				t.Fatalf("seed %v: Split(%v) left has key %v\n", seed, key, k)
+			}
+		}
+
+		for k := range right.All() {
+			if k < key {
+				t.Fatalf("seed %v: Split(%v) right has key %v\n", seed, key, k)
+			}
+		}
+
+		// 拆分后的树可以继续插入、删除
+		left.Insert(-2, -1)
+		right.Delete(key)
+
+		joined, ok := Join(left, right)
+		if !ok {
+			t.Fatalf("seed %v: Join want ok\n", seed)
+		}
+
+		if !joined.Verify() || left.Len() != 0 || right.Len() != 0 {
+			t.Fatalf("seed %v: Join Verify Error\n", seed)
+		}
+
+		last := -3
+		for k, v := range joined.All() {
+			if k <= last || (k >= 0 && k != v*2) {
+				t.Fatalf("seed %v: Join got key %v value %v after %v\n", seed, k, v, last)
+			}
+
+			last = k
+		}
+	}
+}
+
+func Test_TreapJoinOverlap(t *testing.T) {
+	left, right := NewOrderedTree[int, int](), NewOrderedTree[int, int]()
+	for i := 0; i < 10; i++ {
+		left.Insert(i, i)
+		right.Insert(i+9, i+9)
+	}
+
+	if _, ok := Join(left, right); ok {
+		t.Fatalf("Join overlapping trees want false\n")
+	}
+
+	if left.Len() != 10 || right.Len() != 10 || !left.Verify() || !right.Verify() {
+		t.Fatalf("Join overlapping trees should not change the trees\n")
+	}
+
+	right.Delete(9)
+	joined, ok := Join(left, right)
+	if !ok || joined.Len() != 19 || !joined.Verify() {
+		t.Fatalf("Join want 19 keys, got %v %v\n", joined.Len(), ok)
+	}
+
+	empty := NewOrderedTree[int, int]()
+	if joined, ok = Join(joined, empty); !ok || joined.Len() != 19 || !joined.Verify() {
+		t.Fatalf("Join with empty tree want 19 keys, got %v %v\n", joined.Len(), ok)
+	}
+}
+
+func Test_TreapSplitConcurrent(t *testing.T) {
+	// 拆分后的两棵树共享原来的哨兵节点，在不同goroutine中操作不会产生数据竞争，使用go test -race验证
+	tree := NewOrderedTree[int, int]()
+	for i := 0; i < 1000; i++ {
+		tree.Insert(i, i)
+	}
+
+	left, right := tree.Split(500)
+
+	var wg sync.WaitGroup
+	for i, part := range []*Tree[int, int]{left, right} {
+		wg.Add(1)
+		go func(seed int64, part *Tree[int, int]) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(seed))
+			for j := 0; j < 2000; j++ {
+				part.Insert(int(seed)*500+r.Intn(500), j)
+				part.Delete(int(seed)*500 + r.Intn(500))
+			}
+
+			if !part.Verify() {
+				t.Errorf("seed %v: Verify Error\n", seed)
+			}
+		}(int64(i), part)
+	}
+
+	wg.Wait()
+}
+
+func Test_TreapSetOps(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	// build 随机生成一棵树和对应的map
+	build := func(num, max int) (*Tree[int, int], map[int]int) {
+		tree := NewOrderedTree[int, int]()
+		m := map[int]int{}
+		for i := 0; i < num; i++ {
+			key := r.Intn(max)
+			tree.Insert(key, key*10+1)
+			m[key] = key*10 + 1
+		}
+
+		return tree, m
+	}
+
+	check := func(name string, tree *Tree[int, int], want map[int]int) {
+		if !tree.Verify() || tree.Len() != len(want) {
+			t.Fatalf("seed %v: %v want %v keys, got %v\n", seed, name, len(want), tree.Len())
+		}
+
+		for k, v := range tree.All() {
+			if w, ok := want[k]; !ok || w != v {
+				t.Fatalf("seed %v: %v key %v want %v %v, got %v\n", seed, name, k, w, ok, v)
+			}
+		}
+	}
+
+	// 节点数较多时会并行执行，使用go test -race验证
+	for _, size := range [][2]int{{0, 10}, {10, 0}, {100, 100}, {1000, 10}, {10, 1000}, {20000, 5000}} {
+		max := (size[0] + size[1]) * 2
+
+		t1, m1 := build(size[0], max)
+		t2, m2 := build(size[1], max)
+		for k := range m2 {
+			t2.Insert(k, 2)
+			m2[k] = 2
+		}
+
+		want := map[int]int{}
+		for k, v := range m1 {
+			want[k] = v
+		}
+
+		for k, v := range m2 {
+			want[k] += v
+		}
+
+		union := Union(t1, t2, func(key, v1, v2 int) int { return v1 + v2 })
+		check("Union", union, want)
+		if t1.Len() != 0 || t2.Len() != 0 {
+			t.Fatalf("seed %v: t1, t2 should be empty after Union\n", seed)
+		}
+
+		t1, m1 = build(size[0], max)
+		t2, m2 = build(size[1], max)
+		want = map[int]int{}
+		for k, v := range m1 {
+			if _, ok := m2[k]; ok {
+				want[k] = v
+			}
+		}
+
+		check("Intersection", Intersection(t1, t2), want)
+
+		t1, m1 = build(size[0], max)
+		t2, m2 = build(size[1], max)
+		want = map[int]int{}
+		for k, v := range m1 {
+			if _, ok := m2[k]; !ok {
+				want[k] = v
+			}
+		}
+
+		check("Difference", Difference(t1, t2), want)
+	}
+}
+
+func Test_ImplicitTreap(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	s := NewImplicit[int]()
+	var want []int
+
+	check := func(op int) {
+		if !s.Verify() || s.Len() != len(want) {
+			t.Fatalf("seed %v op %v: want %v elements, got %v\n", seed, op, len(want), s.Len())
+		}
+
+		for i, v := range s.All() {
+			if want[i] != v {
+				t.Fatalf("seed %v op %v: index %v want %v, got %v\n", seed, op, i, want[i], v)
+			}
+		}
+
+		for i, v := range s.Backward() {
+			if want[i] != v {
+				t.Fatalf("seed %v op %v: Backward index %v want %v, got %v\n", seed, op, i, want[i], v)
+			}
+		}
+	}
+
+	for op := 0; op < 3000; op++ {
+		i := r.Intn(len(want) + 1)
+		j := i + r.Intn(len(want)-i+1)
+
+		switch r.Intn(8) {
+		case 0, 1, 2:
+			s.InsertAt(i, op)
+			want = append(want[:i], append([]int{op}, want[i:]...)...)
+		case 3:
+			if i < len(want) {
+				if v, ok := s.DeleteAt(i); !ok || v != want[i] {
+					t.Fatalf("seed %v op %v: DeleteAt(%v) want %v, got %v %v\n", seed, op, i, want[i], v, ok)
+				}
+
+				want = append(want[:i], want[i+1:]...)
+			}
+		case 4:
+			if i < len(want) {
+				s.Set(i, -op)
+				want[i] = -op
+			}
+		case 5:
+			s.RangeReverse(i, j)
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				want[a], want[b] = want[b], want[a]
+			}
+		case 6:
+			// 拆分出[i, j)，翻转之后追加到末尾
+			slice, ok := s.Slice(i, j)
+			if !ok || !slice.Verify() || slice.Len() != j-i {
+				t.Fatalf("seed %v op %v: Slice(%v, %v) Error\n", seed, op, i, j)
+			}
+
+			slice.RangeReverse(0, slice.Len())
+			s.Concat(slice)
+			if slice.Len() != 0 {
+				t.Fatalf("seed %v op %v: slice should be empty after Concat\n", seed, op)
+			}
+
+			mid := append([]int{}, want[i:j]...)
+			for a, b := 0, len(mid)-1; a < b; a, b = a+1, b-1 {
+				mid[a], mid[b] = mid[b], mid[a]
+			}
+
+			want = append(append(want[:i:i], want[j:]...), mid...)
+		case 7:
+			if i < len(want) {
+				if v, ok := s.Get(i); !ok || v != want[i] {
+					t.Fatalf("seed %v op %v: Get(%v) want %v, got %v %v\n", seed, op, i, want[i], v, ok)
+				}
+			}
+		}
+
+		if op%50 == 0 {
+			check(op)
+		}
+	}
+
+	check(-1)
+
+	if _, ok := s.Get(s.Len()); ok {
+		t.Fatalf("Get(Len()) want false\n")
+	}
+
+	if s.InsertAt(-1, 0) || s.RangeReverse(1, 0) {
+		t.Fatalf("invalid position want false\n")
+	}
+}
+
+func Test_ImplicitTreapAugment(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	s := NewImplicit[int]()
+	aug := AugmentImplicit(s, Augmentation[struct{}, int, int]{
+		Identity: 0,
+		Value:    func(_ struct{}, val int) int { return val },
+		Combine:  func(a, b int) int { return a + b },
+	})
+
+	var want []int
+	for op := 0; op < 2000; op++ {
+		i := r.Intn(len(want) + 1)
+		j := i + r.Intn(len(want)-i+1)
+
+		switch r.Intn(5) {
+		case 0, 1:
+			val := r.Intn(10) + 1
+			s.InsertAt(i, val)
+			want = append(want[:i], append([]int{val}, want[i:]...)...)
+		case 2:
+			if i < len(want) {
+				s.DeleteAt(i)
+				want = append(want[:i], want[i+1:]...)
+			}
+		case 3:
+			if i < len(want) {
+				s.Set(i, 5)
+				want[i] = 5
+			}
+		case 4:
+			// 拆分出的序列和新的序列追加到末尾
+			slice, _ := s.Slice(i, j)
+			other := NewImplicit[int]()
+			other.Append(7)
+
+			s.Concat(slice)
+			s.Concat(other)
+			want = append(append(append(want[:i:i], want[j:]...), want[i:j]...), 7)
+		}
+
+		total := 0
+		for _, v := range want {
+			total += v
+		}
+
+		if aug.Total() != total {
+			t.Fatalf("seed %v op %v: Total want %v, got %v\n", seed, op, total, aug.Total())
+		}
+
+		// 查找前缀和大于target的第一个位置
+		target := r.Intn(total + 1)
+		idx, prefix := 0, 0
+		for idx < len(want) && prefix+want[idx] <= target {
+			prefix += want[idx]
+			idx++
+		}
+
+		i, got, ok := aug.Search(func(sum int) bool { return sum > target })
+		if i != idx || got != prefix || ok != (idx < len(want)) {
+			t.Fatalf("seed %v op %v: Search(> %v) want %v %v, got %v %v %v\n", seed, op, target, idx, prefix, i, got, ok)
+		}
+	}
+
+	for i, v := range s.Range(3, 10) {
+		if want[i] != v || i < 3 || i >= 10 {
+			t.Fatalf("seed %v: Range index %v want %v, got %v\n", seed, i, want[i], v)
+		}
+	}
+}
+
+// priorities 按key升序返回所有节点的优先级
+func priorities[K, V any](tree *Tree[K, V]) []uint32 {
+	var res []uint32
+	for node := tree.root.minimum(); node != nil; node = node.next() {
+		res = append(res, node.priority)
+	}
+
+	return res
+}
+
+func Test_TreapSeed(t *testing.T) {
+	keys := rand.New(rand.NewSource(time.Now().UnixNano())).Perm(1000)
+
+	build := func(opts ...Option) *Tree[int, int] {
+		tree := NewOrderedTree[int, int](opts...)
+		for _, k := range keys {
+			tree.Insert(k, k)
+		}
+
+		if !tree.Verify() {
+			t.Fatalf("Verify Error\n")
+		}
+
+		return tree
+	}
+
+	equal := func(a, b []uint32) bool {
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+
+		return len(a) == len(b)
+	}
+
+	if !equal(priorities(build(WithSeed(7))), priorities(build(WithSeed(7)))) {
+		t.Fatalf("same seed want same priorities\n")
+	}
+
+	if equal(priorities(build(WithSeed(7))), priorities(build(WithSeed(8)))) {
+		t.Fatalf("different seeds want different priorities\n")
+	}
+
+	if !equal(priorities(build(WithSource(rand.NewSource(7)))), priorities(build(WithSource(rand.NewSource(7))))) {
+		t.Fatalf("same source want same priorities\n")
+	}
+
+	// 默认种子为1，WithSeed(0)被忽略
+	if !equal(priorities(build()), priorities(build(WithSeed(0)))) {
+		t.Fatalf("WithSeed(0) should be ignored\n")
+	}
+}
+
+func Test_TreapPriority(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	tree := NewOrderedTree[int, int]()
+	want := map[int]uint32{} // key -> 优先级
+
+	for op := 0; op < 5000; op++ {
+		key := r.Intn(500)
+		priority := uint32(r.Intn(100))
+
+		switch r.Intn(4) {
+		case 0, 1:
+			tree.InsertWithPriority(key, key, priority)
+			want[key] = priority
+		case 2:
+			_, ok := want[key]
+			if tree.UpdatePriority(key, priority) != ok {
+				t.Fatalf("seed %v op %v: UpdatePriority(%v) want %v\n", seed, op, key, ok)
+			}
+
+			if ok {
+				want[key] = priority
+			}
+		case 3:
+			tree.Delete(key)
+			delete(want, key)
+		}
+
+		if op%100 != 0 {
+			continue
+		}
+
+		if !tree.Verify() || tree.Len() != len(want) {
+			t.Fatalf("seed %v op %v: Verify Error\n", seed, op)
+		}
+
+		for k, p := range want {
+			if got, ok := tree.GetPriority(k); !ok || got != p {
+				t.Fatalf("seed %v op %v: GetPriority(%v) want %v, got %v %v\n", seed, op, k, p, got, ok)
+			}
+		}
+
+		// 和遍历所有key的结果比较，优先级相同时可能返回其中任意一个
+		lo := r.Intn(500)
+		hi := lo + r.Intn(100)
+		for _, bounds := range [][2]int{{lo, hi}, {-1, 500}} {
+			maxPriority, bFound := uint32(0), false
+			for k, p := range want {
+				if k >= bounds[0] && k <= bounds[1] && (!bFound || p > maxPriority) {
+					maxPriority, bFound = p, true
+				}
+			}
+
+			k, v, p, ok := tree.MaxPriorityInRange(bounds[0], bounds[1])
+			if ok != bFound || p != maxPriority || (ok && (k < bounds[0] || k > bounds[1] || v != k || want[k] != p)) {
+				t.Fatalf("seed %v op %v: MaxPriorityInRange(%v, %v) want %v %v, got %v %v %v\n", seed, op, bounds[0], bounds[1], maxPriority, bFound, k, p, ok)
+			}
+		}
+
+		_, _, maxPriority, _ := tree.MaxPriorityInRange(-1, 500)
+		if _, _, p, ok := tree.PeekMaxPriority(); ok != (len(want) > 0) || p != maxPriority {
+			t.Fatalf("seed %v op %v: PeekMaxPriority want %v, got %v %v\n", seed, op, maxPriority, p, ok)
+		}
+	}
+}
+
+func Test_TreapMultimap(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	tree := NewOrderedTree[int, int](WithMultimap())
+	want := map[int][]int{}
+	size := 0
+
+	for i := 0; i < 20000; i++ {
+		key := r.Intn(100)
+
+		switch op := r.Intn(10); {
+		case op < 6:
+			tree.Insert(key, i)
+			want[key] = append(want[key], i)
+			size++
+		case op < 9:
+			if tree.DeleteOne(key) != (len(want[key]) > 0) {
+				t.Fatalf("seed %v: DeleteOne(%v) want %v\n", seed, key, len(want[key]) > 0)
+			}
+
+			if len(want[key]) > 0 {
+				want[key] = want[key][1:]
+				size--
+			}
+		default:
+			if n := tree.DeleteAll(key); n != len(want[key]) {
+				t.Fatalf("seed %v: DeleteAll(%v) want %v, got %v\n", seed, key, len(want[key]), n)
+			}
+
+			size -= len(want[key])
+			delete(want, key)
+		}
+
+		if tree.Count(key) != len(want[key]) {
+			t.Fatalf("seed %v: Count(%v) want %v, got %v\n", seed, key, len(want[key]), tree.Count(key))
+		}
+
+		if !slices.Equal(tree.GetAll(key), want[key]) {
+			t.Fatalf("seed %v: GetAll(%v) want %v, got %v\n", seed, key, want[key], tree.GetAll(key))
+		}
+
+		if val, ok := tree.Get(key); ok != (len(want[key]) > 0) || (ok && val != want[key][0]) {
+			t.Fatalf("seed %v: Get(%v) want first value %v, got %v\n", seed, key, want[key], val)
+		}
+	}
+
+	if !tree.Verify() || tree.Len() != size {
+		t.Fatalf("seed %v: Multimap Verify Error, want %v nodes, got %v\n", seed, size, tree.Len())
+	}
+
+	// 相同key的value按插入顺序排列
+	got := map[int][]int{}
+	for k, v := range tree.All() {
+		got[k] = append(got[k], v)
+	}
+
+	for k, vals := range want {
+		if !slices.Equal(got[k], vals) {
+			t.Fatalf("seed %v: All key %v want %v, got %v\n", seed, k, vals, got[k])
+		}
+	}
+
+	// Put更新最早插入的value，Delete删除所有value
+	tree.Insert(1000, 1)
+	tree.Insert(1000, 2)
+	tree.Put(1000, 3)
+	if !slices.Equal(tree.GetAll(1000), []int{3, 2}) {
+		t.Fatalf("Put want [3 2], got %v\n", tree.GetAll(1000))
+	}
+
+	// InsertWithPriority同样总是插入新节点
+	tree.InsertWithPriority(1000, 4, 1<<31)
+	if tree.Count(1000) != 3 || !tree.Verify() {
+		t.Fatalf("InsertWithPriority want 3 values, got %v\n", tree.GetAll(1000))
+	}
+
+	tree.Delete(1000)
+	if tree.Count(1000) != 0 || !tree.Verify() {
+		t.Fatalf("Delete want no value, got %v\n", tree.GetAll(1000))
+	}
+}
+
+func Test_TreapMultimapRange(t *testing.T) {
+	seed := time.Now().UnixNano()
+	r := rand.New(rand.NewSource(seed))
+
+	// key 0~9，每个key有55个value，value按插入顺序排列
+	tree := NewOrderedTree[int, int](WithMultimap())
+	want := map[int][]int{}
+	for _, i := range r.Perm(550) {
+		tree.Insert(i%10, i)
+		want[i%10] = append(want[i%10], i)
+	}
+
+	values := func(seq iter.Seq2[int, int]) []int {
+		res := []int{}
+		for _, v := range seq {
+			res = append(res, v)
+		}
+
+		return res
+	}
+
+	nodeValues := func(list []*TreeNode[int, int]) []int {
+		res := []int{}
+		for _, node := range list {
+			res = append(res, node.GetValue())
+		}
+
+		return res
+	}
+
+	if got := values(tree.Range(5, 5)); !slices.Equal(got, want[5]) {
+		t.Fatalf("seed %v: Range(5, 5) want %v, got %v\n", seed, want[5], got)
+	}
+
+	if got, all := values(tree.Range(3, 6)), slices.Concat(want[3], want[4], want[5], want[6]); !slices.Equal(got, all) {
+		t.Fatalf("seed %v: Range(3, 6) want %v, got %v\n", seed, all, got)
+	}
+
+	if got := nodeValues(tree.SearchRange(5, 5)); !slices.Equal(got, want[5]) {
+		t.Fatalf("seed %v: SearchRange(5, 5) want %v, got %v\n", seed, want[5], got)
+	}
+
+	if got := nodeValues(tree.SearchRangeLowerBoundKeyWithLimit(5, 3)); !slices.Equal(got, want[5][:3]) {
+		t.Fatalf("seed %v: SearchRangeLowerBoundKeyWithLimit(5, 3) want %v, got %v\n", seed, want[5][:3], got)
+	}
+
+	if got := nodeValues(tree.SearchRangeUpperBoundKeyWithLimit(5, 3)); !slices.Equal(got, want[5][52:]) {
+		t.Fatalf("seed %v: SearchRangeUpperBoundKeyWithLimit(5, 3) want %v, got %v\n", seed, want[5][52:], got)
+	}
+
+	// Ceiling为相同key中最早插入的value，Floor为最后插入的value
+	if k, v, ok := tree.Ceiling(5); !ok || k != 5 || v != want[5][0] {
+		t.Fatalf("seed %v: Ceiling(5) want 5: %v, got %v: %v %v\n", seed, want[5][0], k, v, ok)
+	}
+
+	if k, v, ok := tree.Floor(5); !ok || k != 5 || v != want[5][54] {
+		t.Fatalf("seed %v: Floor(5) want 5: %v, got %v: %v %v\n", seed, want[5][54], k, v, ok)
+	}
+
+	tree.DeleteAll(5)
+	if k, v, ok := tree.Ceiling(5); !ok || k != 6 || v != want[6][0] {
+		t.Fatalf("seed %v: Ceiling(5) want 6: %v, got %v: %v %v\n", seed, want[6][0], k, v, ok)
+	}
+
+	if k, v, ok := tree.Floor(5); !ok || k != 4 || v != want[4][54] {
+		t.Fatalf("seed %v: Floor(5) want 4: %v, got %v: %v %v\n", seed, want[4][54], k, v, ok)
+	}
+}
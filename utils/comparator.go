@@ -1,5 +1,11 @@
 package utils
 
+import (
+	"bytes"
+	"cmp"
+	"time"
+)
+
 const (
 	Lt = -1
 	Et = 0
@@ -13,3 +19,93 @@ type Comparator interface {
 	// k1 < k2, return -1
 	Compare(k1, k2 interface{}) int
 }
+
+// ComparatorFunc 将类型为T的比较函数适配为Comparator，比较结果会被规范为Lt、Et、Gt
+//
+// 例如: rbtree.NewTree(utils.ComparatorFunc[int](cmp.Compare[int]))
+type ComparatorFunc[T any] func(a, b T) int
+
+// Compare Compare
+func (f ComparatorFunc[T]) Compare(k1, k2 interface{}) int {
+	return sign(f(k1.(T), k2.(T)))
+}
+
+// 常用类型的Comparator
+var (
+	IntComparator     Comparator = ComparatorFunc[int](cmp.Compare[int])
+	Int8Comparator    Comparator = ComparatorFunc[int8](cmp.Compare[int8])
+	Int16Comparator   Comparator = ComparatorFunc[int16](cmp.Compare[int16])
+	Int32Comparator   Comparator = ComparatorFunc[int32](cmp.Compare[int32])
+	Int64Comparator   Comparator = ComparatorFunc[int64](cmp.Compare[int64])
+	UintComparator    Comparator = ComparatorFunc[uint](cmp.Compare[uint])
+	Uint8Comparator   Comparator = ComparatorFunc[uint8](cmp.Compare[uint8])
+	Uint16Comparator  Comparator = ComparatorFunc[uint16](cmp.Compare[uint16])
+	Uint32Comparator  Comparator = ComparatorFunc[uint32](cmp.Compare[uint32])
+	Uint64Comparator  Comparator = ComparatorFunc[uint64](cmp.Compare[uint64])
+	UintptrComparator Comparator = ComparatorFunc[uintptr](cmp.Compare[uintptr])
+	Float32Comparator Comparator = ComparatorFunc[float32](CompareFloat[float32])
+	Float64Comparator Comparator = ComparatorFunc[float64](CompareFloat[float64])
+	StringComparator  Comparator = ComparatorFunc[string](cmp.Compare[string])
+	BytesComparator   Comparator = ComparatorFunc[[]byte](CompareBytes)
+	TimeComparator    Comparator = ComparatorFunc[time.Time](CompareTime)
+)
+
+// CompareFloat NaN安全的浮点数比较
+//
+// NaN小于任何非NaN的数，NaN等于NaN，-0.0等于0.0，
+// 直接使用 < 和 > 比较时NaN和任何数都不相等也不大小，会破坏树的有序性
+func CompareFloat[T ~float32 | ~float64](a, b T) int {
+	return cmp.Compare(a, b)
+}
+
+// CompareBytes 按字典序比较[]byte，nil等于空切片
+func CompareBytes(a, b []byte) int {
+	return bytes.Compare(a, b)
+}
+
+// CompareTime 比较时间的先后
+func CompareTime(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+// Reverse 反转比较函数的顺序
+func Reverse[T any](comparator func(a, b T) int) func(a, b T) int {
+	return func(a, b T) int {
+		return -sign(comparator(a, b))
+	}
+}
+
+// Composite 按顺序使用多个比较函数进行字典序比较，前一个比较函数相等时才使用下一个
+//
+// 例如: 先按Name升序，再按Age降序
+// utils.Composite(utils.By(func(u User) string { return u.Name }), utils.Reverse(utils.By(func(u User) int { return u.Age })))
+func Composite[T any](comparators ...func(a, b T) int) func(a, b T) int {
+	return func(a, b T) int {
+		for _, comparator := range comparators {
+			if res := sign(comparator(a, b)); res != Et {
+				return res
+			}
+		}
+
+		return Et
+	}
+}
+
+// By 按照field取出的字段进行比较
+func By[T any, F cmp.Ordered](field func(T) F) func(a, b T) int {
+	return func(a, b T) int {
+		return cmp.Compare(field(a), field(b))
+	}
+}
+
+// sign 将比较结果规范为Lt、Et、Gt
+func sign(res int) int {
+	switch {
+	case res < 0:
+		return Lt
+	case res > 0:
+		return Gt
+	default:
+		return Et
+	}
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_Comparators(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		comparator Comparator
+		k1, k2     interface{}
+		want       int
+	}{
+		{IntComparator, 1, 2, Lt},
+		{Int8Comparator, int8(-1), int8(-2), Gt},
+		{Int16Comparator, int16(3), int16(3), Et},
+		{Int32Comparator, int32(math.MinInt32), int32(math.MaxInt32), Lt},
+		{Int64Comparator, int64(math.MaxInt64), int64(math.MinInt64), Gt},
+		{UintComparator, uint(0), uint(1), Lt},
+		{Uint8Comparator, uint8(255), uint8(0), Gt},
+		{Uint16Comparator, uint16(7), uint16(7), Et},
+		{Uint32Comparator, uint32(1), uint32(math.MaxUint32), Lt},
+		{Uint64Comparator, uint64(math.MaxUint64), uint64(0), Gt},
+		{UintptrComparator, uintptr(1), uintptr(2), Lt},
+		{Float32Comparator, float32(1.5), float32(-1.5), Gt},
+		{Float64Comparator, 0.1, 0.2, Lt},
+		{StringComparator, "abc", "abd", Lt},
+		{BytesComparator, []byte("b"), []byte("a"), Gt},
+		{BytesComparator, []byte(nil), []byte{}, Et},
+		{TimeComparator, now, now.Add(time.Second), Lt},
+		{ComparatorFunc[int](func(a, b int) int { return a - b }), 10, 3, Gt},
+	}
+
+	for i, c := range cases {
+		if got := c.comparator.Compare(c.k1, c.k2); got != c.want {
+			t.Fatalf("case %v: want %v, got %v\n", i, c.want, got)
+		}
+	}
+}
+
+func Test_CompareFloatNaN(t *testing.T) {
+	nan := math.NaN()
+	arr := []float64{3, nan, math.Inf(1), -1, nan, math.Inf(-1), 0}
+	slices.SortFunc(arr, CompareFloat[float64])
+
+	// NaN排在最前面
+	if !math.IsNaN(arr[0]) || !math.IsNaN(arr[1]) {
+		t.Fatalf("want NaN first, got %v\n", arr)
+	}
+
+	for i := 3; i < len(arr); i++ {
+		if arr[i-1] > arr[i] {
+			t.Fatalf("not sorted %v\n", arr)
+		}
+	}
+
+	if CompareFloat(nan, nan) != Et {
+		t.Fatalf("want NaN equal NaN\n")
+	}
+
+	if CompareFloat(nan, math.Inf(-1)) != Lt {
+		t.Fatalf("want NaN less than -Inf\n")
+	}
+}
+
+type user struct {
+	name string
+	age  int
+}
+
+func Test_ReverseComposite(t *testing.T) {
+	users := []user{{"b", 1}, {"a", 1}, {"a", 3}, {"b", 2}, {"a", 2}}
+
+	// 先按name升序，再按age降序
+	slices.SortFunc(users, Composite(
+		By(func(u user) string { return u.name }),
+		Reverse(By(func(u user) int { return u.age })),
+	))
+
+	want := []user{{"a", 3}, {"a", 2}, {"a", 1}, {"b", 2}, {"b", 1}}
+	if !slices.Equal(users, want) {
+		t.Fatalf("want %v, got %v\n", want, users)
+	}
+
+	if Composite[int]()(1, 2) != Et {
+		t.Fatalf("empty Composite want Et\n")
+	}
+
+	if Reverse(func(a, b int) int { return a - b })(1, 5) != Gt {
+		t.Fatalf("Reverse want Gt\n")
+	}
+}
@@ -1,14 +1,22 @@
 package utils
 
-// Entry Entry
-type Entry struct {
-	key   interface{}
-	value interface{}
+// Entry key、value为interface{}的entry
+type Entry = TypedEntry[interface{}, interface{}]
+
+// TypedEntry 指定key、value类型的entry
+type TypedEntry[K, V any] struct {
+	key   K
+	value V
 }
 
 // NewEntry NewEntry
 func NewEntry(key, val interface{}) *Entry {
-	entry := &Entry{}
+	return NewTypedEntry(key, val)
+}
+
+// NewTypedEntry 创建一个指定key、value类型的entry
+func NewTypedEntry[K, V any](key K, val V) *TypedEntry[K, V] {
+	entry := &TypedEntry[K, V]{}
 	entry.key = key
 	entry.value = val
 
@@ -16,21 +24,21 @@ func NewEntry(key, val interface{}) *Entry {
 }
 
 // GetKey 获取key
-func (e *Entry) GetKey() interface{} {
+func (e *TypedEntry[K, V]) GetKey() K {
 	return e.key
 }
 
 // SetKey 设置key
-func (e *Entry) SetKey(key interface{}) {
+func (e *TypedEntry[K, V]) SetKey(key K) {
 	e.key = key
 }
 
 // GetValue 获取value
-func (e *Entry) GetValue() interface{} {
+func (e *TypedEntry[K, V]) GetValue() V {
 	return e.value
 }
 
 // SetValue 设置value
-func (e *Entry) SetValue(val interface{}) {
+func (e *TypedEntry[K, V]) SetValue(val V) {
 	e.value = val
 }